@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"peekaping/src/modules/shared"
+	"time"
+)
+
+// client talks to the agent API of the peekaping server
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+type apiResponse[T any] struct {
+	Message string `json:"message"`
+	Data    T      `json:"data"`
+}
+
+func newClient(baseURL string, token string) *client {
+	return &client{
+		baseURL: baseURL,
+		token:   token,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// FetchMonitors returns the active monitors assigned to this agent
+func (c *client) FetchMonitors(ctx context.Context) ([]*shared.AgentMonitor, error) {
+	var response apiResponse[[]*shared.AgentMonitor]
+	if err := c.do(ctx, http.MethodGet, "/api/v1/agent/monitors", nil, &response); err != nil {
+		return nil, err
+	}
+	return response.Data, nil
+}
+
+// PushResults reports check results back to the server
+func (c *client) PushResults(ctx context.Context, results []*shared.AgentResult) error {
+	body := &shared.AgentResultsDto{Results: results}
+	return c.do(ctx, http.MethodPost, "/api/v1/agent/results", body, nil)
+}
+
+func (c *client) do(ctx context.Context, method string, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(shared.AgentTokenHeader, c.token)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var failure apiResponse[any]
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&failure)
+		return fmt.Errorf("%s %s: unexpected status %d: %s", method, path, resp.StatusCode, failure.Message)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/version"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/urfave/cli/v2"
+	"go.uber.org/zap"
+)
+
+func main() {
+	app := &cli.App{
+		Name:    "peekaping-agent",
+		Usage:   "run peekaping checks from a remote location",
+		Version: version.Version,
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "server",
+				Usage:    "base URL of the peekaping server, e.g. https://peekaping.example.com",
+				EnvVars:  []string{"PEEKAPING_SERVER_URL"},
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "token",
+				Usage:    "agent token issued by the server",
+				EnvVars:  []string{"PEEKAPING_AGENT_TOKEN"},
+				Required: true,
+			},
+			&cli.DurationFlag{
+				Name:    "sync-interval",
+				Usage:   "how often assigned monitors are fetched from the server",
+				EnvVars: []string{"PEEKAPING_AGENT_SYNC_INTERVAL"},
+				Value:   30 * time.Second,
+			},
+			&cli.StringFlag{
+				Name:    "plugins-dir",
+				Usage:   "directory of plugin executables that add monitor types, must match the server's plugins",
+				EnvVars: []string{"PEEKAPING_AGENT_PLUGINS_DIR"},
+			},
+			&cli.StringFlag{
+				Name:    "command-allowlist",
+				Usage:   "comma separated executables and directories command monitors may run",
+				EnvVars: []string{"PEEKAPING_AGENT_COMMAND_ALLOWLIST"},
+			},
+			&cli.StringFlag{
+				Name:    "log-level",
+				Usage:   "debug, info, warn or error",
+				EnvVars: []string{"LOG_LEVEL"},
+				Value:   "info",
+			},
+		},
+		Action: run,
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func run(c *cli.Context) error {
+	zapConfig := zap.NewProductionConfig()
+	if err := zapConfig.Level.UnmarshalText([]byte(c.String("log-level"))); err != nil {
+		return err
+	}
+	base, err := zapConfig.Build()
+	if err != nil {
+		return err
+	}
+	defer base.Sync()
+	logger := base.Sugar().Named("[agent]")
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := newClient(strings.TrimRight(c.String("server"), "/"), c.String("token"))
+	// Push monitors are reported by the monitored service itself, so no heartbeat service is needed here
+	registry := executor.NewExecutorRegistry(logger, nil, nil)
+	registry.RegisterExecutor("command", executor.NewCommandExecutor(logger, c.String("command-allowlist")))
+	if err := registry.LoadPlugins(c.String("plugins-dir")); err != nil {
+		return err
+	}
+	runner := newRunner(client, registry, logger)
+
+	logger.Infof("Starting peekaping agent %s against %s", version.Version, c.String("server"))
+	runner.Run(ctx, c.Duration("sync-interval"))
+	logger.Info("Agent stopped")
+
+	return nil
+}
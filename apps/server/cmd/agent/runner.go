@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/shared"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// maxPendingResults bounds the results kept in memory while the server is unreachable
+const maxPendingResults = 1000
+
+// runner keeps one check loop per assigned monitor and forwards the results to the server
+type runner struct {
+	client   *client
+	registry *executor.ExecutorRegistry
+	logger   *zap.SugaredLogger
+
+	mu      sync.Mutex
+	active  map[string]*check
+	results chan *shared.AgentResult
+}
+
+type check struct {
+	cancel    context.CancelFunc
+	done      chan struct{}
+	updatedAt time.Time
+	proxyID   string
+}
+
+func newRunner(client *client, registry *executor.ExecutorRegistry, logger *zap.SugaredLogger) *runner {
+	return &runner{
+		client:   client,
+		registry: registry,
+		logger:   logger,
+		active:   make(map[string]*check),
+		results:  make(chan *shared.AgentResult, maxPendingResults),
+	}
+}
+
+// Run syncs the assigned monitors every syncInterval until ctx is cancelled
+func (r *runner) Run(ctx context.Context, syncInterval time.Duration) {
+	senderDone := make(chan struct{})
+	go func() {
+		defer close(senderDone)
+		r.sendResults(ctx)
+	}()
+
+	r.sync(ctx)
+
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.sync(ctx)
+		case <-ctx.Done():
+			r.stopAll()
+			<-senderDone
+			return
+		}
+	}
+}
+
+// sync starts checks for new monitors, restarts changed ones and stops unassigned ones
+func (r *runner) sync(ctx context.Context) {
+	assigned, err := r.client.FetchMonitors(ctx)
+	if err != nil {
+		r.logger.Errorf("Failed to fetch assigned monitors: %v", err)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(assigned))
+	for _, item := range assigned {
+		m := item.Monitor
+		seen[m.ID] = true
+
+		proxyID := ""
+		if item.Proxy != nil {
+			proxyID = item.Proxy.ID
+		}
+
+		if c, ok := r.active[m.ID]; ok {
+			if c.updatedAt.Equal(m.UpdatedAt) && c.proxyID == proxyID {
+				continue
+			}
+			c.cancel()
+			<-c.done
+		}
+
+		exec, ok := r.registry.GetExecutor(m.Type)
+		if !ok {
+			r.logger.Warnf("Monitor type %s of monitor %s is not supported by this agent", m.Type, m.Name)
+			delete(r.active, m.ID)
+			continue
+		}
+
+		r.logger.Infof("Starting checks for monitor %s (%s)", m.Name, m.Type)
+		r.active[m.ID] = r.start(ctx, m, item.Proxy, exec)
+	}
+
+	for id, c := range r.active {
+		if !seen[id] {
+			r.logger.Infof("Stopping checks for monitor %s", id)
+			c.cancel()
+			<-c.done
+			delete(r.active, id)
+		}
+	}
+}
+
+func (r *runner) start(ctx context.Context, m *shared.Monitor, proxy *shared.Proxy, exec executor.Executor) *check {
+	ctx, cancel := context.WithCancel(ctx)
+	c := &check{
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		updatedAt: m.UpdatedAt,
+	}
+	if proxy != nil {
+		c.proxyID = proxy.ID
+	}
+
+	go func() {
+		defer close(c.done)
+
+		for {
+			result := r.execute(ctx, m, proxy, exec)
+			if result == nil {
+				return
+			}
+
+			select {
+			case r.results <- result:
+			default:
+				r.logger.Warnf("Result buffer is full, dropping result of monitor %s", m.Name)
+			}
+
+			// Retry sooner while the monitor is failing, like the server does
+			interval := time.Duration(m.Interval) * time.Second
+			if result.Status == shared.MonitorStatusDown && m.RetryInterval > 0 {
+				interval = time.Duration(m.RetryInterval) * time.Second
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return c
+}
+
+func (r *runner) execute(ctx context.Context, m *shared.Monitor, proxy *shared.Proxy, exec executor.Executor) *shared.AgentResult {
+	callCtx, cancel := context.WithTimeout(ctx, time.Duration(m.Timeout)*time.Second)
+	defer cancel()
+
+	result := exec.Execute(callCtx, m, proxy)
+	if result == nil || ctx.Err() != nil {
+		return nil
+	}
+
+	return &shared.AgentResult{
+		MonitorID:  m.ID,
+		Status:     result.Status,
+		Message:    result.Message,
+		StartTime:  result.StartTime,
+		EndTime:    result.EndTime,
+		TLSInfo:    result.TLSInfo,
+		DomainInfo: result.DomainInfo,
+		Metadata:   result.Metadata,
+	}
+}
+
+// sendResults batches results and pushes them to the server, keeping them for the next
+// attempt when the server cannot be reached
+func (r *runner) sendResults(ctx context.Context) {
+	var pending []*shared.AgentResult
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case result := <-r.results:
+			pending = append(pending, result)
+		case <-ticker.C:
+			if len(pending) == 0 {
+				continue
+			}
+			if err := r.client.PushResults(ctx, pending); err != nil {
+				r.logger.Errorf("Failed to push %d results: %v", len(pending), err)
+				if len(pending) > maxPendingResults {
+					pending = pending[len(pending)-maxPendingResults:]
+				}
+				continue
+			}
+			r.logger.Debugf("Pushed %d results", len(pending))
+			pending = nil
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (r *runner) stopAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, c := range r.active {
+		c.cancel()
+		<-c.done
+		delete(r.active, id)
+	}
+}
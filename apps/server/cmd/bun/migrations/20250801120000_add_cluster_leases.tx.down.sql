@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS monitor_leases;
+DROP TABLE IF EXISTS cluster_nodes;
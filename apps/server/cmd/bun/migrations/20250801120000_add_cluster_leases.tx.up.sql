@@ -0,0 +1,20 @@
+-- Add cluster coordination tables
+-- Nodes register themselves and hold renewable leases on monitor shards
+-- so that several server replicas never run the same monitor twice
+
+-- Registry of running server replicas
+CREATE TABLE IF NOT EXISTS cluster_nodes (
+    id VARCHAR(255) PRIMARY KEY,
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_cluster_nodes_last_seen_at ON cluster_nodes(last_seen_at);
+
+-- Shard leases, one row per shard currently owned by a node
+CREATE TABLE IF NOT EXISTS monitor_leases (
+    shard INTEGER PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,7 @@
+-- Remove remote probe agents
+
+ALTER TABLE heartbeats DROP COLUMN location;
+
+-- Drop junction table first (has foreign key constraints)
+DROP TABLE IF EXISTS monitor_agents;
+DROP TABLE IF EXISTS agents;
@@ -0,0 +1,33 @@
+-- Add remote probe agents
+-- Agents run checks from other network locations and report results with their location
+-- Wrapped in a transaction for atomicity
+
+-- Agents table storing registered probes and their API tokens
+CREATE TABLE IF NOT EXISTS agents (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    location VARCHAR(100) NOT NULL,
+    token VARCHAR(64) UNIQUE NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    last_seen_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Monitor agents junction table for many-to-many relationship
+CREATE TABLE IF NOT EXISTS monitor_agents (
+    id UUID PRIMARY KEY,
+    monitor_id UUID NOT NULL,
+    agent_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE,
+    FOREIGN KEY (agent_id) REFERENCES agents(id) ON DELETE CASCADE,
+    UNIQUE(monitor_id, agent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_agents_monitor_id ON monitor_agents(monitor_id);
+CREATE INDEX IF NOT EXISTS idx_monitor_agents_agent_id ON monitor_agents(agent_id);
+
+-- Location the heartbeat was checked from
+ALTER TABLE heartbeats ADD COLUMN location VARCHAR(100);
@@ -0,0 +1,3 @@
+-- Remove monitor dependencies
+
+DROP TABLE IF EXISTS monitor_dependencies;
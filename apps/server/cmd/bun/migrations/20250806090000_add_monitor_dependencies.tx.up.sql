@@ -0,0 +1,18 @@
+-- Add monitor dependencies
+-- Children of a DOWN parent are recorded as dependency down without notifications
+-- Wrapped in a transaction for atomicity
+
+-- Monitor dependencies junction table linking monitors to their parent monitors
+CREATE TABLE IF NOT EXISTS monitor_dependencies (
+    id UUID PRIMARY KEY,
+    monitor_id UUID NOT NULL,
+    parent_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE,
+    FOREIGN KEY (parent_id) REFERENCES monitors(id) ON DELETE CASCADE,
+    UNIQUE(monitor_id, parent_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_dependencies_monitor_id ON monitor_dependencies(monitor_id);
+CREATE INDEX IF NOT EXISTS idx_monitor_dependencies_parent_id ON monitor_dependencies(parent_id);
@@ -0,0 +1,5 @@
+-- Remove degraded status
+
+ALTER TABLE stats DROP COLUMN degraded;
+ALTER TABLE monitors DROP COLUMN degraded_breaches;
+ALTER TABLE monitors DROP COLUMN degraded_threshold;
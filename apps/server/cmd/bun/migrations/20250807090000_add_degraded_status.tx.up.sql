@@ -0,0 +1,11 @@
+-- Add degraded status
+-- Successful checks slower than the threshold for N consecutive checks are recorded as DEGRADED
+-- Wrapped in a transaction for atomicity
+
+-- Response time threshold in milliseconds, 0 disables degraded detection
+ALTER TABLE monitors ADD COLUMN degraded_threshold INTEGER NOT NULL DEFAULT 0;
+-- Consecutive slow checks required before the monitor becomes degraded
+ALTER TABLE monitors ADD COLUMN degraded_breaches INTEGER NOT NULL DEFAULT 0;
+
+-- Degraded beats are counted separately in the aggregated stats
+ALTER TABLE stats ADD COLUMN degraded INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Remove heartbeat metadata
+
+ALTER TABLE heartbeats DROP COLUMN metadata;
@@ -0,0 +1,5 @@
+-- Add heartbeat metadata
+-- Structured check details reported by the executors, stored as a JSON string
+-- Wrapped in a transaction for atomicity
+
+ALTER TABLE heartbeats ADD COLUMN metadata TEXT;
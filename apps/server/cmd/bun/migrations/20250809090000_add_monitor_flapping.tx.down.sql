@@ -0,0 +1,6 @@
+-- Remove flapping detection
+
+DROP TABLE IF EXISTS monitor_flaps;
+
+ALTER TABLE monitors DROP COLUMN flap_window;
+ALTER TABLE monitors DROP COLUMN flap_threshold;
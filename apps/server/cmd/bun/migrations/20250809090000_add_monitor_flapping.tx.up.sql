@@ -0,0 +1,22 @@
+-- Add flapping detection
+-- Monitors changing status too often within a window of heartbeats are marked as flapping
+-- Wrapped in a transaction for atomicity
+
+-- Percentage of status changes within the window at which the monitor is flapping, 0 disables it
+ALTER TABLE monitors ADD COLUMN flap_threshold INTEGER NOT NULL DEFAULT 0;
+-- Number of recent heartbeats the status change rate is computed over, 0 uses the default
+ALTER TABLE monitors ADD COLUMN flap_window INTEGER NOT NULL DEFAULT 0;
+
+-- Flapping periods recorded for reporting, ended_at is NULL while the monitor is flapping
+CREATE TABLE IF NOT EXISTS monitor_flaps (
+    id UUID PRIMARY KEY,
+    monitor_id UUID NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    transitions INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitor_flaps_monitor_started ON monitor_flaps(monitor_id, started_at);
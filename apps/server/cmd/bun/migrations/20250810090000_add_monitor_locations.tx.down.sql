@@ -0,0 +1,3 @@
+-- Remove monitor locations
+
+DROP TABLE IF EXISTS monitor_locations;
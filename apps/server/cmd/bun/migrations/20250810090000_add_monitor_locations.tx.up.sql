@@ -0,0 +1,14 @@
+-- Add monitor locations
+-- Latest result of every location checking a monitor, shared by all replicas so the down
+-- quorum sees every location whichever replica received the agent result
+-- Wrapped in a transaction for atomicity
+
+CREATE TABLE IF NOT EXISTS monitor_locations (
+    monitor_id UUID NOT NULL,
+    location VARCHAR(100) NOT NULL,
+    status INTEGER NOT NULL,
+    message TEXT,
+    reported_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (monitor_id, location),
+    FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
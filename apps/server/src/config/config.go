@@ -51,6 +51,48 @@ type Config struct {
 	// During this period, all login attempts will be blocked with HTTP 429
 	// Examples: "5m", "30m", "1h", "24h"
 	BruteforceLockout time.Duration `env:"BRUTEFORCE_LOCKOUT" default:"1m"`
+
+	// Cluster settings
+	// Coordinate health checks between several server replicas through database leases
+	// When disabled, every replica runs all active monitors
+	ClusterEnabled bool `env:"CLUSTER_ENABLED" default:"false"`
+
+	// Unique identifier of this replica, generated from the hostname when empty
+	ClusterNodeID string `env:"CLUSTER_NODE_ID"`
+
+	// Number of shards monitors are spread across; every replica must use the same value
+	ClusterShards int `env:"CLUSTER_SHARDS" default:"64"`
+
+	// How long a shard lease stays valid without renewal
+	// A replica that stops renewing has its monitors taken over after this duration
+	// Examples: "15s", "30s", "1m"
+	ClusterLeaseTTL time.Duration `env:"CLUSTER_LEASE_TTL" default:"30s"`
+
+	// Remote probe agent settings
+	// Location reported on heartbeats produced by this server's own checks
+	ServerLocation string `env:"SERVER_LOCATION" default:"local"`
+
+	// Number of locations that must agree before a monitor is marked DOWN
+	// Capped at the number of locations that recently reported the monitor
+	AgentDownQuorum int `env:"AGENT_DOWN_QUORUM" default:"2"`
+
+	// Health check execution settings
+	// Maximum number of checks executed at the same time
+	HealthCheckWorkers int `env:"HEALTHCHECK_WORKERS" default:"100"`
+
+	// Number of due checks that may wait for a free worker, further ticks are dropped
+	HealthCheckQueueSize int `env:"HEALTHCHECK_QUEUE_SIZE" default:"1000"`
+
+	// Maximum number of concurrent checks against the same target host, 0 disables the limit
+	HealthCheckPerHostLimit int `env:"HEALTHCHECK_PER_HOST_LIMIT" default:"0"`
+
+	// Directory of plugin executables that add monitor types, plugins are disabled when empty
+	PluginsDir string `env:"PLUGINS_DIR"`
+
+	// Comma separated executables and directories command monitors may run, command monitors
+	// are disabled when empty
+	// Example: "/usr/lib/nagios/plugins,/opt/checks/check_license.sh"
+	CommandAllowlist string `env:"COMMAND_ALLOWLIST"`
 }
 
 var validate = validator.New()
@@ -7,17 +7,25 @@ import (
 	"os"
 	"peekaping/docs"
 	"peekaping/src/config"
+	"peekaping/src/modules/agent"
 	"peekaping/src/modules/auth"
 	"peekaping/src/modules/badge"
 	"peekaping/src/modules/bruteforce"
 	"peekaping/src/modules/certificate"
 	"peekaping/src/modules/cleanup"
+	"peekaping/src/modules/cluster"
+	"peekaping/src/modules/domain_expiry"
 	"peekaping/src/modules/domain_status_page"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/healthcheck"
+	"peekaping/src/modules/healthcheck/executor"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/maintenance"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_agent"
+	"peekaping/src/modules/monitor_dependency"
+	"peekaping/src/modules/monitor_flap"
+	"peekaping/src/modules/monitor_location"
 	"peekaping/src/modules/monitor_maintenance"
 	"peekaping/src/modules/monitor_notification"
 	"peekaping/src/modules/monitor_status_page"
@@ -83,6 +91,7 @@ func main() {
 	events.RegisterDependencies(container)
 	heartbeat.RegisterDependencies(container, &cfg)
 	monitor.RegisterDependencies(container, &cfg)
+	cluster.RegisterDependencies(container, &cfg)
 	healthcheck.RegisterDependencies(container)
 	bruteforce.RegisterDependencies(container, &cfg)
 	auth.RegisterDependencies(container, &cfg)
@@ -93,6 +102,7 @@ func main() {
 	notification_sent_history.RegisterDependencies(container, &cfg)
 	monitor_tls_info.RegisterDependencies(container, &cfg)
 	certificate.RegisterDependencies(container)
+	domain_expiry.RegisterDependencies(container)
 	stats.RegisterDependencies(container, &cfg)
 	monitor_maintenance.RegisterDependencies(container, &cfg)
 	maintenance.RegisterDependencies(container, &cfg)
@@ -102,6 +112,20 @@ func main() {
 	tag.RegisterDependencies(container, &cfg)
 	monitor_tag.RegisterDependencies(container, &cfg)
 	badge.RegisterDependencies(container, &cfg)
+	agent.RegisterDependencies(container, &cfg)
+	monitor_agent.RegisterDependencies(container, &cfg)
+	monitor_dependency.RegisterDependencies(container, &cfg)
+	monitor_flap.RegisterDependencies(container, &cfg)
+	monitor_location.RegisterDependencies(container, &cfg)
+
+	// Register the monitor types configured at startup and provided by plugins before any check runs
+	err = container.Invoke(func(registry *executor.ExecutorRegistry, logger *zap.SugaredLogger) error {
+		registry.RegisterExecutor("command", executor.NewCommandExecutor(logger, cfg.CommandAllowlist))
+		return registry.LoadPlugins(cfg.PluginsDir)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Start the event healthcheck listener
 	err = container.Invoke(func(listener *healthcheck.EventListener, eventBus *events.EventBus) {
@@ -161,6 +185,38 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Start the agent event listener
+	err = container.Invoke(func(listener *agent.EventListener, eventBus *events.EventBus) {
+		listener.Subscribe(eventBus)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the monitor dependency event listener
+	err = container.Invoke(func(listener *monitor_dependency.EventListener, eventBus *events.EventBus) {
+		listener.Subscribe(eventBus)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the monitor flap event listener
+	err = container.Invoke(func(listener *monitor_flap.EventListener, eventBus *events.EventBus) {
+		listener.Subscribe(eventBus)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the monitor location event listener
+	err = container.Invoke(func(listener *monitor_location.EventListener, eventBus *events.EventBus) {
+		listener.Subscribe(eventBus)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Start the server
 	err = container.Invoke(func(server *Server) {
 		docs.SwaggerInfo.Host = "localhost:" + server.cfg.Port
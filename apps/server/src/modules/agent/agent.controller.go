@@ -0,0 +1,253 @@
+package agent
+
+import (
+	"net/http"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type Controller struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+func NewController(
+	service Service,
+	logger *zap.SugaredLogger,
+) *Controller {
+	return &Controller{
+		service,
+		logger,
+	}
+}
+
+// @Router		/agents [get]
+// @Summary		Get agents
+// @Tags			Agents
+// @Produce		json
+// @Security  BearerAuth
+// @Param     q    query     string  false  "Search query"
+// @Param     page query     int     false  "Page number" default(1)
+// @Param     limit query    int     false  "Items per page" default(10)
+// @Success		200	{object}	utils.ApiResponse[[]Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) FindAll(ctx *gin.Context) {
+	page, err := utils.GetQueryInt(ctx, "page", 0)
+	if err != nil || page < 0 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid page parameter"))
+		return
+	}
+
+	limit, err := utils.GetQueryInt(ctx, "limit", 10)
+	if err != nil || limit < 1 {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid limit parameter"))
+		return
+	}
+
+	q := ctx.Query("q")
+
+	response, err := c.service.FindAll(ctx, page, limit, q)
+	if err != nil {
+		c.logger.Errorw("Failed to fetch agents", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
+}
+
+// @Router		/agents [post]
+// @Summary		Create agent
+// @Tags			Agents
+// @Produce		json
+// @Accept		json
+// @Security  BearerAuth
+// @Param     body body   CreateUpdateDto  true  "Agent object"
+// @Success		201	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) Create(ctx *gin.Context) {
+	var agent *CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&agent); err != nil {
+		c.logger.Errorw("Invalid request body", "error", err)
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+		return
+	}
+
+	if err := utils.Validate.Struct(agent); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	createdAgent, err := c.service.Create(ctx, agent)
+	if err != nil {
+		c.logger.Errorw("Failed to create agent", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, utils.NewSuccessResponse("Agent created successfully", createdAgent))
+}
+
+// @Router		/agents/{id} [get]
+// @Summary		Get agent by ID
+// @Tags			Agents
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Agent ID"
+// @Success		200	{object}	utils.ApiResponse[AgentWithMonitorsResponseDto]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) FindByID(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	agent, err := c.service.FindByIDWithMonitors(ctx, id)
+	if err != nil {
+		c.logger.Errorw("Failed to fetch agent", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if agent == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Agent not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", agent))
+}
+
+// @Router		/agents/{id} [put]
+// @Summary		Update agent
+// @Tags			Agents
+// @Produce		json
+// @Accept		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Agent ID"
+// @Param       agent body     CreateUpdateDto  true  "Agent object"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) UpdateFull(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var agent CreateUpdateDto
+	if err := ctx.ShouldBindJSON(&agent); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(agent); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	updatedAgent, err := c.service.UpdateFull(ctx, id, &agent)
+	if err != nil {
+		c.logger.Errorw("Failed to update agent", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if updatedAgent == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Agent not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Agent updated successfully", updatedAgent))
+}
+
+// @Router		/agents/{id} [patch]
+// @Summary		Update agent
+// @Tags			Agents
+// @Produce		json
+// @Accept		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Agent ID"
+// @Param       agent body     PartialUpdateDto  true  "Agent object"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) UpdatePartial(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var agent PartialUpdateDto
+	if err := ctx.ShouldBindJSON(&agent); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	if err := utils.Validate.Struct(agent); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	updatedAgent, err := c.service.UpdatePartial(ctx, id, &agent)
+	if err != nil {
+		c.logger.Errorw("Failed to update agent", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if updatedAgent == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Agent not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Agent updated successfully", updatedAgent))
+}
+
+// @Router		/agents/{id}/token [post]
+// @Summary		Regenerate agent token
+// @Description	Issues a new token for the agent, the previous token stops working immediately
+// @Tags			Agents
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Agent ID"
+// @Success		200	{object}	utils.ApiResponse[Model]
+// @Failure		404	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) RegenerateToken(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	updatedAgent, err := c.service.RegenerateToken(ctx, id)
+	if err != nil {
+		c.logger.Errorw("Failed to regenerate agent token", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	if updatedAgent == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Agent not found"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Agent token regenerated successfully", updatedAgent))
+}
+
+// @Router		/agents/{id} [delete]
+// @Summary		Delete agent
+// @Tags			Agents
+// @Produce		json
+// @Security BearerAuth
+// @Param       id   path      string  true  "Agent ID"
+// @Success		200	{object}	utils.ApiResponse[any]
+// @Failure		400	{object}	utils.APIError[any]
+// @Failure		500	{object}	utils.APIError[any]
+func (c *Controller) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	err := c.service.Delete(ctx, id)
+	if err != nil {
+		c.logger.Errorw("Failed to delete agent", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse[any]("Agent deleted successfully", nil))
+}
@@ -0,0 +1,29 @@
+package agent
+
+import "time"
+
+type CreateUpdateDto struct {
+	Name       string   `json:"name" validate:"required,min=1,max=100" example:"Frankfurt probe"`
+	Location   string   `json:"location" validate:"required,min=1,max=100" example:"eu-central"`
+	Active     bool     `json:"active" example:"true"`
+	MonitorIDs []string `json:"monitor_ids,omitempty"`
+}
+
+type PartialUpdateDto struct {
+	Name       *string   `json:"name,omitempty" validate:"omitempty,min=1,max=100" example:"Frankfurt probe"`
+	Location   *string   `json:"location,omitempty" validate:"omitempty,min=1,max=100" example:"eu-central"`
+	Active     *bool     `json:"active,omitempty" example:"true"`
+	MonitorIDs *[]string `json:"monitor_ids,omitempty"`
+}
+
+type AgentWithMonitorsResponseDto struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Location   string     `json:"location"`
+	Token      string     `json:"token"`
+	Active     bool       `json:"active"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	MonitorIDs []string   `json:"monitor_ids"`
+}
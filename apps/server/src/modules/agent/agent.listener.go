@@ -0,0 +1,46 @@
+package agent
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/monitor_agent"
+
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+// EventListener removes agent assignments of deleted monitors
+type EventListener struct {
+	monitorAgentService monitor_agent.Service
+	logger              *zap.SugaredLogger
+}
+
+type EventListenerParams struct {
+	dig.In
+	MonitorAgentService monitor_agent.Service
+	Logger              *zap.SugaredLogger
+}
+
+func NewEventListener(p EventListenerParams) *EventListener {
+	return &EventListener{
+		monitorAgentService: p.MonitorAgentService,
+		logger:              p.Logger.Named("[agent-event-listener]"),
+	}
+}
+
+// Subscribe subscribes to MonitorDeleted events
+func (l *EventListener) Subscribe(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.MonitorDeleted, l.handleMonitorDeleted)
+}
+
+func (l *EventListener) handleMonitorDeleted(event events.Event) {
+	monitorID, ok := event.Payload.(string)
+	if !ok {
+		l.logger.Errorf("Invalid handleMonitorDeleted event payload type: %v", event.Payload)
+		return
+	}
+
+	if err := l.monitorAgentService.DeleteByMonitorID(context.Background(), monitorID); err != nil {
+		l.logger.Errorf("Failed to delete agent assignments for monitor %s: %v", monitorID, err)
+	}
+}
@@ -0,0 +1,25 @@
+package agent
+
+import "time"
+
+type Model struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Location   string     `json:"location"`
+	Token      string     `json:"token"`
+	Active     bool       `json:"active"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+type UpdateModel struct {
+	ID         *string    `json:"id"`
+	Name       *string    `json:"name"`
+	Location   *string    `json:"location"`
+	Token      *string    `json:"token"`
+	Active     *bool      `json:"active"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	CreatedAt  *time.Time `json:"created_at"`
+	UpdatedAt  *time.Time `json:"updated_at"`
+}
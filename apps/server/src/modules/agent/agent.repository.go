@@ -0,0 +1,17 @@
+package agent
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, entity *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindByToken(ctx context.Context, token string) (*Model, error)
+	FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error)
+	UpdateFull(ctx context.Context, id string, entity *Model) error
+	UpdatePartial(ctx context.Context, id string, entity *UpdateModel) error
+	UpdateLastSeen(ctx context.Context, id string, lastSeenAt time.Time) error
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,253 @@
+package agent
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"peekaping/src/modules/monitor_agent"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindByIDWithMonitors(ctx context.Context, id string) (*AgentWithMonitorsResponseDto, error)
+	FindByToken(ctx context.Context, token string) (*Model, error)
+	FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error)
+	UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error)
+	UpdatePartial(ctx context.Context, id string, entity *PartialUpdateDto) (*Model, error)
+	RegenerateToken(ctx context.Context, id string) (*Model, error)
+	Delete(ctx context.Context, id string) error
+
+	GetMonitorIDs(ctx context.Context, id string) ([]string, error)
+	IsMonitorAssigned(ctx context.Context, id string, monitorID string) (bool, error)
+	MarkSeen(ctx context.Context, id string) error
+}
+
+type ServiceImpl struct {
+	repository          Repository
+	monitorAgentService monitor_agent.Service
+	logger              *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	monitorAgentService monitor_agent.Service,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		monitorAgentService,
+		logger.Named("[agent-service]"),
+	}
+}
+
+// Generate a cryptographically secure 32-byte (256-bit) agent token
+func generateToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Model, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	createModel := &Model{
+		Name:     entity.Name,
+		Location: entity.Location,
+		Token:    token,
+		Active:   entity.Active,
+	}
+
+	created, err := s.repository.Create(ctx, createModel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setMonitors(ctx, created.ID, entity.MonitorIDs); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) FindByIDWithMonitors(ctx context.Context, id string) (*AgentWithMonitorsResponseDto, error) {
+	model, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if model == nil {
+		return nil, nil
+	}
+
+	monitorIDs, err := s.GetMonitorIDs(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AgentWithMonitorsResponseDto{
+		ID:         model.ID,
+		Name:       model.Name,
+		Location:   model.Location,
+		Token:      model.Token,
+		Active:     model.Active,
+		LastSeenAt: model.LastSeenAt,
+		CreatedAt:  model.CreatedAt,
+		UpdatedAt:  model.UpdatedAt,
+		MonitorIDs: monitorIDs,
+	}, nil
+}
+
+func (s *ServiceImpl) FindByToken(ctx context.Context, token string) (*Model, error) {
+	return s.repository.FindByToken(ctx, token)
+}
+
+func (s *ServiceImpl) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
+	return s.repository.FindAll(ctx, page, limit, q)
+}
+
+func (s *ServiceImpl) UpdateFull(ctx context.Context, id string, entity *CreateUpdateDto) (*Model, error) {
+	updateModel := &Model{
+		ID:       id,
+		Name:     entity.Name,
+		Location: entity.Location,
+		Active:   entity.Active,
+	}
+
+	err := s.repository.UpdateFull(ctx, id, updateModel)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.setMonitors(ctx, id, entity.MonitorIDs); err != nil {
+		return nil, err
+	}
+
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) UpdatePartial(ctx context.Context, id string, entity *PartialUpdateDto) (*Model, error) {
+	updateModel := &UpdateModel{
+		ID:       &id,
+		Name:     entity.Name,
+		Location: entity.Location,
+		Active:   entity.Active,
+	}
+
+	err := s.repository.UpdatePartial(ctx, id, updateModel)
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.MonitorIDs != nil {
+		if err := s.setMonitors(ctx, id, *entity.MonitorIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) RegenerateToken(ctx context.Context, id string) (*Model, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repository.UpdatePartial(ctx, id, &UpdateModel{Token: &token})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	// Delete monitor_agent relations first
+	err := s.monitorAgentService.DeleteByAgentID(ctx, id)
+	if err != nil {
+		s.logger.Warnw("Failed to delete monitor-agent relations", "agentID", id, "error", err)
+	}
+
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *ServiceImpl) GetMonitorIDs(ctx context.Context, id string) ([]string, error) {
+	relations, err := s.monitorAgentService.FindByAgentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	monitorIDs := make([]string, 0, len(relations))
+	for _, relation := range relations {
+		monitorIDs = append(monitorIDs, relation.MonitorID)
+	}
+	return monitorIDs, nil
+}
+
+func (s *ServiceImpl) IsMonitorAssigned(ctx context.Context, id string, monitorID string) (bool, error) {
+	monitorIDs, err := s.GetMonitorIDs(ctx, id)
+	if err != nil {
+		return false, err
+	}
+
+	for _, assigned := range monitorIDs {
+		if assigned == monitorID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *ServiceImpl) MarkSeen(ctx context.Context, id string) error {
+	return s.repository.UpdateLastSeen(ctx, id, time.Now().UTC())
+}
+
+// setMonitors replaces the monitors assigned to the agent with the given list
+func (s *ServiceImpl) setMonitors(ctx context.Context, id string, monitorIDs []string) error {
+	current, err := s.GetMonitorIDs(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	currentMonitorIDs := make(map[string]bool)
+	for _, monitorID := range current {
+		currentMonitorIDs[monitorID] = true
+	}
+
+	newMonitorIDs := make(map[string]bool)
+	for _, monitorID := range monitorIDs {
+		newMonitorIDs[monitorID] = true
+	}
+
+	// Remove monitors that are no longer assigned
+	for monitorID := range currentMonitorIDs {
+		if !newMonitorIDs[monitorID] {
+			if err := s.monitorAgentService.DeleteByMonitorAndAgent(ctx, monitorID, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Add newly assigned monitors
+	for monitorID := range newMonitorIDs {
+		if !currentMonitorIDs[monitorID] {
+			if _, err := s.monitorAgentService.Create(ctx, monitorID, id); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package agent
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:agents,alias:a"`
+
+	ID         string     `bun:"id,pk"`
+	Name       string     `bun:"name,notnull"`
+	Location   string     `bun:"location,notnull"`
+	Token      string     `bun:"token,notnull,unique"`
+	Active     bool       `bun:"active,notnull,default:true"`
+	LastSeenAt *time.Time `bun:"last_seen_at"`
+	CreatedAt  time.Time  `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt  time.Time  `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		ID:         sm.ID,
+		Name:       sm.Name,
+		Location:   sm.Location,
+		Token:      sm.Token,
+		Active:     sm.Active,
+		LastSeenAt: sm.LastSeenAt,
+		CreatedAt:  sm.CreatedAt,
+		UpdatedAt:  sm.UpdatedAt,
+	}
+}
+
+func toSQLModel(m *Model) *sqlModel {
+	return &sqlModel{
+		ID:         m.ID,
+		Name:       m.Name,
+		Location:   m.Location,
+		Token:      m.Token,
+		Active:     m.Active,
+		LastSeenAt: m.LastSeenAt,
+		CreatedAt:  m.CreatedAt,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, error) {
+	sm := toSQLModel(entity)
+	sm.ID = uuid.New().String()
+	sm.CreatedAt = time.Now()
+	sm.UpdatedAt = time.Now()
+
+	_, err := r.db.NewInsert().Model(sm).Returning("*").Exec(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("id = ?", id).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindByToken(ctx context.Context, token string) (*Model, error) {
+	sm := new(sqlModel)
+	err := r.db.NewSelect().Model(sm).Where("token = ?", token).Scan(ctx)
+	if err != nil {
+		if err.Error() == "sql: no rows in result set" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromSQL(sm), nil
+}
+
+func (r *SQLRepositoryImpl) FindAll(ctx context.Context, page int, limit int, q string) ([]*Model, error) {
+	query := r.db.NewSelect().Model((*sqlModel)(nil))
+
+	if q != "" {
+		query = query.Where("LOWER(name) LIKE ? OR LOWER(location) LIKE ?", "%"+q+"%", "%"+q+"%")
+	}
+
+	query = query.Order("name ASC").
+		Limit(limit).
+		Offset(page * limit)
+
+	var sms []*sqlModel
+	err := query.Scan(ctx, &sms)
+	if err != nil {
+		return nil, err
+	}
+
+	var models []*Model
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) UpdateFull(ctx context.Context, id string, entity *Model) error {
+	sm := toSQLModel(entity)
+	sm.UpdatedAt = time.Now()
+
+	_, err := r.db.NewUpdate().
+		Model(sm).
+		Where("id = ?", id).
+		ExcludeColumn("id", "token", "last_seen_at", "created_at").
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) UpdatePartial(ctx context.Context, id string, entity *UpdateModel) error {
+	query := r.db.NewUpdate().Model((*sqlModel)(nil)).Where("id = ?", id)
+
+	hasUpdates := false
+
+	if entity.Name != nil {
+		query = query.Set("name = ?", *entity.Name)
+		hasUpdates = true
+	}
+	if entity.Location != nil {
+		query = query.Set("location = ?", *entity.Location)
+		hasUpdates = true
+	}
+	if entity.Token != nil {
+		query = query.Set("token = ?", *entity.Token)
+		hasUpdates = true
+	}
+	if entity.Active != nil {
+		query = query.Set("active = ?", *entity.Active)
+		hasUpdates = true
+	}
+
+	if !hasUpdates {
+		return nil
+	}
+
+	// Always set updated_at
+	query = query.Set("updated_at = ?", time.Now())
+
+	_, err := query.Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) UpdateLastSeen(ctx context.Context, id string, lastSeenAt time.Time) error {
+	_, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("last_seen_at = ?", lastSeenAt).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) Delete(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().Model((*sqlModel)(nil)).Where("id = ?", id).Exec(ctx)
+	return err
+}
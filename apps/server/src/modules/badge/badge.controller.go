@@ -55,6 +55,12 @@ func (c *Controller) parseQueryOptions(ctx *gin.Context) *BadgeOptions {
 	if downColor := ctx.Query("downColor"); downColor != "" {
 		options.DownColor = downColor
 	}
+	if degradedLabel := ctx.Query("degradedLabel"); degradedLabel != "" {
+		options.DegradedLabel = degradedLabel
+	}
+	if degradedColor := ctx.Query("degradedColor"); degradedColor != "" {
+		options.DegradedColor = degradedColor
+	}
 
 	// Parse text customization options
 	if labelPrefix := ctx.Query("labelPrefix"); labelPrefix != "" {
@@ -98,6 +104,8 @@ func (c *Controller) parseQueryOptions(ctx *gin.Context) *BadgeOptions {
 // @Param			downLabel	query	string	false	"Label when monitor is down"
 // @Param			upColor		query	string	false	"Color when monitor is up"
 // @Param			downColor	query	string	false	"Color when monitor is down"
+// @Param			degradedLabel	query	string	false	"Label when monitor is degraded"
+// @Param			degradedColor	query	string	false	"Color when monitor is degraded"
 // @Success		200	{string}	string	"SVG badge"
 // @Failure		400	{object}	utils.APIError[any]
 // @Failure		404	{object}	utils.APIError[any]
@@ -51,6 +51,9 @@ type BadgeOptions struct {
 	UpColor   string `json:"up_color"`
 	DownColor string `json:"down_color"`
 
+	DegradedLabel string `json:"degraded_label"`
+	DegradedColor string `json:"degraded_color"`
+
 	// Text customization options
 	LabelPrefix string `json:"label_prefix"`
 	Label       string `json:"label"`
@@ -66,17 +69,19 @@ type BadgeOptions struct {
 // DefaultBadgeOptions returns default badge options
 func DefaultBadgeOptions() *BadgeOptions {
 	return &BadgeOptions{
-		Style:      BadgeStyleFlat,
-		Color:      "#007ec6", // Modern blue for general use
-		LabelColor: "#555",
-		UpLabel:    "Up",
-		DownLabel:  "Down",
-		UpColor:    "#4c1",    // Green for up status
-		DownColor:  "#e05d44", // Red for down status
-		Label:      "",
-		Suffix:     "",
-		WarnDays:   14,
-		DownDays:   7,
+		Style:         BadgeStyleFlat,
+		Color:         "#007ec6", // Modern blue for general use
+		LabelColor:    "#555",
+		UpLabel:       "Up",
+		DownLabel:     "Down",
+		UpColor:       "#4c1",    // Green for up status
+		DownColor:     "#e05d44", // Red for down status
+		DegradedLabel: "Degraded",
+		DegradedColor: "#dfb317", // Yellow for degraded status
+		Label:         "",
+		Suffix:        "",
+		WarnDays:      14,
+		DownDays:      7,
 	}
 }
 
@@ -84,7 +89,7 @@ func DefaultBadgeOptions() *BadgeOptions {
 type MonitorBadgeData struct {
 	ID     string `json:"id"`
 	Name   string `json:"name"`
-	Status int    `json:"status"` // 0=down, 1=up, 2=pending, 3=maintenance
+	Status int    `json:"status"` // 0=down, 1=up, 2=pending, 3=maintenance, 4=degraded
 	Active bool   `json:"active"`
 
 	// Statistics
@@ -116,6 +121,8 @@ func (m *MonitorBadgeData) GetStatusText(options *BadgeOptions) string {
 		return "Pending"
 	case 3:
 		return "Maintenance"
+	case 4:
+		return options.DegradedLabel
 	default:
 		return "Unknown"
 	}
@@ -136,6 +143,8 @@ func (m *MonitorBadgeData) GetStatusColor(options *BadgeOptions) string {
 		return "#fe7d37"
 	case 3:
 		return "#7c69ef"
+	case 4:
+		return options.DegradedColor
 	default:
 		return "#9f9f9f"
 	}
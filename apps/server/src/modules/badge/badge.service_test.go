@@ -1409,6 +1409,14 @@ func TestMonitorBadgeData_GetStatusText(t *testing.T) {
 			},
 			expected: "Maintenance",
 		},
+		{
+			name: "degraded monitor",
+			data: &MonitorBadgeData{
+				Status: int(shared.MonitorStatusDegraded),
+				Active: true,
+			},
+			expected: options.DegradedLabel,
+		},
 		{
 			name: "inactive monitor",
 			data: &MonitorBadgeData{
@@ -1475,6 +1483,14 @@ func TestMonitorBadgeData_GetStatusColor(t *testing.T) {
 			},
 			expected: "#7c69ef",
 		},
+		{
+			name: "degraded monitor",
+			data: &MonitorBadgeData{
+				Status: int(shared.MonitorStatusDegraded),
+				Active: true,
+			},
+			expected: options.DegradedColor,
+		},
 		{
 			name: "inactive monitor",
 			data: &MonitorBadgeData{
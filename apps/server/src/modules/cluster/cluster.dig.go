@@ -0,0 +1,22 @@
+package cluster
+
+import (
+	"peekaping/src/config"
+	"peekaping/src/utils"
+
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+func RegisterDependencies(container *dig.Container, cfg *config.Config) {
+	utils.RegisterRepositoryByDBType(container, cfg, NewSQLRepository, NewMongoRepository)
+
+	container.Provide(func(repository Repository, logger *zap.SugaredLogger) Service {
+		return NewService(repository, Options{
+			Enabled:  cfg.ClusterEnabled,
+			NodeID:   cfg.ClusterNodeID,
+			Shards:   cfg.ClusterShards,
+			LeaseTTL: cfg.ClusterLeaseTTL,
+		}, logger)
+	})
+}
@@ -0,0 +1,18 @@
+package cluster
+
+import "time"
+
+// Node represents a running server instance taking part in health check scheduling
+type Node struct {
+	ID         string
+	StartedAt  time.Time
+	LastSeenAt time.Time
+}
+
+// Lease represents ownership of a monitor shard by a node until ExpiresAt
+type Lease struct {
+	Shard     int
+	Owner     string
+	ExpiresAt time.Time
+	UpdatedAt time.Time
+}
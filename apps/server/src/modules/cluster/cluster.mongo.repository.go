@@ -0,0 +1,168 @@
+package cluster
+
+import (
+	"context"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type nodeMongoModel struct {
+	ID         string    `bson:"_id"`
+	StartedAt  time.Time `bson:"started_at"`
+	LastSeenAt time.Time `bson:"last_seen_at"`
+}
+
+type leaseMongoModel struct {
+	Shard     int       `bson:"_id"` // Use shard number as the document ID for uniqueness
+	Owner     string    `bson:"owner"`
+	ExpiresAt time.Time `bson:"expires_at"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func toNodeFromMongo(mm *nodeMongoModel) *Node {
+	return &Node{
+		ID:         mm.ID,
+		StartedAt:  mm.StartedAt,
+		LastSeenAt: mm.LastSeenAt,
+	}
+}
+
+func toLeaseFromMongo(mm *leaseMongoModel) *Lease {
+	return &Lease{
+		Shard:     mm.Shard,
+		Owner:     mm.Owner,
+		ExpiresAt: mm.ExpiresAt,
+		UpdatedAt: mm.UpdatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client          *mongo.Client
+	db              *mongo.Database
+	nodeCollection  *mongo.Collection
+	leaseCollection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+
+	repo := &MongoRepositoryImpl{
+		client:          client,
+		db:              db,
+		nodeCollection:  db.Collection("cluster_nodes"),
+		leaseCollection: db.Collection("monitor_leases"),
+	}
+
+	repo.nodeCollection.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "last_seen_at", Value: 1}},
+	})
+
+	return repo
+}
+
+func (r *MongoRepositoryImpl) UpsertNode(ctx context.Context, node *Node) error {
+	filter := bson.M{"_id": node.ID}
+	update := bson.M{
+		"$set": bson.M{
+			"last_seen_at": node.LastSeenAt,
+		},
+		"$setOnInsert": bson.M{
+			"started_at": node.StartedAt,
+		},
+	}
+
+	_, err := r.nodeCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepositoryImpl) FindNodesSeenSince(ctx context.Context, since time.Time) ([]*Node, error) {
+	filter := bson.M{"last_seen_at": bson.M{"$gte": since}}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := r.nodeCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var nodes []*Node
+	for cursor.Next(ctx) {
+		var mm nodeMongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, toNodeFromMongo(&mm))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return nodes, nil
+}
+
+func (r *MongoRepositoryImpl) DeleteNode(ctx context.Context, id string) error {
+	_, err := r.nodeCollection.DeleteOne(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *MongoRepositoryImpl) AcquireLease(ctx context.Context, shard int, owner string, now time.Time, expiresAt time.Time) (bool, error) {
+	// Only match the shard when it is ours or expired; the upsert then fails with a
+	// duplicate key error if another node holds a valid lease.
+	filter := bson.M{
+		"_id": shard,
+		"$or": bson.A{
+			bson.M{"owner": owner},
+			bson.M{"expires_at": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"owner":      owner,
+			"expires_at": expiresAt,
+			"updated_at": now,
+		},
+	}
+
+	_, err := r.leaseCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *MongoRepositoryImpl) ReleaseLease(ctx context.Context, shard int, owner string) error {
+	_, err := r.leaseCollection.DeleteOne(ctx, bson.M{"_id": shard, "owner": owner})
+	return err
+}
+
+func (r *MongoRepositoryImpl) FindLeases(ctx context.Context) ([]*Lease, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+
+	cursor, err := r.leaseCollection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var leases []*Lease
+	for cursor.Next(ctx) {
+		var mm leaseMongoModel
+		if err := cursor.Decode(&mm); err != nil {
+			return nil, err
+		}
+		leases = append(leases, toLeaseFromMongo(&mm))
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+	return leases, nil
+}
@@ -0,0 +1,27 @@
+package cluster
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// UpsertNode registers the node or refreshes its last_seen_at timestamp
+	UpsertNode(ctx context.Context, node *Node) error
+
+	// FindNodesSeenSince returns nodes that reported a heartbeat after the given time
+	FindNodesSeenSince(ctx context.Context, since time.Time) ([]*Node, error)
+
+	// DeleteNode removes a node from the registry
+	DeleteNode(ctx context.Context, id string) error
+
+	// AcquireLease takes or renews a shard lease. It succeeds when the shard is free,
+	// already owned by the given owner or when the current lease has expired.
+	AcquireLease(ctx context.Context, shard int, owner string, now time.Time, expiresAt time.Time) (bool, error)
+
+	// ReleaseLease gives up a shard lease held by the given owner
+	ReleaseLease(ctx context.Context, shard int, owner string) error
+
+	// FindLeases returns all shard leases
+	FindLeases(ctx context.Context) ([]*Lease, error)
+}
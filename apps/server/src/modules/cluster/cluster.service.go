@@ -0,0 +1,277 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	// Start registers this node and keeps its shard leases renewed until ctx is cancelled.
+	// onSync is called after every renewal, monitor changes made through other nodes are only
+	// seen in the database so the caller reconciles even when the owned shards stayed the same.
+	Start(ctx context.Context, onSync func())
+
+	// Stop releases all leases held by this node and removes it from the registry
+	Stop()
+
+	// Enabled reports whether multi-node coordination is turned on
+	Enabled() bool
+
+	// NodeID returns the identifier of this node
+	NodeID() string
+
+	// Owns reports whether this node is responsible for running the given monitor
+	Owns(monitorID string) bool
+}
+
+type Options struct {
+	Enabled  bool
+	NodeID   string
+	Shards   int
+	LeaseTTL time.Duration
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+	enabled    bool
+	nodeID     string
+	shards     int
+	leaseTTL   time.Duration
+	startedAt  time.Time
+
+	mu     sync.RWMutex
+	owned  map[int]time.Time // shard -> lease expiry
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewService(repository Repository, opts Options, logger *zap.SugaredLogger) Service {
+	nodeID := opts.NodeID
+	if nodeID == "" {
+		hostname, err := os.Hostname()
+		if err != nil || hostname == "" {
+			hostname = "node"
+		}
+		nodeID = fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+	}
+
+	shards := opts.Shards
+	if shards <= 0 {
+		shards = 64
+	}
+
+	leaseTTL := opts.LeaseTTL
+	if leaseTTL <= 0 {
+		leaseTTL = 30 * time.Second
+	}
+
+	return &ServiceImpl{
+		repository: repository,
+		logger:     logger.Named("[cluster-service]"),
+		enabled:    opts.Enabled,
+		nodeID:     nodeID,
+		shards:     shards,
+		leaseTTL:   leaseTTL,
+		startedAt:  time.Now().UTC(),
+		owned:      make(map[int]time.Time),
+	}
+}
+
+func (s *ServiceImpl) Enabled() bool {
+	return s.enabled
+}
+
+func (s *ServiceImpl) NodeID() string {
+	return s.nodeID
+}
+
+func (s *ServiceImpl) Owns(monitorID string) bool {
+	if !s.enabled {
+		return true
+	}
+
+	shard := shardFor(monitorID, s.shards)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	expiresAt, ok := s.owned[shard]
+	return ok && time.Now().UTC().Before(expiresAt)
+}
+
+func (s *ServiceImpl) Start(ctx context.Context, onSync func()) {
+	if !s.enabled {
+		return
+	}
+
+	s.logger.Infof("Starting cluster coordination as node %s (%d shards, lease ttl %s)", s.nodeID, s.shards, s.leaseTTL)
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	// Acquire the initial set of leases synchronously so the caller can schedule monitors right away
+	s.sync(ctx)
+
+	go func() {
+		defer close(s.done)
+		ticker := time.NewTicker(s.leaseTTL / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				s.sync(ctx)
+				if onSync != nil {
+					onSync()
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (s *ServiceImpl) Stop() {
+	if !s.enabled || s.cancel == nil {
+		return
+	}
+
+	s.cancel()
+	<-s.done
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for shard := range s.owned {
+		if err := s.repository.ReleaseLease(ctx, shard, s.nodeID); err != nil {
+			s.logger.Warnf("Failed to release lease for shard %d: %v", shard, err)
+		}
+		delete(s.owned, shard)
+	}
+
+	if err := s.repository.DeleteNode(ctx, s.nodeID); err != nil {
+		s.logger.Warnf("Failed to unregister node %s: %v", s.nodeID, err)
+	}
+}
+
+// sync refreshes node liveness, acquires or renews the shards assigned to this node
+// and releases the ones that moved to another node. It reports whether ownership changed.
+func (s *ServiceImpl) sync(ctx context.Context) bool {
+	now := time.Now().UTC()
+
+	err := s.repository.UpsertNode(ctx, &Node{
+		ID:         s.nodeID,
+		StartedAt:  s.startedAt,
+		LastSeenAt: now,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to refresh node %s: %v", s.nodeID, err)
+	}
+
+	nodeIDs := []string{s.nodeID}
+	nodes, err := s.repository.FindNodesSeenSince(ctx, now.Add(-s.leaseTTL))
+	if err != nil {
+		s.logger.Errorf("Failed to list live nodes: %v", err)
+	}
+	for _, n := range nodes {
+		if n.ID != s.nodeID {
+			nodeIDs = append(nodeIDs, n.ID)
+		}
+	}
+
+	expiresAt := now.Add(s.leaseTTL)
+	changed := false
+
+	// Work on a copy so Owns is not blocked by database round trips
+	s.mu.RLock()
+	owned := make(map[int]time.Time, len(s.owned))
+	for shard, exp := range s.owned {
+		owned[shard] = exp
+	}
+	s.mu.RUnlock()
+
+	for shard := 0; shard < s.shards; shard++ {
+		_, held := owned[shard]
+
+		if ownerFor(shard, nodeIDs) != s.nodeID {
+			if held {
+				if err := s.repository.ReleaseLease(ctx, shard, s.nodeID); err != nil {
+					s.logger.Warnf("Failed to release lease for shard %d: %v", shard, err)
+				}
+				delete(owned, shard)
+				changed = true
+			}
+			continue
+		}
+
+		acquired, err := s.repository.AcquireLease(ctx, shard, s.nodeID, now, expiresAt)
+		if err != nil {
+			// Keep the current expiry, the lease is dropped below once it runs out
+			s.logger.Errorf("Failed to acquire lease for shard %d: %v", shard, err)
+			continue
+		}
+
+		if acquired {
+			owned[shard] = expiresAt
+			if !held {
+				changed = true
+			}
+		} else if held {
+			delete(owned, shard)
+			changed = true
+		}
+	}
+
+	for shard, exp := range owned {
+		if !now.Before(exp) {
+			s.logger.Warnf("Lease for shard %d expired without renewal", shard)
+			delete(owned, shard)
+			changed = true
+		}
+	}
+
+	s.mu.Lock()
+	s.owned = owned
+	s.mu.Unlock()
+
+	if changed {
+		s.logger.Infof("Node %s now owns %d/%d shards (%d live nodes)", s.nodeID, len(owned), s.shards, len(nodeIDs))
+	}
+
+	return changed
+}
+
+// shardFor maps a monitor ID onto a shard number
+func shardFor(monitorID string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(monitorID))
+	return int(h.Sum32() % uint32(shards))
+}
+
+// ownerFor picks the node responsible for a shard using rendezvous hashing, so that
+// adding or removing a node only moves the shards that belonged to it
+func ownerFor(shard int, nodeIDs []string) string {
+	var owner string
+	var best uint64
+	for _, id := range nodeIDs {
+		h := fnv.New64a()
+		h.Write([]byte(id + ":" + strconv.Itoa(shard)))
+		score := h.Sum64()
+		if owner == "" || score > best || (score == best && id < owner) {
+			owner = id
+			best = score
+		}
+	}
+	return owner
+}
@@ -0,0 +1,181 @@
+package cluster
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// memoryRepository is an in-memory Repository shared by several nodes in tests
+type memoryRepository struct {
+	mu     sync.Mutex
+	nodes  map[string]*Node
+	leases map[int]*Lease
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{
+		nodes:  make(map[string]*Node),
+		leases: make(map[int]*Lease),
+	}
+}
+
+func (r *memoryRepository) UpsertNode(ctx context.Context, node *Node) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *node
+	r.nodes[node.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepository) FindNodesSeenSince(ctx context.Context, since time.Time) ([]*Node, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var nodes []*Node
+	for _, n := range r.nodes {
+		if !n.LastSeenAt.Before(since) {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes, nil
+}
+
+func (r *memoryRepository) DeleteNode(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.nodes, id)
+	return nil
+}
+
+func (r *memoryRepository) AcquireLease(ctx context.Context, shard int, owner string, now time.Time, expiresAt time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	l, ok := r.leases[shard]
+	if ok && l.Owner != owner && !l.ExpiresAt.Before(now) {
+		return false, nil
+	}
+	r.leases[shard] = &Lease{Shard: shard, Owner: owner, ExpiresAt: expiresAt, UpdatedAt: now}
+	return true, nil
+}
+
+func (r *memoryRepository) ReleaseLease(ctx context.Context, shard int, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if l, ok := r.leases[shard]; ok && l.Owner == owner {
+		delete(r.leases, shard)
+	}
+	return nil
+}
+
+func (r *memoryRepository) FindLeases(ctx context.Context) ([]*Lease, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var leases []*Lease
+	for _, l := range r.leases {
+		leases = append(leases, l)
+	}
+	return leases, nil
+}
+
+func newTestService(repo Repository, nodeID string) *ServiceImpl {
+	return NewService(repo, Options{
+		Enabled:  true,
+		NodeID:   nodeID,
+		Shards:   16,
+		LeaseTTL: time.Minute,
+	}, zap.NewNop().Sugar()).(*ServiceImpl)
+}
+
+func TestService_DisabledOwnsEverything(t *testing.T) {
+	svc := NewService(newMemoryRepository(), Options{Enabled: false}, zap.NewNop().Sugar())
+
+	assert.False(t, svc.Enabled())
+	assert.NotEmpty(t, svc.NodeID())
+	assert.True(t, svc.Owns("any-monitor"))
+}
+
+func TestService_SingleNodeOwnsAllShards(t *testing.T) {
+	repo := newMemoryRepository()
+	node := newTestService(repo, "node-a")
+
+	changed := node.sync(context.Background())
+
+	assert.True(t, changed)
+	assert.Len(t, node.owned, 16)
+	for _, id := range []string{"m1", "m2", "m3", "m4"} {
+		assert.True(t, node.Owns(id))
+	}
+}
+
+func TestService_ShardsAreSplitBetweenNodes(t *testing.T) {
+	repo := newMemoryRepository()
+	ctx := context.Background()
+	a := newTestService(repo, "node-a")
+	b := newTestService(repo, "node-b")
+
+	// a starts alone and takes everything
+	a.sync(ctx)
+	require.Len(t, a.owned, 16)
+
+	// b joins: it registers and finds its shards still held by a
+	b.sync(ctx)
+	// a sees b and releases b's shards, then b picks them up
+	a.sync(ctx)
+	b.sync(ctx)
+
+	assert.Equal(t, 16, len(a.owned)+len(b.owned))
+	assert.NotEmpty(t, a.owned)
+	assert.NotEmpty(t, b.owned)
+
+	for shard := range a.owned {
+		_, dup := b.owned[shard]
+		assert.False(t, dup, "shard %d owned by both nodes", shard)
+	}
+
+	for _, id := range []string{"m1", "m2", "m3", "m4", "m5", "m6"} {
+		assert.NotEqual(t, a.Owns(id), b.Owns(id), "monitor %s must run on exactly one node", id)
+	}
+}
+
+func TestService_TakeoverAfterNodeDies(t *testing.T) {
+	repo := newMemoryRepository()
+	ctx := context.Background()
+	a := newTestService(repo, "node-a")
+	b := newTestService(repo, "node-b")
+
+	a.sync(ctx)
+	b.sync(ctx)
+	a.sync(ctx)
+	b.sync(ctx)
+	require.NotEmpty(t, b.owned)
+
+	// b stops renewing: age its node record and leases past the ttl
+	repo.mu.Lock()
+	repo.nodes["node-b"].LastSeenAt = time.Now().UTC().Add(-2 * time.Minute)
+	for _, l := range repo.leases {
+		if l.Owner == "node-b" {
+			l.ExpiresAt = time.Now().UTC().Add(-time.Second)
+		}
+	}
+	repo.mu.Unlock()
+
+	changed := a.sync(ctx)
+
+	assert.True(t, changed)
+	assert.Len(t, a.owned, 16)
+}
+
+func TestOwnerFor_IsStable(t *testing.T) {
+	nodes := []string{"node-a", "node-b", "node-c"}
+	for shard := 0; shard < 32; shard++ {
+		owner := ownerFor(shard, nodes)
+		assert.Contains(t, nodes, owner)
+		// order of the node list must not matter
+		assert.Equal(t, owner, ownerFor(shard, []string{"node-c", "node-a", "node-b"}))
+	}
+}
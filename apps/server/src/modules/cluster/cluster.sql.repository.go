@@ -0,0 +1,170 @@
+package cluster
+
+import (
+	"context"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type nodeSQLModel struct {
+	bun.BaseModel `bun:"table:cluster_nodes,alias:cn"`
+
+	ID         string    `bun:"id,pk"`
+	StartedAt  time.Time `bun:"started_at,nullzero,notnull,default:current_timestamp"`
+	LastSeenAt time.Time `bun:"last_seen_at,nullzero,notnull,default:current_timestamp"`
+}
+
+type leaseSQLModel struct {
+	bun.BaseModel `bun:"table:monitor_leases,alias:ml"`
+
+	Shard     int       `bun:"shard,pk"`
+	Owner     string    `bun:"owner,notnull"`
+	ExpiresAt time.Time `bun:"expires_at,notnull"`
+	UpdatedAt time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+}
+
+func toNodeFromSQL(sm *nodeSQLModel) *Node {
+	return &Node{
+		ID:         sm.ID,
+		StartedAt:  sm.StartedAt,
+		LastSeenAt: sm.LastSeenAt,
+	}
+}
+
+func toLeaseFromSQL(sm *leaseSQLModel) *Lease {
+	return &Lease{
+		Shard:     sm.Shard,
+		Owner:     sm.Owner,
+		ExpiresAt: sm.ExpiresAt,
+		UpdatedAt: sm.UpdatedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) UpsertNode(ctx context.Context, node *Node) error {
+	res, err := r.db.NewUpdate().
+		Model((*nodeSQLModel)(nil)).
+		Set("last_seen_at = ?", node.LastSeenAt).
+		Where("id = ?", node.ID).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		return nil
+	}
+
+	sm := &nodeSQLModel{
+		ID:         node.ID,
+		StartedAt:  node.StartedAt,
+		LastSeenAt: node.LastSeenAt,
+	}
+	_, err = r.db.NewInsert().Model(sm).Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) FindNodesSeenSince(ctx context.Context, since time.Time) ([]*Node, error) {
+	var sms []*nodeSQLModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Where("last_seen_at >= ?", since).
+		Order("id ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var nodes []*Node
+	for _, sm := range sms {
+		nodes = append(nodes, toNodeFromSQL(sm))
+	}
+	return nodes, nil
+}
+
+func (r *SQLRepositoryImpl) DeleteNode(ctx context.Context, id string) error {
+	_, err := r.db.NewDelete().
+		Model((*nodeSQLModel)(nil)).
+		Where("id = ?", id).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) AcquireLease(ctx context.Context, shard int, owner string, now time.Time, expiresAt time.Time) (bool, error) {
+	// Renew our own lease or take over an expired one in a single conditional update
+	res, err := r.db.NewUpdate().
+		Model((*leaseSQLModel)(nil)).
+		Set("owner = ?", owner).
+		Set("expires_at = ?", expiresAt).
+		Set("updated_at = ?", now).
+		Where("shard = ?", shard).
+		Where("(owner = ? OR expires_at < ?)", owner, now).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if affected, _ := res.RowsAffected(); affected > 0 {
+		return true, nil
+	}
+
+	// No row was updated: either the shard was never leased or somebody else holds it
+	sm := &leaseSQLModel{
+		Shard:     shard,
+		Owner:     owner,
+		ExpiresAt: expiresAt,
+		UpdatedAt: now,
+	}
+	_, err = r.db.NewInsert().Model(sm).Exec(ctx)
+	if err == nil {
+		return true, nil
+	}
+
+	// Insert failed, check whether it was caused by a concurrent owner
+	exists, existsErr := r.db.NewSelect().
+		Model((*leaseSQLModel)(nil)).
+		Where("shard = ?", shard).
+		Exists(ctx)
+	if existsErr != nil {
+		return false, existsErr
+	}
+	if exists {
+		return false, nil
+	}
+
+	return false, err
+}
+
+func (r *SQLRepositoryImpl) ReleaseLease(ctx context.Context, shard int, owner string) error {
+	_, err := r.db.NewDelete().
+		Model((*leaseSQLModel)(nil)).
+		Where("shard = ?", shard).
+		Where("owner = ?", owner).
+		Exec(ctx)
+	return err
+}
+
+func (r *SQLRepositoryImpl) FindLeases(ctx context.Context) ([]*Lease, error) {
+	var sms []*leaseSQLModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Order("shard ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var leases []*Lease
+	for _, sm := range sms {
+		leases = append(leases, toLeaseFromSQL(sm))
+	}
+	return leases, nil
+}
@@ -0,0 +1,22 @@
+package domain_expiry
+
+import (
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/notification_sent_history"
+	"peekaping/src/modules/shared"
+
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+func RegisterDependencies(container *dig.Container) {
+	container.Provide(func(
+		settingService shared.SettingService,
+		eventBus *events.EventBus,
+		notificationHistoryService notification_sent_history.Service,
+		logger *zap.SugaredLogger,
+	) Service {
+		notificationService := NewEventBasedNotificationService(eventBus, logger)
+		return NewService(settingService, notificationService, notificationHistoryService, logger)
+	})
+}
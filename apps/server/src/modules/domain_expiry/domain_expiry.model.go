@@ -0,0 +1,17 @@
+package domain_expiry
+
+import (
+	"peekaping/src/modules/shared"
+)
+
+type DomainInfo = shared.DomainInfo
+
+// DomainExpiryEvent represents a domain expiry event payload
+type DomainExpiryEvent struct {
+	MonitorID     string      `json:"monitor_id"`
+	MonitorName   string      `json:"monitor_name"`
+	DomainInfo    *DomainInfo `json:"domain_info"`
+	DaysRemaining int         `json:"days_remaining"`
+	TargetDays    int         `json:"target_days"`
+	Message       string      `json:"message"`
+}
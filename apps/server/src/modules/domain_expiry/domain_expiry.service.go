@@ -0,0 +1,210 @@
+package domain_expiry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/notification_sent_history"
+	"peekaping/src/modules/shared"
+	"slices"
+
+	"go.uber.org/zap"
+)
+
+// notificationType is the type domain expiry notifications are recorded with in the history
+const notificationType = "domain"
+
+const settingKey = "domain_expiry_notify_days"
+
+var defaultNotificationDays = []int{7, 14, 30}
+
+type Service interface {
+	// CheckDomainExpiry sends a notification for every threshold the domain expiry has passed,
+	// notifyDays overrides the thresholds from settings when not empty
+	CheckDomainExpiry(ctx context.Context, domainInfo *DomainInfo, monitorID string, monitorName string, notifyDays []int) error
+	GetNotificationDays(ctx context.Context) ([]int, error)
+	SetNotificationDays(ctx context.Context, days []int) error
+}
+
+type ServiceImpl struct {
+	settingService             shared.SettingService
+	notificationService        NotificationService
+	notificationHistoryService notification_sent_history.Service
+	logger                     *zap.SugaredLogger
+}
+
+// NotificationService interface for sending domain expiry notifications
+type NotificationService interface {
+	SendDomainExpiryNotification(ctx context.Context, monitorID string, monitorName string, domainInfo *DomainInfo, daysRemaining int, targetDays int) error
+}
+
+func NewService(
+	settingService shared.SettingService,
+	notificationService NotificationService,
+	notificationHistoryService notification_sent_history.Service,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		settingService:             settingService,
+		notificationService:        notificationService,
+		notificationHistoryService: notificationHistoryService,
+		logger:                     logger.Named("[domain-expiry-service]"),
+	}
+}
+
+// CheckDomainExpiry checks the domain expiry and sends notifications if needed
+func (s *ServiceImpl) CheckDomainExpiry(ctx context.Context, domainInfo *DomainInfo, monitorID string, monitorName string, notifyDays []int) error {
+	if domainInfo == nil || domainInfo.ExpiresAt.IsZero() {
+		s.logger.Debug("No domain expiry info available")
+		return nil
+	}
+
+	if len(notifyDays) == 0 {
+		days, err := s.GetNotificationDays(ctx)
+		if err != nil {
+			s.logger.Errorf("Failed to get notification days: %v", err)
+			return err
+		}
+		notifyDays = days
+	}
+
+	if len(notifyDays) == 0 {
+		s.logger.Debug("No notification days configured, skipping domain expiry check")
+		return nil
+	}
+
+	// A domain outside of every threshold has been renewed since the last notification,
+	// forget the sent notifications so the next expiry is announced again
+	if domainInfo.DaysRemaining > slices.Max(notifyDays) {
+		history, err := s.notificationHistoryService.GetNotificationHistory(ctx, monitorID, notificationType)
+		if err != nil {
+			return fmt.Errorf("failed to get notification history: %w", err)
+		}
+		if len(history) > 0 {
+			s.logger.Infof("Domain %s renewed for monitor %s, clearing notification history", domainInfo.Domain, monitorID)
+			if err := s.notificationHistoryService.ClearNotificationHistory(ctx, monitorID, notificationType); err != nil {
+				s.logger.Errorf("Failed to clear notification history: %v", err)
+			}
+		}
+		return nil
+	}
+
+	// Check each notification threshold with deduplication
+	for _, targetDays := range notifyDays {
+		if domainInfo.DaysRemaining > targetDays || domainInfo.DaysRemaining < 0 {
+			continue
+		}
+
+		// Check if we already sent a notification for this threshold
+		alreadySent, err := s.notificationHistoryService.CheckIfNotificationSent(ctx, notificationType, monitorID, targetDays)
+		if err != nil {
+			s.logger.Errorf("Failed to check notification history: %v", err)
+			continue
+		}
+
+		if alreadySent {
+			s.logger.Debugf("Domain notification already sent for monitor %s, threshold %d days", monitorID, targetDays)
+			continue
+		}
+
+		s.logger.Infof("Sending domain expiry notification: %s expires in %d days (threshold: %d)", domainInfo.Domain, domainInfo.DaysRemaining, targetDays)
+
+		if err := s.notificationService.SendDomainExpiryNotification(
+			ctx, monitorID, monitorName, domainInfo, domainInfo.DaysRemaining, targetDays,
+		); err != nil {
+			s.logger.Errorf("Failed to send domain expiry notification: %v", err)
+			continue
+		}
+
+		// Record that we sent the notification
+		if err := s.notificationHistoryService.RecordNotificationSent(ctx, notificationType, monitorID, targetDays); err != nil {
+			s.logger.Errorf("Failed to record notification sent: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// GetNotificationDays retrieves the domain expiry notification days from settings
+func (s *ServiceImpl) GetNotificationDays(ctx context.Context) ([]int, error) {
+	setting, err := s.settingService.GetByKey(ctx, settingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get domain notification days setting: %w", err)
+	}
+
+	// If setting doesn't exist, initialize with default values
+	if setting == nil {
+		if err := s.SetNotificationDays(ctx, defaultNotificationDays); err != nil {
+			s.logger.Errorf("Failed to initialize default notification days: %v", err)
+		}
+		return defaultNotificationDays, nil
+	}
+
+	var days []int
+	if err := json.Unmarshal([]byte(setting.Value), &days); err != nil {
+		s.logger.Errorf("Failed to parse notification days from setting: %v", err)
+		return defaultNotificationDays, nil
+	}
+
+	return days, nil
+}
+
+// SetNotificationDays sets the domain expiry notification days in settings
+func (s *ServiceImpl) SetNotificationDays(ctx context.Context, days []int) error {
+	jsonData, err := json.Marshal(days)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification days: %w", err)
+	}
+
+	dto := &shared.SettingCreateUpdateDto{
+		Value: string(jsonData),
+		Type:  "json",
+	}
+
+	if _, err := s.settingService.SetByKey(ctx, settingKey, dto); err != nil {
+		return fmt.Errorf("failed to save notification days setting: %w", err)
+	}
+
+	s.logger.Infof("Updated domain expiry notification days: %v", days)
+	return nil
+}
+
+// EventBasedNotificationService integrates with the existing notification system via events
+type EventBasedNotificationService struct {
+	eventBus *events.EventBus
+	logger   *zap.SugaredLogger
+}
+
+func NewEventBasedNotificationService(eventBus *events.EventBus, logger *zap.SugaredLogger) NotificationService {
+	return &EventBasedNotificationService{
+		eventBus: eventBus,
+		logger:   logger,
+	}
+}
+
+func (s *EventBasedNotificationService) SendDomainExpiryNotification(
+	ctx context.Context,
+	monitorID string,
+	monitorName string,
+	domainInfo *DomainInfo,
+	daysRemaining int,
+	targetDays int,
+) error {
+	message := fmt.Sprintf("Domain expiry warning: Domain '%s' expires in %d days", domainInfo.Domain, daysRemaining)
+
+	s.eventBus.Publish(events.Event{
+		Type: events.DomainExpiry,
+		Payload: &DomainExpiryEvent{
+			MonitorID:     monitorID,
+			MonitorName:   monitorName,
+			DomainInfo:    domainInfo,
+			DaysRemaining: daysRemaining,
+			TargetDays:    targetDays,
+			Message:       message,
+		},
+	})
+
+	s.logger.Infof("Published domain expiry event for monitor %s: %s", monitorName, message)
+	return nil
+}
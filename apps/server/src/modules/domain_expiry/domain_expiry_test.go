@@ -0,0 +1,138 @@
+package domain_expiry
+
+import (
+	"context"
+	"peekaping/src/modules/notification_sent_history"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type MockHistoryService struct {
+	mock.Mock
+}
+
+func (m *MockHistoryService) CheckIfNotificationSent(ctx context.Context, notificationType string, monitorID string, targetDays int) (bool, error) {
+	args := m.Called(ctx, notificationType, monitorID, targetDays)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockHistoryService) RecordNotificationSent(ctx context.Context, notificationType string, monitorID string, targetDays int) error {
+	args := m.Called(ctx, notificationType, monitorID, targetDays)
+	return args.Error(0)
+}
+
+func (m *MockHistoryService) ClearNotificationHistory(ctx context.Context, monitorID string, notificationType string) error {
+	args := m.Called(ctx, monitorID, notificationType)
+	return args.Error(0)
+}
+
+func (m *MockHistoryService) CleanupOldRecords(ctx context.Context, olderThanDays int) error {
+	args := m.Called(ctx, olderThanDays)
+	return args.Error(0)
+}
+
+func (m *MockHistoryService) GetNotificationHistory(ctx context.Context, monitorID string, notificationType string) ([]*notification_sent_history.Model, error) {
+	args := m.Called(ctx, monitorID, notificationType)
+	return args.Get(0).([]*notification_sent_history.Model), args.Error(1)
+}
+
+type MockSettingService struct {
+	mock.Mock
+}
+
+func (m *MockSettingService) GetByKey(ctx context.Context, key string) (*shared.SettingModel, error) {
+	args := m.Called(ctx, key)
+	setting, _ := args.Get(0).(*shared.SettingModel)
+	return setting, args.Error(1)
+}
+
+func (m *MockSettingService) SetByKey(ctx context.Context, key string, entity *shared.SettingCreateUpdateDto) (*shared.SettingModel, error) {
+	args := m.Called(ctx, key, entity)
+	setting, _ := args.Get(0).(*shared.SettingModel)
+	return setting, args.Error(1)
+}
+
+func (m *MockSettingService) DeleteByKey(ctx context.Context, key string) error {
+	return m.Called(ctx, key).Error(0)
+}
+
+func (m *MockSettingService) InitializeSettings(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (m *MockNotificationService) SendDomainExpiryNotification(ctx context.Context, monitorID string, monitorName string, domainInfo *DomainInfo, daysRemaining int, targetDays int) error {
+	args := m.Called(ctx, monitorID, monitorName, domainInfo, daysRemaining, targetDays)
+	return args.Error(0)
+}
+
+func TestCheckDomainExpiry(t *testing.T) {
+	ctx := context.Background()
+	logger := zap.NewNop().Sugar()
+
+	t.Run("notifies once per passed threshold", func(t *testing.T) {
+		history := new(MockHistoryService)
+		notifications := new(MockNotificationService)
+		service := NewService(new(MockSettingService), notifications, history, logger)
+
+		info := &DomainInfo{Domain: "example.com", ExpiresAt: time.Now().Add(10 * 24 * time.Hour), DaysRemaining: 10}
+
+		history.On("CheckIfNotificationSent", ctx, "domain", "monitor-1", 14).Return(true, nil)
+		history.On("CheckIfNotificationSent", ctx, "domain", "monitor-1", 30).Return(false, nil)
+		notifications.On("SendDomainExpiryNotification", ctx, "monitor-1", "Example", info, 10, 30).Return(nil)
+		history.On("RecordNotificationSent", ctx, "domain", "monitor-1", 30).Return(nil)
+
+		err := service.CheckDomainExpiry(ctx, info, "monitor-1", "Example", []int{7, 14, 30})
+
+		assert.NoError(t, err)
+		history.AssertExpectations(t)
+		notifications.AssertExpectations(t)
+		history.AssertNotCalled(t, "CheckIfNotificationSent", ctx, "domain", "monitor-1", 7)
+	})
+
+	t.Run("clears history after renewal", func(t *testing.T) {
+		history := new(MockHistoryService)
+		notifications := new(MockNotificationService)
+		service := NewService(new(MockSettingService), notifications, history, logger)
+
+		info := &DomainInfo{Domain: "example.com", ExpiresAt: time.Now().Add(365 * 24 * time.Hour), DaysRemaining: 365}
+
+		history.On("GetNotificationHistory", ctx, "monitor-1", "domain").Return([]*notification_sent_history.Model{{Days: 30}}, nil)
+		history.On("ClearNotificationHistory", ctx, "monitor-1", "domain").Return(nil)
+
+		err := service.CheckDomainExpiry(ctx, info, "monitor-1", "Example", []int{7, 14, 30})
+
+		assert.NoError(t, err)
+		history.AssertExpectations(t)
+		notifications.AssertNotCalled(t, "SendDomainExpiryNotification", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("uses the thresholds from settings", func(t *testing.T) {
+		history := new(MockHistoryService)
+		settings := new(MockSettingService)
+		notifications := new(MockNotificationService)
+		service := NewService(settings, notifications, history, logger)
+
+		info := &DomainInfo{Domain: "example.com", ExpiresAt: time.Now().Add(3 * 24 * time.Hour), DaysRemaining: 3}
+
+		settings.On("GetByKey", ctx, "domain_expiry_notify_days").Return(&shared.SettingModel{Value: "[5]"}, nil)
+		history.On("CheckIfNotificationSent", ctx, "domain", "monitor-1", 5).Return(false, nil)
+		notifications.On("SendDomainExpiryNotification", ctx, "monitor-1", "Example", info, 3, 5).Return(nil)
+		history.On("RecordNotificationSent", ctx, "domain", "monitor-1", 5).Return(nil)
+
+		err := service.CheckDomainExpiry(ctx, info, "monitor-1", "Example", nil)
+
+		assert.NoError(t, err)
+		settings.AssertExpectations(t)
+		history.AssertExpectations(t)
+		notifications.AssertExpectations(t)
+	})
+}
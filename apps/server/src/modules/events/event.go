@@ -26,8 +26,18 @@ const (
 	ProxyDeleted EventType = "proxy.deleted"
 	// CertificateExpiry is emitted when a certificate is expiring
 	CertificateExpiry EventType = "certificate.expiry"
+	// DomainExpiry is emitted when a domain registration is expiring
+	DomainExpiry EventType = "domain.expiry"
 	// ImportantHeartbeat is emitted when a heartbeat is important for notification purposes
 	ImportantHeartbeat EventType = "important.heartbeat"
+	// MonitorFlapping is emitted when a monitor starts or stops flapping
+	MonitorFlapping EventType = "monitor.flapping"
+	// MaintenanceCreated is emitted when a maintenance is created
+	MaintenanceCreated EventType = "maintenance.created"
+	// MaintenanceUpdated is emitted when a maintenance or its monitors are updated
+	MaintenanceUpdated EventType = "maintenance.updated"
+	// MaintenanceDeleted is emitted when a maintenance is deleted
+	MaintenanceDeleted EventType = "maintenance.deleted"
 )
 
 // Event represents a generic event with a type and payload
@@ -0,0 +1,190 @@
+package healthcheck
+
+import (
+	"net/http"
+	"peekaping/src/modules/agent"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/proxy"
+	"peekaping/src/modules/shared"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const agentContextKey = "agent"
+
+type AgentResultsResponse struct {
+	Accepted int `json:"accepted"`
+	Rejected int `json:"rejected"`
+}
+
+// agentAuth resolves the calling agent from its token and records that it was seen
+func agentAuth(agentService agent.Service, logger *zap.SugaredLogger) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token := ctx.GetHeader(shared.AgentTokenHeader)
+		if token == "" {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utils.NewFailResponse("Agent token required"))
+			return
+		}
+
+		a, err := agentService.FindByToken(ctx, token)
+		if err != nil {
+			logger.Errorw("Failed to find agent by token", "error", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		if a == nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, utils.NewFailResponse("Invalid agent token"))
+			return
+		}
+		if !a.Active {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, utils.NewFailResponse("Agent is not active"))
+			return
+		}
+
+		if err := agentService.MarkSeen(ctx, a.ID); err != nil {
+			logger.Warnw("Failed to update agent last seen time", "agentID", a.ID, "error", err)
+		}
+
+		ctx.Set(agentContextKey, a)
+		ctx.Next()
+	}
+}
+
+// RegisterAgentEndpoints exposes the API remote probe agents use to fetch their monitors and report results
+func RegisterAgentEndpoints(
+	router *gin.RouterGroup,
+	agentService agent.Service,
+	monitorService monitor.Service,
+	proxyService proxy.Service,
+	healthcheckSupervisor *HealthCheckSupervisor,
+	logger *zap.SugaredLogger,
+) {
+	logger = logger.Named("[agent-api]")
+
+	group := router.Group("/agent")
+	group.Use(agentAuth(agentService, logger))
+
+	// Monitors assigned to the calling agent together with their proxies
+	group.GET("/monitors", func(ctx *gin.Context) {
+		a := ctx.MustGet(agentContextKey).(*agent.Model)
+
+		monitorIDs, err := agentService.GetMonitorIDs(ctx, a.ID)
+		if err != nil {
+			logger.Errorw("Failed to get agent monitors", "agentID", a.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+
+		response := []*shared.AgentMonitor{}
+		if len(monitorIDs) == 0 {
+			ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
+			return
+		}
+
+		monitors, err := monitorService.FindByIDs(ctx, monitorIDs)
+		if err != nil {
+			logger.Errorw("Failed to get agent monitors", "agentID", a.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+
+		proxies := make(map[string]*proxy.Model)
+		for _, m := range monitors {
+			// Push monitors are fed by the monitored service itself and group monitors
+			// are derived from heartbeats stored on the server
+			if !m.Active || m.Type == "push" || m.Type == "group" {
+				continue
+			}
+
+			item := &shared.AgentMonitor{Monitor: m}
+			if m.ProxyId != "" {
+				p, ok := proxies[m.ProxyId]
+				if !ok {
+					p, err = proxyService.FindByID(ctx, m.ProxyId)
+					if err != nil {
+						logger.Errorw("Failed to fetch proxy for monitor", "monitorID", m.ID, "error", err)
+					}
+					proxies[m.ProxyId] = p
+				}
+				item.Proxy = p
+			}
+			response = append(response, item)
+		}
+
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
+	})
+
+	// Check results reported by the calling agent, recorded with the agent location
+	group.POST("/results", func(ctx *gin.Context) {
+		a := ctx.MustGet(agentContextKey).(*agent.Model)
+
+		var body shared.AgentResultsDto
+		if err := ctx.ShouldBindJSON(&body); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid request body"))
+			return
+		}
+		if err := utils.Validate.Struct(body); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+
+		monitorIDs, err := agentService.GetMonitorIDs(ctx, a.ID)
+		if err != nil {
+			logger.Errorw("Failed to get agent monitors", "agentID", a.ID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		assigned := make(map[string]bool, len(monitorIDs))
+		for _, id := range monitorIDs {
+			assigned[id] = true
+		}
+
+		response := AgentResultsResponse{}
+		for _, r := range body.Results {
+			if !assigned[r.MonitorID] {
+				response.Rejected++
+				continue
+			}
+
+			m, err := monitorService.FindByID(ctx, r.MonitorID)
+			if err != nil || m == nil || !m.Active {
+				response.Rejected++
+				continue
+			}
+
+			// The server records maintenance beats itself while the monitor is under maintenance
+			underMaintenance, err := healthcheckSupervisor.isUnderMaintenance(ctx, m.ID)
+			if err != nil {
+				logger.Errorw("Failed to check maintenance status", "monitorID", m.ID, "error", err)
+			}
+			if underMaintenance {
+				response.Rejected++
+				continue
+			}
+
+			// Checks report UP, DOWN or DEGRADED, the other statuses are decided by the server
+			if r.Status != shared.MonitorStatusUp && r.Status != shared.MonitorStatusDown &&
+				r.Status != shared.MonitorStatusDegraded {
+				response.Rejected++
+				continue
+			}
+
+			result := &executor.Result{
+				Status:     r.Status,
+				Message:    r.Message,
+				StartTime:  r.StartTime.UTC(),
+				EndTime:    r.EndTime.UTC(),
+				TLSInfo:    r.TLSInfo,
+				DomainInfo: r.DomainInfo,
+				Metadata:   r.Metadata,
+			}
+			healthcheckSupervisor.processReceivedResult(result, m, a.Location)
+			response.Accepted++
+		}
+
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
+	})
+}
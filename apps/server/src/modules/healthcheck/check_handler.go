@@ -0,0 +1,181 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"peekaping/src/modules/auth"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/proxy"
+	"peekaping/src/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+var (
+	errExecutorNotFound = errors.New("executor not found for monitor type")
+	errCheckInProgress  = errors.New("a check of the monitor is already running")
+)
+
+func (s *HealthCheckSupervisor) findProxy(ctx context.Context, proxyID string) (*proxy.Model, error) {
+	if proxyID == "" {
+		return nil, nil
+	}
+	return s.proxyService.FindByID(ctx, proxyID)
+}
+
+// CheckNow runs the monitor's executor immediately and records the heartbeat like a scheduled tick
+func (s *HealthCheckSupervisor) CheckNow(ctx context.Context, m *Monitor) (*executor.Result, error) {
+	exec, ok := s.execRegistry.GetExecutor(m.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errExecutorNotFound, m.Type)
+	}
+
+	proxyModel, err := s.findProxy(ctx, m.ProxyId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Share the busy flag of the scheduled task so a manual check never overlaps a tick
+	s.mu.RLock()
+	t, ok := s.active[m.ID]
+	s.mu.RUnlock()
+	if ok {
+		if !t.busy.CompareAndSwap(false, true) {
+			return nil, errCheckInProgress
+		}
+		defer t.busy.Store(false)
+	}
+
+	return s.handleMonitorTick(ctx, m, exec, proxyModel, nil), nil
+}
+
+// DryRun executes an unsaved monitor once without persisting anything
+func (s *HealthCheckSupervisor) DryRun(ctx context.Context, m *Monitor) (*executor.Result, error) {
+	exec, ok := s.execRegistry.GetExecutor(m.Type)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errExecutorNotFound, m.Type)
+	}
+
+	proxyModel, err := s.findProxy(ctx, m.ProxyId)
+	if err != nil {
+		return nil, err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, time.Duration(m.Timeout)*time.Second)
+	defer cancel()
+
+	return exec.Execute(callCtx, m, proxyModel), nil
+}
+
+// RegisterCheckEndpoints exposes on-demand checks of saved monitors and dry runs of unsaved ones
+func RegisterCheckEndpoints(
+	router *gin.RouterGroup,
+	middleware *auth.MiddlewareProvider,
+	monitorService monitor.Service,
+	healthcheckSupervisor *HealthCheckSupervisor,
+	logger *zap.SugaredLogger,
+) {
+	logger = logger.Named("[check-api]")
+
+	// Runs the monitor's executor immediately and records the heartbeat
+	router.POST("/monitors/:id/check", middleware.Auth(), func(ctx *gin.Context) {
+		id := ctx.Param("id")
+
+		m, err := monitorService.FindByID(ctx, id)
+		if err != nil {
+			logger.Errorw("Failed to fetch monitor", "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		if m == nil {
+			ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+			return
+		}
+
+		result, err := healthcheckSupervisor.CheckNow(ctx, m)
+		if err != nil {
+			if errors.Is(err, errExecutorNotFound) {
+				ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+				return
+			}
+			if errors.Is(err, errCheckInProgress) {
+				ctx.JSON(http.StatusConflict, utils.NewFailResponse("Monitor check already in progress"))
+				return
+			}
+			logger.Errorw("Failed to check monitor", "monitorID", id, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		if result == nil {
+			ctx.JSON(http.StatusUnprocessableEntity, utils.NewFailResponse("Executor produced no result"))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Check completed", result))
+	})
+
+	// Validates and executes an unsaved monitor once without persisting anything
+	router.POST("/monitors/test", middleware.Auth(), func(ctx *gin.Context) {
+		var dto monitor.CreateUpdateDto
+		if err := ctx.ShouldBindJSON(&dto); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+
+		if err := utils.Validate.Struct(dto); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+			return
+		}
+
+		// Push monitors only react to incoming pushes, there is nothing to execute
+		if dto.Type == "push" {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Push monitors can not be tested"))
+			return
+		}
+
+		if err := monitorService.ValidateMonitorConfig(dto.Type, dto.Config); err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(fmt.Sprintf("Invalid monitor configuration: %v", err)))
+			return
+		}
+
+		m := &Monitor{
+			Type:              dto.Type,
+			Name:              dto.Name,
+			Interval:          dto.Interval,
+			Timeout:           dto.Timeout,
+			MaxRetries:        dto.MaxRetries,
+			RetryInterval:     dto.RetryInterval,
+			ResendInterval:    dto.ResendInterval,
+			DegradedThreshold: dto.DegradedThreshold,
+			DegradedBreaches:  dto.DegradedBreaches,
+			FlapThreshold:     dto.FlapThreshold,
+			FlapWindow:        dto.FlapWindow,
+			Active:            dto.Active,
+			Config:            dto.Config,
+			ProxyId:           dto.ProxyId,
+			PushToken:         dto.PushToken,
+		}
+
+		result, err := healthcheckSupervisor.DryRun(ctx, m)
+		if err != nil {
+			if errors.Is(err, errExecutorNotFound) {
+				ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+				return
+			}
+			logger.Errorw("Failed to test monitor", "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		if result == nil {
+			ctx.JSON(http.StatusUnprocessableEntity, utils.NewFailResponse("Executor produced no result"))
+			return
+		}
+
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Test completed", result))
+	})
+}
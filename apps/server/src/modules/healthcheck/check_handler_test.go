@@ -0,0 +1,70 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSupervisor_DryRun(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	logger := zap.NewNop().Sugar()
+	s := &HealthCheckSupervisor{
+		execRegistry: executor.NewExecutorRegistry(logger, nil, nil),
+		logger:       logger,
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	m := &Monitor{
+		Type:    "tcp",
+		Name:    "local port",
+		Timeout: 5,
+		Config:  fmt.Sprintf(`{"host":"127.0.0.1","port":%d}`, port),
+	}
+
+	result, err := s.DryRun(context.Background(), m)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status)
+	assert.False(t, result.EndTime.Before(result.StartTime))
+}
+
+func TestSupervisor_DryRunUnknownType(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	s := &HealthCheckSupervisor{
+		execRegistry: executor.NewExecutorRegistry(logger, nil, nil),
+		logger:       logger,
+	}
+
+	_, err := s.DryRun(context.Background(), &Monitor{Type: "unknown", Timeout: 5})
+	assert.ErrorIs(t, err, errExecutorNotFound)
+
+	_, err = s.CheckNow(context.Background(), &Monitor{Type: "unknown", Timeout: 5})
+	assert.ErrorIs(t, err, errExecutorNotFound)
+}
+
+func TestSupervisor_CheckNowWhileTickRunning(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	running := &task{}
+	running.busy.Store(true)
+	s := &HealthCheckSupervisor{
+		active:       map[string]*task{"m1": running},
+		execRegistry: executor.NewExecutorRegistry(logger, nil, nil),
+		logger:       logger,
+	}
+
+	_, err := s.CheckNow(context.Background(), &Monitor{ID: "m1", Type: "tcp", Timeout: 5})
+	assert.ErrorIs(t, err, errCheckInProgress)
+	// The running tick still owns the task
+	assert.True(t, running.busy.Load())
+}
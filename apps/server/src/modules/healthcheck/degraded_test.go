@@ -0,0 +1,107 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubExecutor returns the same result on every check
+type stubExecutor struct {
+	executor.Executor
+	result *executor.Result
+}
+
+func (e *stubExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *executor.Proxy) *executor.Result {
+	result := *e.result
+	return &result
+}
+
+func TestDegradedStatus(t *testing.T) {
+	m := &Monitor{DegradedThreshold: 500, DegradedBreaches: 2}
+
+	// First slow check only counts towards the breaches
+	status, slow := degradedStatus(m, &monitorState{Status: shared.MonitorStatusUp}, shared.MonitorStatusUp, 800)
+	assert.Equal(t, shared.MonitorStatusUp, status)
+	assert.Equal(t, 1, slow)
+
+	status, slow = degradedStatus(m, &monitorState{Status: shared.MonitorStatusUp, SlowChecks: 1}, shared.MonitorStatusUp, 800)
+	assert.Equal(t, shared.MonitorStatusDegraded, status)
+	assert.Equal(t, 2, slow)
+
+	// A fast check resets the count
+	status, slow = degradedStatus(m, &monitorState{Status: shared.MonitorStatusDegraded, SlowChecks: 2}, shared.MonitorStatusUp, 100)
+	assert.Equal(t, shared.MonitorStatusUp, status)
+	assert.Equal(t, 0, slow)
+
+	// A reloaded state without the count stays degraded
+	status, _ = degradedStatus(m, &monitorState{Status: shared.MonitorStatusDegraded}, shared.MonitorStatusUp, 800)
+	assert.Equal(t, shared.MonitorStatusDegraded, status)
+
+	// Failed checks are never degraded
+	status, _ = degradedStatus(m, &monitorState{Status: shared.MonitorStatusUp, SlowChecks: 5}, shared.MonitorStatusDown, 800)
+	assert.Equal(t, shared.MonitorStatusDown, status)
+
+	// Disabled threshold
+	status, _ = degradedStatus(&Monitor{}, nil, shared.MonitorStatusUp, 10000)
+	assert.Equal(t, shared.MonitorStatusUp, status)
+
+	// Zero breaches degrade on the first slow check
+	status, _ = degradedStatus(&Monitor{DegradedThreshold: 500}, nil, shared.MonitorStatusUp, 800)
+	assert.Equal(t, shared.MonitorStatusDegraded, status)
+}
+
+func TestSupervisor_DegradedTransitions(t *testing.T) {
+	s := &HealthCheckSupervisor{}
+
+	assert.True(t, s.isImportantForNotification(shared.MonitorStatusUp, shared.MonitorStatusDegraded))
+	assert.True(t, s.isImportantForNotification(shared.MonitorStatusDegraded, shared.MonitorStatusUp))
+	assert.True(t, s.isImportantForNotification(shared.MonitorStatusDegraded, shared.MonitorStatusDown))
+	assert.False(t, s.isImportantForNotification(shared.MonitorStatusDegraded, shared.MonitorStatusDegraded))
+	assert.False(t, s.isImportantForNotification(shared.MonitorStatusDegraded, shared.MonitorStatusMaintenance))
+
+	assert.True(t, s.isImportantBeat(shared.MonitorStatusUp, shared.MonitorStatusDegraded))
+	assert.True(t, s.isImportantBeat(shared.MonitorStatusDegraded, shared.MonitorStatusMaintenance))
+	assert.False(t, s.isImportantBeat(shared.MonitorStatusDegraded, shared.MonitorStatusDegraded))
+}
+
+func TestSupervisor_DegradedMessage(t *testing.T) {
+	m := &Monitor{ID: "m1", Name: "Disk", Timeout: 5, DegradedThreshold: 100}
+	s, hbService := newTickTestSupervisor(nil, nil)
+
+	// Executors reporting DEGRADED keep their message even when the check was slow
+	warning := &stubExecutor{result: tickResult(shared.MonitorStatusDegraded, "DISK WARNING - 85% used", 300)}
+	result := s.handleMonitorTick(context.Background(), m, warning, nil, nil)
+	require.NotNil(t, result)
+	require.Len(t, hbService.beats, 1)
+	assert.Equal(t, shared.MonitorStatusDegraded, hbService.beats[0].Status)
+	assert.Equal(t, "DISK WARNING - 85% used", hbService.beats[0].Msg)
+
+	// A slow successful check is degraded by its response time
+	slow := &stubExecutor{result: tickResult(shared.MonitorStatusUp, "DISK OK", 300)}
+	s.handleMonitorTick(context.Background(), m, slow, nil, nil)
+	s.handleMonitorTick(context.Background(), m, slow, nil, nil)
+	require.Len(t, hbService.beats, 3)
+	assert.Equal(t, shared.MonitorStatusDegraded, hbService.beats[1].Status)
+	assert.Equal(t, "DISK OK (response time 300 ms exceeds 100 ms)", hbService.beats[1].Msg)
+	assert.Equal(t, "DISK OK (response time 300 ms exceeds 100 ms)", hbService.beats[2].Msg)
+}
+
+func TestSupervisor_ReportedResponseTime(t *testing.T) {
+	m := &Monitor{ID: "m1", Name: "Website", Timeout: 5, DegradedThreshold: 100}
+	s, hbService := newTickTestSupervisor(nil, nil)
+
+	// The response time measured by the check replaces the time the check took
+	result := tickResult(shared.MonitorStatusUp, "HTTP OK", 5)
+	result.Metadata = executor.Metadata{executor.MetadataResponseTimeMs: 250.4}
+	s.handleMonitorTick(context.Background(), m, &stubExecutor{result: result}, nil, nil)
+
+	require.Len(t, hbService.beats, 1)
+	assert.Equal(t, 250, hbService.beats[0].Ping)
+	assert.Equal(t, shared.MonitorStatusDegraded, hbService.beats[0].Status)
+	assert.Equal(t, "HTTP OK (response time 250 ms exceeds 100 ms)", hbService.beats[0].Msg)
+}
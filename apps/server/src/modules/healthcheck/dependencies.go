@@ -0,0 +1,53 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/shared"
+)
+
+// downParents returns the names of the parent monitors of monitorID that are currently DOWN.
+// It is only consulted for DOWN results, so healthy ticks do not read the dependency graph.
+func (s *HealthCheckSupervisor) downParents(ctx context.Context, monitorID string) ([]string, error) {
+	if s.dependencySvc == nil {
+		return nil, nil
+	}
+
+	rels, err := s.dependencySvc.FindByMonitorID(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rels) == 0 {
+		return nil, nil
+	}
+
+	parentIDs := make([]string, 0, len(rels))
+	for _, rel := range rels {
+		parentIDs = append(parentIDs, rel.ParentID)
+	}
+
+	parents, err := s.monitorSvc.FindByIDs(ctx, parentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, parent := range parents {
+		if !parent.Active {
+			continue
+		}
+
+		// Parents checked here are fresher in memory than the asynchronously updated monitor status
+		status := parent.Status
+		if s.clusterService.Owns(parent.ID) {
+			if state, ok := s.states.get(parent.ID); ok && state != nil {
+				status = state.Status
+			}
+		}
+
+		if status == shared.MonitorStatusDown {
+			names = append(names, parent.Name)
+		}
+	}
+
+	return names, nil
+}
@@ -0,0 +1,220 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/cluster"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/maintenance"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_dependency"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeDependencyService struct {
+	monitor_dependency.Service
+	parents map[string][]string
+}
+
+func (f *fakeDependencyService) FindByMonitorID(ctx context.Context, monitorID string) ([]*monitor_dependency.Model, error) {
+	var rels []*monitor_dependency.Model
+	for _, parentID := range f.parents[monitorID] {
+		rels = append(rels, &monitor_dependency.Model{MonitorID: monitorID, ParentID: parentID})
+	}
+	return rels, nil
+}
+
+type fakeMonitorService struct {
+	monitor.Service
+	monitors map[string]*Monitor
+}
+
+func (f *fakeMonitorService) FindByIDs(ctx context.Context, ids []string) ([]*Monitor, error) {
+	var monitors []*Monitor
+	for _, id := range ids {
+		if m, ok := f.monitors[id]; ok {
+			monitors = append(monitors, m)
+		}
+	}
+	return monitors, nil
+}
+
+func (f *fakeMonitorService) FindActive(ctx context.Context) ([]*Monitor, error) {
+	var monitors []*Monitor
+	for _, m := range f.monitors {
+		if m.Active {
+			monitors = append(monitors, m)
+		}
+	}
+	return monitors, nil
+}
+
+// fakeClusterService owns the given monitors, a nil set stands for a single node owning all of them
+type fakeClusterService struct {
+	cluster.Service
+	enabled bool
+	owned   map[string]bool
+}
+
+func (f *fakeClusterService) Enabled() bool {
+	return f.enabled
+}
+
+func (f *fakeClusterService) Owns(monitorID string) bool {
+	return f.owned == nil || f.owned[monitorID]
+}
+
+// recordingHeartbeatService stores the created heartbeats of monitors without history
+type recordingHeartbeatService struct {
+	heartbeat.Service
+	beats []*heartbeat.Model
+}
+
+func (r *recordingHeartbeatService) Create(ctx context.Context, dto *heartbeat.CreateUpdateDto) (*heartbeat.Model, error) {
+	hb := &heartbeat.Model{
+		MonitorID: dto.MonitorID,
+		Status:    dto.Status,
+		Msg:       dto.Msg,
+		Ping:      dto.Ping,
+		DownCount: dto.DownCount,
+		Retries:   dto.Retries,
+		Important: dto.Important,
+		Time:      dto.Time,
+		EndTime:   dto.EndTime,
+		Notified:  dto.Notified,
+		Location:  dto.Location,
+		Metadata:  dto.Metadata,
+	}
+	r.beats = append(r.beats, hb)
+	return hb, nil
+}
+
+func (r *recordingHeartbeatService) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
+	return nil, nil
+}
+
+type fakeMaintenanceService struct {
+	maintenance.Service
+}
+
+func (f *fakeMaintenanceService) GetMaintenancesByMonitorID(ctx context.Context, monitorID string) ([]*maintenance.Model, error) {
+	return nil, nil
+}
+
+// newTickTestSupervisor returns a supervisor able to process heartbeats of the given monitors
+func newTickTestSupervisor(monitors map[string]*Monitor, parents map[string][]string) (*HealthCheckSupervisor, *recordingHeartbeatService) {
+	logger := zap.NewNop().Sugar()
+	hbService := &recordingHeartbeatService{}
+	return &HealthCheckSupervisor{
+		dependencySvc:    &fakeDependencyService{parents: parents},
+		monitorSvc:       &fakeMonitorService{monitors: monitors},
+		clusterService:   &fakeClusterService{},
+		maintenanceSvc:   &fakeMaintenanceService{},
+		maintenances:     newMaintenanceCache(),
+		heartbeatService: hbService,
+		eventBus:         events.NewEventBus(logger),
+		states:           newStateManager(),
+		locations:        newLocationTracker(1, nil),
+		flaps:            newFlapTracker(),
+		logger:           logger,
+	}, hbService
+}
+
+// tickResult builds an executor result that took ping milliseconds
+func tickResult(status shared.MonitorStatus, message string, ping int) *executor.Result {
+	start := time.Now()
+	return &executor.Result{
+		Status:    status,
+		Message:   message,
+		StartTime: start,
+		EndTime:   start.Add(time.Duration(ping) * time.Millisecond),
+	}
+}
+
+func TestSupervisor_DownParents(t *testing.T) {
+	s := &HealthCheckSupervisor{
+		dependencySvc: &fakeDependencyService{parents: map[string][]string{
+			"child": {"router", "db", "paused"},
+		}},
+		monitorSvc: &fakeMonitorService{monitors: map[string]*Monitor{
+			"router": {ID: "router", Name: "Core router", Active: true, Status: shared.MonitorStatusDown},
+			"db":     {ID: "db", Name: "Database", Active: true, Status: shared.MonitorStatusDown},
+			"paused": {ID: "paused", Name: "Paused", Active: false, Status: shared.MonitorStatusDown},
+		}},
+		clusterService: &fakeClusterService{owned: map[string]bool{"db": true}},
+		states:         newStateManager(),
+		logger:         zap.NewNop().Sugar(),
+	}
+
+	// The database recovered on this node, the stored monitor status is not updated yet
+	s.states.set("db", &monitorState{Status: shared.MonitorStatusUp})
+
+	names, err := s.downParents(context.Background(), "child")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Core router"}, names)
+
+	names, err = s.downParents(context.Background(), "router")
+	require.NoError(t, err)
+	assert.Empty(t, names)
+}
+
+func TestSupervisor_DependencySuppressionAnnouncedAfterParentRecovers(t *testing.T) {
+	router := &Monitor{ID: "router", Name: "Core router", Active: true, Status: shared.MonitorStatusDown}
+	child := &Monitor{ID: "child", Name: "Website", Active: true}
+	s, hbService := newTickTestSupervisor(
+		map[string]*Monitor{"router": router},
+		map[string][]string{"child": {"router"}},
+	)
+
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusUp, "OK", 10), child, "", nil)
+	require.True(t, hbService.beats[0].Notified)
+
+	// The parent is down, the child outage is recorded without notification
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusDown, "timeout", 10), child, "", nil)
+	assert.False(t, hbService.beats[1].Notified)
+	assert.Equal(t, "Dependency down: Core router (timeout)", hbService.beats[1].Msg)
+
+	// The parent recovered while the child is still down, the outage is announced now
+	router.Status = shared.MonitorStatusUp
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusDown, "timeout", 10), child, "", nil)
+	assert.True(t, hbService.beats[2].Notified)
+	assert.Equal(t, "timeout", hbService.beats[2].Msg)
+
+	// Only announced once
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusDown, "timeout", 10), child, "", nil)
+	assert.False(t, hbService.beats[3].Notified)
+
+	// The announced outage recovers with a notification
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusUp, "OK", 10), child, "", nil)
+	assert.True(t, hbService.beats[4].Notified)
+}
+
+func TestSupervisor_DependencySuppressionSkipsUnannouncedRecovery(t *testing.T) {
+	router := &Monitor{ID: "router", Name: "Core router", Active: true, Status: shared.MonitorStatusDown}
+	child := &Monitor{ID: "child", Name: "Website", Active: true}
+	s, hbService := newTickTestSupervisor(
+		map[string]*Monitor{"router": router},
+		map[string][]string{"child": {"router"}},
+	)
+
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusUp, "OK", 10), child, "", nil)
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusDown, "timeout", 10), child, "", nil)
+	assert.False(t, hbService.beats[1].Notified)
+
+	// Parent and child recover together, there is no outage to resolve
+	router.Status = shared.MonitorStatusUp
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusUp, "OK", 10), child, "", nil)
+	assert.True(t, hbService.beats[2].Important)
+	assert.False(t, hbService.beats[2].Notified)
+
+	// Later outages notify as usual
+	s.postProcessHeartbeat(tickResult(shared.MonitorStatusDown, "timeout", 10), child, "", nil)
+	assert.True(t, hbService.beats[3].Notified)
+}
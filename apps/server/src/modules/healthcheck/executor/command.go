@@ -0,0 +1,351 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// commandMaxOutput bounds the stdout and stderr kept of a command, the rest is discarded
+	commandMaxOutput = 64 << 10
+	// commandOutputLength is the part of the output kept in the heartbeat
+	commandOutputLength = 1024
+)
+
+// Nagios plugin exit codes
+const (
+	nagiosOK       = 0
+	nagiosWarning  = 1
+	nagiosCritical = 2
+	nagiosUnknown  = 3
+)
+
+type CommandConfig struct {
+	// Command is the absolute path of an executable allowed by COMMAND_ALLOWLIST
+	Command string            `json:"command" validate:"required" example:"/usr/lib/nagios/plugins/check_disk"`
+	Args    []string          `json:"args" validate:"omitempty,max=64" example:"-w,20%,-c,10%,-p,/"`
+	Env     map[string]string `json:"env" validate:"omitempty"`
+	// WorkingDir is where the command runs, an empty temporary directory by default
+	WorkingDir string `json:"working_dir" validate:"omitempty" example:"/var/lib/peekaping"`
+	// ExitCodes maps exit code 0 to UP and others to DOWN with "zero", "nagios" maps 1 (WARNING)
+	// to DEGRADED as well
+	ExitCodes string `json:"exit_codes" validate:"omitempty,oneof=zero nagios" example:"nagios"`
+	// PingPerfdata is the label of the perfdata value reported as response time
+	PingPerfdata string `json:"ping_perfdata" validate:"omitempty" example:"time"`
+}
+
+// perfdatum is a value of Nagios performance data: 'label'=value[UOM];[warn];[crit];[min];[max]
+type perfdatum struct {
+	Label string
+	Value float64
+	Unit  string
+	Warn  string
+	Crit  string
+	Min   string
+	Max   string
+}
+
+type CommandExecutor struct {
+	logger    *zap.SugaredLogger
+	allowlist []string
+}
+
+// NewCommandExecutor allows the executables and directories of the comma separated allowlist,
+// commands are refused when it is empty
+func NewCommandExecutor(logger *zap.SugaredLogger, allowlist string) *CommandExecutor {
+	var entries []string
+	for _, entry := range strings.Split(allowlist, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(entry); err == nil {
+			entry = resolved
+		}
+		entries = append(entries, filepath.Clean(entry))
+	}
+
+	return &CommandExecutor{
+		logger:    logger,
+		allowlist: entries,
+	}
+}
+
+func (s *CommandExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[CommandConfig](configJSON)
+}
+
+func (s *CommandExecutor) Validate(configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*CommandConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	if _, err := s.resolve(cfg.Command); err != nil {
+		return err
+	}
+	if cfg.WorkingDir != "" && !filepath.IsAbs(cfg.WorkingDir) {
+		return errors.New("working_dir must be an absolute path")
+	}
+	return validateCommandEnv(cfg.Env)
+}
+
+// validateCommandEnv refuses variables that would let a monitor pick which binaries or
+// libraries the allowed command loads
+func validateCommandEnv(env map[string]string) error {
+	for name := range env {
+		if name == "" || strings.ContainsAny(name, "=\x00") {
+			return fmt.Errorf("invalid environment variable name %q", name)
+		}
+		upper := strings.ToUpper(name)
+		if upper == "PATH" || upper == "HOME" || strings.HasPrefix(upper, "LD_") || strings.HasPrefix(upper, "DYLD_") {
+			return fmt.Errorf("environment variable %s can not be set", name)
+		}
+	}
+	return nil
+}
+
+// resolve returns the real path of the command if the allowlist contains it or one of its
+// directories, symbolic links can not lead out of the allowlist
+func (s *CommandExecutor) resolve(command string) (string, error) {
+	if len(s.allowlist) == 0 {
+		return "", errors.New("command monitors are disabled, no COMMAND_ALLOWLIST is configured")
+	}
+	if !filepath.IsAbs(command) {
+		return "", errors.New("command must be an absolute path")
+	}
+
+	path, err := filepath.EvalSymlinks(command)
+	if err != nil {
+		return "", fmt.Errorf("command not found: %w", err)
+	}
+
+	for _, entry := range s.allowlist {
+		if path == entry || strings.HasPrefix(path, entry+string(filepath.Separator)) {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("command %s is not in the allowlist", command)
+}
+
+func (c *CommandExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := c.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*CommandConfig)
+
+	c.logger.Debugf("execute command cfg: %s %v", cfg.Command, cfg.Args)
+
+	// The allowlist may have changed since the monitor was saved
+	path, err := c.resolve(cfg.Command)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	// Configs written before the restricted variables were refused are not trusted either
+	if err := validateCommandEnv(cfg.Env); err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+
+	workingDir := cfg.WorkingDir
+	if workingDir == "" {
+		workingDir, err = os.MkdirTemp("", "peekaping-command-")
+		if err != nil {
+			return DownResult(fmt.Errorf("failed to create working directory: %w", err), time.Now().UTC(), time.Now().UTC())
+		}
+		defer os.RemoveAll(workingDir)
+	}
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &limitedOutput{limit: commandMaxOutput}
+	stderr := &limitedOutput{limit: commandMaxOutput}
+
+	cmd := exec.CommandContext(ctx, path, cfg.Args...)
+	cmd.Dir = workingDir
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// The environment of the server, database credentials included, is not passed on
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH"), "HOME=" + workingDir, "LANG=C"}
+	for name, value := range cfg.Env {
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+	cmd.WaitDelay = time.Second
+
+	startTime := time.Now().UTC()
+	err = cmd.Run()
+	endTime := time.Now().UTC()
+
+	var exitErr *exec.ExitError
+	if (err != nil && !errors.As(err, &exitErr)) || ctx.Err() != nil {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("command timed out after %v", timeout)
+		}
+		c.logger.Infof("Command check failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("failed to run command: %w", err), startTime, endTime)
+	}
+
+	exitCode := cmd.ProcessState.ExitCode()
+	output := stdout.buf.String()
+	if strings.TrimSpace(output) == "" {
+		output = stderr.buf.String()
+	}
+	text, perfdata := parseNagiosOutput(output)
+
+	metadata := Metadata{"exit_code": exitCode}
+	if output != "" {
+		metadata["output"] = truncate(output, commandOutputLength)
+	}
+	if len(perfdata) > 0 {
+		values := make([]Metadata, 0, len(perfdata))
+		for _, p := range perfdata {
+			value := Metadata{"label": p.Label, "value": p.Value}
+			for key, field := range map[string]string{"unit": p.Unit, "warn": p.Warn, "crit": p.Crit, "min": p.Min, "max": p.Max} {
+				if field != "" {
+					value[key] = field
+				}
+			}
+			values = append(values, value)
+		}
+		metadata["perfdata"] = values
+	}
+
+	if cfg.PingPerfdata != "" {
+		for _, p := range perfdata {
+			if p.Label == cfg.PingPerfdata {
+				if duration, ok := perfdataDuration(p); ok {
+					metadata[MetadataResponseTimeMs] = float64(duration) / float64(time.Millisecond)
+				}
+				break
+			}
+		}
+	}
+
+	result := &Result{
+		Status:    shared.MonitorStatusDown,
+		Message:   text,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Metadata:  metadata,
+	}
+	if result.Message == "" {
+		result.Message = fmt.Sprintf("Command exited with code %d", exitCode)
+	}
+
+	switch {
+	case exitCode == nagiosOK:
+		result.Status = shared.MonitorStatusUp
+	case cfg.ExitCodes == "nagios" && exitCode == nagiosWarning:
+		result.Status = shared.MonitorStatusDegraded
+	case cfg.ExitCodes == "nagios" && exitCode != nagiosCritical && exitCode != nagiosUnknown:
+		// Codes beyond the Nagios range are reported as they are
+		result.Message = fmt.Sprintf("Command exited with unexpected code %d: %s", exitCode, result.Message)
+	}
+
+	c.logger.Infof("Command check finished: %s, exit code %d", m.Name, exitCode)
+
+	return result
+}
+
+// parseNagiosOutput splits the output of a Nagios plugin into the status text of the first
+// line and the performance data found after "|" on the first line and the long text
+func parseNagiosOutput(output string) (string, []perfdatum) {
+	lines := strings.Split(strings.TrimRight(output, "\r\n"), "\n")
+
+	text, perf, _ := strings.Cut(lines[0], "|")
+	perfText := perf
+	inPerfdata := false
+	for _, line := range lines[1:] {
+		if !inPerfdata {
+			_, after, found := strings.Cut(line, "|")
+			if !found {
+				continue
+			}
+			inPerfdata = true
+			line = after
+		}
+		perfText += " " + line
+	}
+
+	return strings.TrimSpace(text), parsePerfdata(perfText)
+}
+
+// parsePerfdata reads space separated perfdata values, labels with spaces are single quoted
+// and malformed values are skipped
+func parsePerfdata(text string) []perfdatum {
+	var values []perfdatum
+	text = strings.TrimSpace(text)
+	for text != "" {
+		var label string
+		if strings.HasPrefix(text, "'") {
+			end := strings.Index(text[1:], "'=")
+			if end < 0 {
+				break
+			}
+			label = text[1 : end+1]
+			text = text[end+3:]
+		} else {
+			var found bool
+			label, text, found = strings.Cut(text, "=")
+			if !found {
+				break
+			}
+		}
+
+		var field string
+		field, text, _ = strings.Cut(text, " ")
+		text = strings.TrimLeft(text, " ")
+
+		parts := strings.Split(field, ";")
+		number := strings.TrimRight(parts[0], "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ%")
+		value, err := strconv.ParseFloat(number, 64)
+		label = strings.TrimSpace(label)
+		if err != nil || label == "" {
+			continue
+		}
+
+		p := perfdatum{Label: label, Value: value, Unit: parts[0][len(number):]}
+		for i, target := range []*string{&p.Warn, &p.Crit, &p.Min, &p.Max} {
+			if i+1 < len(parts) {
+				*target = parts[i+1]
+			}
+		}
+		values = append(values, p)
+	}
+	return values
+}
+
+// perfdataDuration converts a perfdata value with a time unit, values without unit are seconds
+func perfdataDuration(p perfdatum) (time.Duration, bool) {
+	var unit time.Duration
+	switch p.Unit {
+	case "s", "":
+		unit = time.Second
+	case "ms":
+		unit = time.Millisecond
+	case "us":
+		unit = time.Microsecond
+	default:
+		return 0, false
+	}
+	if p.Value < 0 {
+		return 0, false
+	}
+	return time.Duration(p.Value * float64(unit)), true
+}
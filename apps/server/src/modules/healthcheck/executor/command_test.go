@@ -0,0 +1,216 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"peekaping/src/modules/shared"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// checkScript is a Nagios style plugin that prints its arguments and exits with the code of
+// the first one
+const checkScript = `#!/bin/sh
+code=$1
+shift
+case "$code" in
+env) echo "secret=$DB_PASS site=$SITE pwd=$(pwd)"; exit 0 ;;
+quiet) echo "disk sensor offline" >&2; exit 2 ;;
+sleep) sleep 10 ;;
+esac
+echo "$@"
+exit "$code"
+`
+
+func newTestCommandExecutor(t *testing.T) (*CommandExecutor, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("command scripts need a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	allowed := filepath.Join(dir, "plugins")
+	require.NoError(t, os.Mkdir(allowed, 0o755))
+	writePlugin(t, allowed, "check_test", checkScript)
+	writePlugin(t, dir, "outside", checkScript)
+	require.NoError(t, os.Symlink(filepath.Join(dir, "outside"), filepath.Join(allowed, "escape")))
+
+	return NewCommandExecutor(zap.NewNop().Sugar(), " "+allowed+" ,"), allowed
+}
+
+func TestCommandExecutor_Validate(t *testing.T) {
+	executor, allowed := newTestCommandExecutor(t)
+	command := filepath.Join(allowed, "check_test")
+
+	tests := []struct {
+		name      string
+		config    map[string]any
+		wantError string
+	}{
+		{name: "valid", config: map[string]any{"command": command, "args": []string{"-w", "80"}, "exit_codes": "nagios", "env": map[string]string{"SITE": "a"}}},
+		{name: "missing command", config: map[string]any{"args": []string{"-w"}}, wantError: "required"},
+		{name: "relative command", config: map[string]any{"command": "check_test"}, wantError: "absolute path"},
+		{name: "not allowed", config: map[string]any{"command": "/bin/sh"}, wantError: "not in the allowlist"},
+		{name: "traversal", config: map[string]any{"command": allowed + "/../outside"}, wantError: "not in the allowlist"},
+		{name: "symlink out of allowlist", config: map[string]any{"command": filepath.Join(allowed, "escape")}, wantError: "not in the allowlist"},
+		{name: "missing executable", config: map[string]any{"command": filepath.Join(allowed, "check_missing")}, wantError: "command not found"},
+		{name: "relative working dir", config: map[string]any{"command": command, "working_dir": "tmp"}, wantError: "working_dir"},
+		{name: "invalid env name", config: map[string]any{"command": command, "env": map[string]string{"A=B": "c"}}, wantError: "environment variable"},
+		{name: "loader env", config: map[string]any{"command": command, "env": map[string]string{"LD_PRELOAD": "/tmp/evil.so"}}, wantError: "LD_PRELOAD can not be set"},
+		{name: "darwin loader env", config: map[string]any{"command": command, "env": map[string]string{"DYLD_INSERT_LIBRARIES": "/tmp/evil.dylib"}}, wantError: "can not be set"},
+		{name: "path env", config: map[string]any{"command": command, "env": map[string]string{"PATH": "/tmp"}}, wantError: "PATH can not be set"},
+		{name: "home env", config: map[string]any{"command": command, "env": map[string]string{"home": "/root"}}, wantError: "home can not be set"},
+		{name: "unknown exit codes", config: map[string]any{"command": command, "exit_codes": "sensu"}, wantError: "oneof"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config, _ := json.Marshal(tt.config)
+			err := executor.Validate(string(config))
+			if tt.wantError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	disabled := NewCommandExecutor(zap.NewNop().Sugar(), "")
+	err := disabled.Validate(`{"command":"` + command + `"}`)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "command monitors are disabled")
+}
+
+func TestCommandExecutor_Execute(t *testing.T) {
+	executor, allowed := newTestCommandExecutor(t)
+	command := filepath.Join(allowed, "check_test")
+	t.Setenv("DB_PASS", "hunter2")
+
+	workingDir := t.TempDir()
+
+	tests := []struct {
+		name        string
+		config      map[string]any
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+		wantPing    float64
+	}{
+		{
+			name:        "ok with perfdata",
+			config:      map[string]any{"args": []string{"0", "HTTP OK: 200 | time=0.250s;1;2;0 'page size'=512B;;;0"}, "ping_perfdata": "time"},
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "HTTP OK: 200",
+			wantPing:    250,
+		},
+		{
+			name:        "warning is down by default",
+			config:      map[string]any{"args": []string{"1", "DISK WARNING - 85% used"}},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "DISK WARNING - 85% used",
+		},
+		{
+			name:        "nagios warning is degraded",
+			config:      map[string]any{"args": []string{"1", "DISK WARNING - 85% used"}, "exit_codes": "nagios"},
+			wantStatus:  shared.MonitorStatusDegraded,
+			wantMessage: "DISK WARNING - 85% used",
+		},
+		{
+			name:        "nagios critical",
+			config:      map[string]any{"args": []string{"2", "DISK CRITICAL - 97% used"}, "exit_codes": "nagios"},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "DISK CRITICAL - 97% used",
+		},
+		{
+			name:        "nagios unexpected code",
+			config:      map[string]any{"args": []string{"7", "weird"}, "exit_codes": "nagios"},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "Command exited with unexpected code 7: weird",
+		},
+		{
+			name:        "stderr without stdout",
+			config:      map[string]any{"args": []string{"quiet"}},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "disk sensor offline",
+		},
+		{
+			name:        "no output",
+			config:      map[string]any{"args": []string{"3"}, "exit_codes": "nagios"},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "Command exited with code 3",
+		},
+		{
+			name:        "environment and working dir",
+			config:      map[string]any{"args": []string{"env"}, "env": map[string]string{"SITE": "berlin"}, "working_dir": workingDir},
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "secret= site=berlin pwd=" + workingDir,
+		},
+		{
+			name:        "restricted environment",
+			config:      map[string]any{"args": []string{"env"}, "env": map[string]string{"LD_LIBRARY_PATH": "/tmp"}},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "environment variable LD_LIBRARY_PATH can not be set",
+		},
+		{
+			name:        "timeout",
+			config:      map[string]any{"args": []string{"sleep"}},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "failed to run command: command timed out after 1s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["command"] = command
+			config, _ := json.Marshal(tt.config)
+
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "command",
+				Name:    "Command Monitor",
+				Timeout: 1,
+				Config:  string(config),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Equal(t, tt.wantMessage, result.Message)
+			if tt.wantPing > 0 {
+				assert.Equal(t, tt.wantPing, result.Metadata[MetadataResponseTimeMs])
+				assert.Less(t, result.EndTime.Sub(result.StartTime), time.Duration(tt.wantPing)*time.Millisecond)
+			}
+		})
+	}
+}
+
+func TestParseNagiosOutput(t *testing.T) {
+	output := "PING OK - Packet loss = 0%, RTA = 0.80 ms | rta=0.800000ms;100.000000;500.000000;0.000000 pl=0%;20;60;0\n" +
+		"long text line 1\n" +
+		"long text line 2 | 'free space'=42GB;;;0;100\n" +
+		"load1=0.5\n"
+
+	text, perfdata := parseNagiosOutput(output)
+	assert.Equal(t, "PING OK - Packet loss = 0%, RTA = 0.80 ms", text)
+	require.Len(t, perfdata, 4)
+
+	assert.Equal(t, perfdatum{Label: "rta", Value: 0.8, Unit: "ms", Warn: "100.000000", Crit: "500.000000", Min: "0.000000"}, perfdata[0])
+	assert.Equal(t, perfdatum{Label: "pl", Value: 0, Unit: "%", Warn: "20", Crit: "60", Min: "0"}, perfdata[1])
+	assert.Equal(t, perfdatum{Label: "free space", Value: 42, Unit: "GB", Min: "0", Max: "100"}, perfdata[2])
+	assert.Equal(t, perfdatum{Label: "load1", Value: 0.5}, perfdata[3])
+
+	duration, ok := perfdataDuration(perfdata[0])
+	assert.True(t, ok)
+	assert.Equal(t, 800*time.Microsecond, duration)
+	_, ok = perfdataDuration(perfdata[1])
+	assert.False(t, ok)
+
+	text, perfdata = parseNagiosOutput("OK")
+	assert.Equal(t, "OK", text)
+	assert.Empty(t, perfdata)
+}
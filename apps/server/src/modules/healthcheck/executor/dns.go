@@ -2,30 +2,64 @@ package executor
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/url"
+	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/shared"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/miekg/dns"
 	"go.uber.org/zap"
 )
 
 type DNSConfig struct {
-	Host           string `json:"host" validate:"required" example:"example.com"`
-	ResolverServer string `json:"resolver_server" validate:"required,ip" example:"1.1.1.1"`
+	Host string `json:"host" validate:"required" example:"example.com"`
+	// ResolverServer is the IP address of the resolver, or the DoH endpoint URL when Transport is https
+	ResolverServer string `json:"resolver_server" validate:"required" example:"1.1.1.1"`
 	Port           int    `json:"port" validate:"required,min=1,max=65535" example:"53"`
 	ResolveType    string `json:"resolve_type" validate:"required,oneof=A AAAA CAA CNAME MX NS PTR SOA SRV TXT" example:"A"`
+	// Transport is udp (default, retried over tcp when truncated), tcp, tls (DNS-over-TLS) or https (DNS-over-HTTPS)
+	Transport       string `json:"transport" validate:"omitempty,oneof=udp tcp tls https" example:"udp"`
+	TLSServerName   string `json:"tls_server_name" validate:"omitempty" example:"cloudflare-dns.com"`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors" example:"false"`
+	// AdditionalResolvers are queried next to ResolverServer with the same port and transport
+	AdditionalResolvers []string `json:"additional_resolvers" validate:"omitempty,max=10" example:"8.8.8.8,9.9.9.9"`
+	// Consistency is how many resolvers have to return the same answer: all (default), majority or any
+	Consistency string `json:"consistency" validate:"omitempty,oneof=all majority any" example:"all"`
+	// ExpectedAnswers are compared with the records as they are shown in the heartbeat
+	ExpectedAnswers []string `json:"expected_answers" validate:"omitempty" example:"93.184.215.14"`
+	// AnswerMatch is exact (same set, the default), contains (every expected answer is present)
+	// or regex (every expected answer is a pattern matching at least one record)
+	AnswerMatch string `json:"answer_match" validate:"omitempty,oneof=exact contains regex" example:"exact"`
+	// AlertOnChange reports DOWN while the records differ from the baseline, the records seen when
+	// the change detection started. Turning it off for one check accepts the current records.
+	AlertOnChange bool `json:"alert_on_change" example:"false"`
+	// DNSSEC requires a validated answer (AD flag) with signatures matching the zone keys
+	DNSSEC bool `json:"dnssec" example:"false"`
 }
 
+// metadataBaselineRecords carries the records changes are detected against from one heartbeat
+// to the next, so the baseline survives restarts and is shared by the replicas
+const metadataBaselineRecords = "baseline_records"
+
 type DNSExecutor struct {
 	logger *zap.SugaredLogger
+	// heartbeatService reads the baseline back, change detection is off without it (agents)
+	heartbeatService heartbeat.Service
+	// baselines keeps the last baseline of each monitor, carried forward when the latest
+	// heartbeat cannot be read or was written without running the check (maintenance)
+	baselines sync.Map
 }
 
-func NewDNSExecutor(logger *zap.SugaredLogger) *DNSExecutor {
+func NewDNSExecutor(logger *zap.SugaredLogger, heartbeatService heartbeat.Service) *DNSExecutor {
 	return &DNSExecutor{
-		logger: logger,
+		logger:           logger,
+		heartbeatService: heartbeatService,
 	}
 }
 
@@ -34,11 +68,44 @@ func (s *DNSExecutor) Unmarshal(configJSON string) (any, error) {
 }
 
 func (s *DNSExecutor) Validate(configJSON string) error {
-	cfg, err := s.Unmarshal(configJSON)
+	cfgAny, err := s.Unmarshal(configJSON)
 	if err != nil {
 		return err
 	}
-	return GenericValidator(cfg.(*DNSConfig))
+	cfg := cfgAny.(*DNSConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	for _, resolver := range append([]string{cfg.ResolverServer}, cfg.AdditionalResolvers...) {
+		if err := validateResolver(resolver, cfg.Transport); err != nil {
+			return err
+		}
+	}
+
+	if cfg.AnswerMatch == "regex" {
+		for _, pattern := range cfg.ExpectedAnswers {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid expected answer pattern %q: %w", pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateResolver(resolver, transport string) error {
+	if transport == "https" {
+		u, err := url.Parse(resolver)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("resolver %q must be an https URL for DNS-over-HTTPS", resolver)
+		}
+		return nil
+	}
+	if net.ParseIP(resolver) == nil {
+		return fmt.Errorf("resolver %q must be an IP address", resolver)
+	}
+	return nil
 }
 
 func (d *DNSExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
@@ -50,178 +117,279 @@ func (d *DNSExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy
 
 	d.logger.Debugf("execute dns cfg: %+v", cfg)
 
-	// Create custom resolver with specified DNS server
-	r := &net.Resolver{
-		PreferGo: true,
-		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-			dialer := &net.Dialer{
-				Timeout: time.Duration(m.Timeout) * time.Second,
-			}
-			return dialer.DialContext(ctx, network, fmt.Sprintf("%s:%d", cfg.ResolverServer, cfg.Port))
-		},
+	// Loaded outside of the check timeout, a slow resolver must not cost the baseline
+	baseline, baselineErr := d.baseline(ctx, m.ID)
+	if baselineErr != nil {
+		d.logger.Errorf("Failed to load DNS baseline of monitor %s: %v", m.ID, baselineErr)
+	}
+	if baseline == nil && m.ID != "" {
+		if known, ok := d.baselines.Load(m.ID); ok {
+			baseline = known.([]string)
+		}
 	}
 
+	timeout := time.Duration(m.Timeout) * time.Second
+	queryCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resolvers := append([]string{cfg.ResolverServer}, cfg.AdditionalResolvers...)
+
 	startTime := time.Now().UTC()
-	var recordsFound bool
-	var message string
-
-	switch strings.ToUpper(cfg.ResolveType) {
-	case "A":
-		var ips []net.IP
-		ips, err = r.LookupIP(ctx, "ip4", cfg.Host)
-		if err == nil && len(ips) > 0 {
-			recordsFound = true
-			ipStrings := make([]string, len(ips))
-			for i, ip := range ips {
-				ipStrings[i] = ip.String()
-			}
-			message = fmt.Sprintf("A records: %s", strings.Join(ipStrings, ", "))
-		}
-	case "AAAA":
-		var ips []net.IP
-		ips, err = r.LookupIP(ctx, "ip6", cfg.Host)
-		if err == nil && len(ips) > 0 {
-			recordsFound = true
-			ipStrings := make([]string, len(ips))
-			for i, ip := range ips {
-				ipStrings[i] = ip.String()
-			}
-			message = fmt.Sprintf("AAAA records: %s", strings.Join(ipStrings, ", "))
-		}
-	case "CNAME":
-		var cname string
-		cname, err = r.LookupCNAME(ctx, cfg.Host)
-		if err == nil && cname != "" {
-			recordsFound = true
-			message = fmt.Sprintf("CNAME: %s", cname)
-		}
-	case "MX":
-		var mxRecords []*net.MX
-		mxRecords, err = r.LookupMX(ctx, cfg.Host)
-		if err == nil && len(mxRecords) > 0 {
-			recordsFound = true
-			mxStrings := make([]string, len(mxRecords))
-			for i, mx := range mxRecords {
-				mxStrings[i] = fmt.Sprintf("%s (priority: %d)", mx.Host, mx.Pref)
-			}
-			message = fmt.Sprintf("MX records: %s", strings.Join(mxStrings, ", "))
-		}
-	case "NS":
-		var nsRecords []*net.NS
-		nsRecords, err = r.LookupNS(ctx, cfg.Host)
-		if err == nil && len(nsRecords) > 0 {
-			recordsFound = true
-			nsStrings := make([]string, len(nsRecords))
-			for i, ns := range nsRecords {
-				nsStrings[i] = ns.Host
-			}
-			message = fmt.Sprintf("NS records: %s", strings.Join(nsStrings, ", "))
-		}
-	case "TXT":
-		var txtRecords []string
-		txtRecords, err = r.LookupTXT(ctx, cfg.Host)
-		if err == nil && len(txtRecords) > 0 {
-			recordsFound = true
-			message = fmt.Sprintf("TXT records: %s", strings.Join(txtRecords, "; "))
-		}
-	case "PTR":
-		// For PTR records, we need to reverse the IP address
-		// This is a simplified version - in production you might want more robust handling
-		var names []string
-		names, err = r.LookupAddr(ctx, cfg.Host)
-		if err == nil && len(names) > 0 {
-			recordsFound = true
-			message = fmt.Sprintf("PTR records: %s", strings.Join(names, ", "))
-		}
-	case "SRV":
-		// SRV records require a service and protocol, which we'll extract from the host
-		// Format: _service._proto.name
-		var srvCname string
-		var srvRecords []*net.SRV
-		srvCname, srvRecords, err = r.LookupSRV(ctx, "", "", cfg.Host)
-		if err == nil && len(srvRecords) > 0 {
-			recordsFound = true
-			srvStrings := make([]string, len(srvRecords))
-			for i, srv := range srvRecords {
-				srvStrings[i] = fmt.Sprintf("%s:%d (priority: %d, weight: %d)", srv.Target, srv.Port, srv.Priority, srv.Weight)
-			}
-			message = fmt.Sprintf("SRV records (cname: %s): %s", srvCname, strings.Join(srvStrings, ", "))
+	answers := make([]*dnsAnswer, len(resolvers))
+	var wg sync.WaitGroup
+	for i, resolver := range resolvers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			answers[i] = queryDNS(queryCtx, cfg, resolver, timeout)
+		}()
+	}
+	wg.Wait()
+	endTime := time.Now().UTC()
+
+	down := func(message string) *Result {
+		d.logger.Infof("DNS lookup failed: %s, %s", m.Name, message)
+		metadata := dnsMetadata(cfg, answers, nil)
+		// A failed lookup keeps the baseline for the next check
+		if baseline != nil {
+			metadata[metadataBaselineRecords] = baseline
 		}
-	case "CAA":
-		// Use miekg/dns for CAA record lookup
-		msg := new(dns.Msg)
-		msg.SetQuestion(dns.Fqdn(cfg.Host), dns.TypeCAA)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   message,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Metadata:  metadata,
+		}
+	}
 
-		client := new(dns.Client)
-		client.Timeout = time.Duration(m.Timeout) * time.Second
+	answer, err := agreedAnswer(answers, cfg.Consistency)
+	if err != nil {
+		return down(err.Error())
+	}
 
-		var resp *dns.Msg
-		resp, _, err = client.Exchange(msg, fmt.Sprintf("%s:%d", cfg.ResolverServer, cfg.Port))
+	if len(answer.records) == 0 {
+		return down(fmt.Sprintf("No %s records found for %s", cfg.ResolveType, cfg.Host))
+	}
+
+	if err := matchAnswers(answer.records, cfg.ExpectedAnswers, cfg.AnswerMatch); err != nil {
+		return down(err.Error())
+	}
+
+	metadata := dnsMetadata(cfg, answers, answer.records)
 
-		if err == nil && resp != nil && len(resp.Answer) > 0 {
-			var caaStrings []string
-			for _, ans := range resp.Answer {
-				if caa, ok := ans.(*dns.CAA); ok {
-					caaStrings = append(caaStrings, fmt.Sprintf("%d %s %q", caa.Flag, caa.Tag, caa.Value))
+	sorted := sortedRecords(answer.records)
+	// Without a known baseline a failed load must not replace the stored one with these records
+	if m.ID != "" && d.heartbeatService != nil && (baseline != nil || baselineErr == nil) {
+		if baseline != nil && !slices.Equal(baseline, sorted) {
+			metadata["previous_records"] = baseline
+			metadata["records_changed"] = true
+			if cfg.AlertOnChange {
+				// The baseline is kept, the monitor stays DOWN until the records are back or accepted
+				metadata[metadataBaselineRecords] = baseline
+				d.logger.Infof("DNS records changed: %s, %v -> %v", m.Name, baseline, sorted)
+				return &Result{
+					Status:    shared.MonitorStatusDown,
+					Message:   fmt.Sprintf("DNS records changed from [%s] to [%s]", strings.Join(baseline, ", "), strings.Join(sorted, ", ")),
+					StartTime: startTime,
+					EndTime:   endTime,
+					Metadata:  metadata,
 				}
 			}
-			if len(caaStrings) > 0 {
-				recordsFound = true
-				message = fmt.Sprintf("CAA records: %s", strings.Join(caaStrings, "; "))
-			}
 		}
-	case "SOA":
-		// Use miekg/dns for SOA record lookup
-		msg := new(dns.Msg)
-		msg.SetQuestion(dns.Fqdn(cfg.Host), dns.TypeSOA)
+		metadata[metadataBaselineRecords] = sorted
+		d.baselines.Store(m.ID, sorted)
+	}
 
-		client := new(dns.Client)
-		client.Timeout = time.Duration(m.Timeout) * time.Second
+	message := answer.message
+	if cfg.DNSSEC {
+		message += " (DNSSEC validated)"
+	}
 
-		var resp *dns.Msg
-		resp, _, err = client.Exchange(msg, fmt.Sprintf("%s:%d", cfg.ResolverServer, cfg.Port))
+	d.logger.Infof("DNS lookup successful: %s, %s", m.Name, message)
 
-		if err == nil && resp != nil && len(resp.Answer) > 0 {
-			for _, ans := range resp.Answer {
-				if soa, ok := ans.(*dns.SOA); ok {
-					recordsFound = true
-					message = fmt.Sprintf("SOA: Primary NS: %s, Admin: %s, Serial: %d, Refresh: %d, Retry: %d, Expire: %d, Min TTL: %d",
-						soa.Ns, soa.Mbox, soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.Minttl)
-					break
-				}
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Metadata:  metadata,
+	}
+}
+
+// baseline returns the records the monitor's latest heartbeat was compared against, nil when
+// there is none or the monitor is not saved (dry runs)
+func (d *DNSExecutor) baseline(ctx context.Context, monitorID string) ([]string, error) {
+	if monitorID == "" || d.heartbeatService == nil {
+		return nil, nil
+	}
+
+	beats, err := d.heartbeatService.FindByMonitorIDPaginated(ctx, monitorID, 1, 0, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	if len(beats) == 0 {
+		return nil, nil
+	}
+
+	stored, ok := beats[0].Metadata[metadataBaselineRecords]
+	if !ok {
+		return nil, nil
+	}
+	// Stored metadata comes back as generic JSON or BSON arrays
+	raw, err := json.Marshal(stored)
+	if err != nil {
+		return nil, err
+	}
+	var records []string
+	if err := json.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", metadataBaselineRecords, err)
+	}
+	return records, nil
+}
+
+func dnsMetadata(cfg *DNSConfig, answers []*dnsAnswer, records []string) Metadata {
+	metadata := Metadata{
+		"record_type": strings.ToUpper(cfg.ResolveType),
+	}
+	if records != nil {
+		metadata[MetadataRecords] = records
+	}
+	if len(answers) > 1 {
+		perResolver := make(map[string]any, len(answers))
+		for _, answer := range answers {
+			if answer.err != nil {
+				perResolver[answer.resolver] = answer.err.Error()
+			} else {
+				perResolver[answer.resolver] = answer.records
+			}
+		}
+		metadata["resolver_records"] = perResolver
+	}
+	return metadata
+}
+
+// agreedAnswer picks the answer the resolvers agree on according to the consistency mode
+func agreedAnswer(answers []*dnsAnswer, consistency string) (*dnsAnswer, error) {
+	if len(answers) == 1 {
+		if answers[0].err != nil {
+			return nil, fmt.Errorf("DNS lookup failed: %v", answers[0].err)
+		}
+		return answers[0], nil
+	}
+
+	// Group the successful answers by their record set
+	var groups [][]*dnsAnswer
+	var failures []string
+	for _, answer := range answers {
+		if answer.err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", answer.resolver, answer.err))
+			continue
+		}
+		key := sortedRecords(answer.records)
+		found := false
+		for i, group := range groups {
+			if slices.Equal(sortedRecords(group[0].records), key) {
+				groups[i] = append(group, answer)
+				found = true
+				break
 			}
 		}
+		if !found {
+			groups = append(groups, []*dnsAnswer{answer})
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil, fmt.Errorf("DNS lookup failed on all resolvers: %s", strings.Join(failures, "; "))
+	}
+
+	largest := groups[0]
+	for _, group := range groups[1:] {
+		if len(group) > len(largest) {
+			largest = group
+		}
+	}
+
+	switch consistency {
+	case "any":
+		return largest[0], nil
+	case "majority":
+		if len(largest)*2 > len(answers) {
+			return largest[0], nil
+		}
 	default:
-		err = fmt.Errorf("unsupported record type: %s", cfg.ResolveType)
+		if len(failures) == 0 && len(groups) == 1 {
+			return largest[0], nil
+		}
 	}
 
-	endTime := time.Now().UTC()
+	return nil, fmt.Errorf("resolvers disagree: %s", describeAnswers(answers))
+}
 
-	if err != nil {
-		d.logger.Infof("DNS lookup failed: %s, %s", m.Name, err.Error())
-		return &Result{
-			Status:    shared.MonitorStatusDown,
-			Message:   fmt.Sprintf("DNS lookup failed: %v", err),
-			StartTime: startTime,
-			EndTime:   endTime,
+func describeAnswers(answers []*dnsAnswer) string {
+	parts := make([]string, len(answers))
+	for i, answer := range answers {
+		if answer.err != nil {
+			parts[i] = fmt.Sprintf("%s failed (%v)", answer.resolver, answer.err)
+		} else {
+			parts[i] = fmt.Sprintf("%s [%s]", answer.resolver, strings.Join(sortedRecords(answer.records), ", "))
 		}
 	}
+	return strings.Join(parts, ", ")
+}
 
-	if !recordsFound {
-		return &Result{
-			Status:    shared.MonitorStatusDown,
-			Message:   fmt.Sprintf("No %s records found for %s", cfg.ResolveType, cfg.Host),
-			StartTime: startTime,
-			EndTime:   endTime,
+// matchAnswers checks the records against the expected answers
+func matchAnswers(records, expected []string, mode string) error {
+	if len(expected) == 0 {
+		return nil
+	}
+
+	switch mode {
+	case "regex":
+		for _, pattern := range expected {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("invalid expected answer pattern %q: %w", pattern, err)
+			}
+			if !slices.ContainsFunc(records, re.MatchString) {
+				return fmt.Errorf("no record matches %q, got [%s]", pattern, strings.Join(records, ", "))
+			}
 		}
+		return nil
+	case "contains":
+		normalized := normalizeRecords(records)
+		for _, answer := range expected {
+			if !slices.Contains(normalized, normalizeRecord(answer)) {
+				return fmt.Errorf("expected answer %q not found, got [%s]", answer, strings.Join(records, ", "))
+			}
+		}
+		return nil
+	default:
+		got := sortedRecords(normalizeRecords(records))
+		want := sortedRecords(normalizeRecords(expected))
+		if !slices.Equal(slices.Compact(got), slices.Compact(want)) {
+			return fmt.Errorf("answers [%s] do not match expected [%s]", strings.Join(records, ", "), strings.Join(expected, ", "))
+		}
+		return nil
 	}
+}
 
-	d.logger.Infof("DNS lookup successful: %s, %s", m.Name, message)
+// normalizeRecord makes answers comparable regardless of case and the trailing dot of names
+func normalizeRecord(record string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(record)), ".")
+}
 
-	return &Result{
-		Status:    shared.MonitorStatusUp,
-		Message:   message,
-		StartTime: startTime,
-		EndTime:   endTime,
+func normalizeRecords(records []string) []string {
+	normalized := make([]string, len(records))
+	for i, record := range records {
+		normalized[i] = normalizeRecord(record)
 	}
+	return normalized
+}
+
+func sortedRecords(records []string) []string {
+	sorted := slices.Clone(records)
+	slices.Sort(sorted)
+	return sorted
 }
@@ -2,18 +2,33 @@ package executor
 
 import (
 	"context"
+	"crypto"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 )
 
 func TestDNSExecutor_Unmarshal(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
-	executor := NewDNSExecutor(logger)
+	executor := NewDNSExecutor(logger, nil)
 
 	tests := []struct {
 		name          string
@@ -69,7 +84,7 @@ func TestDNSExecutor_Unmarshal(t *testing.T) {
 func TestDNSExecutor_Validate(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
-	executor := NewDNSExecutor(logger)
+	executor := NewDNSExecutor(logger, nil)
 
 	tests := []struct {
 		name          string
@@ -191,7 +206,7 @@ func TestDNSExecutor_Validate(t *testing.T) {
 func TestDNSExecutor_Execute(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
-	executor := NewDNSExecutor(logger)
+	executor := NewDNSExecutor(logger, nil)
 
 	tests := []struct {
 		name           string
@@ -337,7 +352,7 @@ func TestDNSExecutor_Execute(t *testing.T) {
 func TestDNSExecutor_Execute_DifferentRecordTypes(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
-	executor := NewDNSExecutor(logger)
+	executor := NewDNSExecutor(logger, nil)
 
 	// Test different record types with a known domain
 	recordTypes := []string{"A", "AAAA", "MX", "NS", "TXT"}
@@ -372,7 +387,7 @@ func TestDNSExecutor_Execute_DifferentRecordTypes(t *testing.T) {
 func TestDNSExecutor_Execute_WithProxy(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
-	executor := NewDNSExecutor(logger)
+	executor := NewDNSExecutor(logger, nil)
 
 	monitor := &Monitor{
 		ID:       "monitor1",
@@ -409,9 +424,518 @@ func TestNewDNSExecutor(t *testing.T) {
 	logger := zap.NewNop().Sugar()
 
 	// Test executor creation
-	executor := NewDNSExecutor(logger)
+	executor := NewDNSExecutor(logger, nil)
 
 	// Verify executor is properly initialized
 	assert.NotNil(t, executor)
 	assert.NotNil(t, executor.logger)
 }
+
+// fakeResolver answers A, MX and TXT queries for example.test and serves a DNSSEC signed
+// A record for signed.test
+type fakeResolver struct {
+	mu      sync.Mutex
+	a       []string
+	signKey *dns.DNSKEY
+	signer  crypto.Signer
+}
+
+func newFakeResolver(t *testing.T, a ...string) *fakeResolver {
+	t.Helper()
+
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "signed.test.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 300},
+		Flags:     257,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	require.NoError(t, err)
+
+	return &fakeResolver{a: a, signKey: key, signer: priv.(crypto.Signer)}
+}
+
+func (f *fakeResolver) setA(a ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.a = a
+}
+
+func (f *fakeResolver) answer(req *dns.Msg) *dns.Msg {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	resp := new(dns.Msg)
+	resp.SetReply(req)
+	q := req.Question[0]
+	header := dns.RR_Header{Name: q.Name, Rrtype: q.Qtype, Class: dns.ClassINET, Ttl: 300}
+
+	switch {
+	case q.Name == "example.test." && q.Qtype == dns.TypeA:
+		for _, ip := range f.a {
+			resp.Answer = append(resp.Answer, &dns.A{Hdr: header, A: net.ParseIP(ip)})
+		}
+	case q.Name == "example.test." && q.Qtype == dns.TypeMX:
+		resp.Answer = append(resp.Answer, &dns.MX{Hdr: header, Mx: "mail.example.test.", Preference: 10})
+	case q.Name == "example.test." && q.Qtype == dns.TypeTXT:
+		resp.Answer = append(resp.Answer, &dns.TXT{Hdr: header, Txt: []string{"v=spf1 ", "-all"}})
+	case q.Name == "www.example.test." && q.Qtype == dns.TypeCNAME:
+		resp.Answer = append(resp.Answer, &dns.CNAME{Hdr: header, Target: "example.test."})
+	case q.Name == "signed.test." && q.Qtype == dns.TypeA:
+		a := &dns.A{Hdr: header, A: net.ParseIP("192.0.2.10")}
+		resp.Answer = append(resp.Answer, a, f.sign(a))
+		resp.AuthenticatedData = true
+	case q.Name == "signed.test." && q.Qtype == dns.TypeDNSKEY:
+		resp.Answer = append(resp.Answer, f.signKey, f.sign(f.signKey))
+		resp.AuthenticatedData = true
+	case q.Name == "unsigned.test." && q.Qtype == dns.TypeA:
+		resp.Answer = append(resp.Answer, &dns.A{Hdr: header, A: net.ParseIP("192.0.2.20")})
+		resp.AuthenticatedData = true
+	case strings.HasSuffix(q.Name, ".test."):
+		// NOERROR without records
+	default:
+		resp.Rcode = dns.RcodeNameError
+	}
+
+	return resp
+}
+
+func (f *fakeResolver) sign(rr dns.RR) *dns.RRSIG {
+	sig := &dns.RRSIG{
+		Hdr:         dns.RR_Header{Name: rr.Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 300},
+		TypeCovered: rr.Header().Rrtype,
+		Algorithm:   f.signKey.Algorithm,
+		Labels:      uint8(dns.CountLabel(rr.Header().Name)),
+		OrigTtl:     rr.Header().Ttl,
+		Expiration:  uint32(time.Now().Add(time.Hour).Unix()),
+		Inception:   uint32(time.Now().Add(-time.Hour).Unix()),
+		KeyTag:      f.signKey.KeyTag(),
+		SignerName:  "signed.test.",
+	}
+	if err := sig.Sign(f.signer, []dns.RR{rr}); err != nil {
+		panic(err)
+	}
+	return sig
+}
+
+func (f *fakeResolver) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	w.WriteMsg(f.answer(req))
+}
+
+// serveUDPAndTCP serves the resolver on ip:port over UDP and TCP, port 0 picks a free one
+func (f *fakeResolver) serveUDPAndTCP(t *testing.T, ip string, port int) (int, error) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", net.JoinHostPort(ip, strconv.Itoa(port)))
+	if err != nil {
+		return 0, err
+	}
+	port = listener.Addr().(*net.TCPAddr).Port
+	packetConn, err := net.ListenPacket("udp", net.JoinHostPort(ip, strconv.Itoa(port)))
+	if err != nil {
+		listener.Close()
+		return 0, err
+	}
+
+	udpServer := &dns.Server{PacketConn: packetConn, Handler: f}
+	tcpServer := &dns.Server{Listener: listener, Handler: f}
+	go udpServer.ActivateAndServe()
+	go tcpServer.ActivateAndServe()
+	t.Cleanup(func() {
+		udpServer.Shutdown()
+		tcpServer.Shutdown()
+	})
+
+	return port, nil
+}
+
+func executeDNS(t *testing.T, executor *DNSExecutor, id, config string) *Result {
+	t.Helper()
+	result := executor.Execute(context.Background(), &Monitor{
+		ID:      id,
+		Type:    "dns",
+		Name:    "Test DNS Monitor",
+		Timeout: 5,
+		Config:  config,
+	}, nil)
+	require.NotNil(t, result)
+	return result
+}
+
+func TestDNSExecutor_ValidateTransports(t *testing.T) {
+	executor := NewDNSExecutor(zap.NewNop().Sugar(), nil)
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "dns over https",
+			config:    `{"host":"example.com","resolver_server":"https://cloudflare-dns.com/dns-query","port":443,"resolve_type":"A","transport":"https"}`,
+			wantError: false,
+		},
+		{
+			name:      "dns over https with ip resolver",
+			config:    `{"host":"example.com","resolver_server":"1.1.1.1","port":443,"resolve_type":"A","transport":"https"}`,
+			wantError: true,
+		},
+		{
+			name:      "dns over tls with additional resolvers",
+			config:    `{"host":"example.com","resolver_server":"1.1.1.1","port":853,"resolve_type":"A","transport":"tls","additional_resolvers":["8.8.8.8"],"consistency":"majority"}`,
+			wantError: false,
+		},
+		{
+			name:      "additional resolver is not an ip",
+			config:    `{"host":"example.com","resolver_server":"1.1.1.1","port":53,"resolve_type":"A","additional_resolvers":["dns.google"]}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown transport",
+			config:    `{"host":"example.com","resolver_server":"1.1.1.1","port":53,"resolve_type":"A","transport":"quic"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid answer pattern",
+			config:    `{"host":"example.com","resolver_server":"1.1.1.1","port":53,"resolve_type":"A","expected_answers":["93.184.("],"answer_match":"regex"}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown consistency",
+			config:    `{"host":"example.com","resolver_server":"1.1.1.1","port":53,"resolve_type":"A","consistency":"quorum"}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDNSExecutor_ExpectedAnswers(t *testing.T) {
+	resolver := newFakeResolver(t, "192.0.2.1", "192.0.2.2")
+	port, err := resolver.serveUDPAndTCP(t, "127.0.0.1", 0)
+	require.NoError(t, err)
+
+	executor := NewDNSExecutor(zap.NewNop().Sugar(), nil)
+
+	tests := []struct {
+		name        string
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "exact match in any order",
+			config:      `{"host":"example.test","resolve_type":"A","expected_answers":["192.0.2.2","192.0.2.1"]}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "A records: 192.0.2.1, 192.0.2.2",
+		},
+		{
+			name:        "exact mismatch",
+			config:      `{"host":"example.test","resolve_type":"A","expected_answers":["192.0.2.1"]}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "answers [192.0.2.1, 192.0.2.2] do not match expected [192.0.2.1]",
+		},
+		{
+			name:        "contains",
+			config:      `{"host":"example.test","resolve_type":"A","expected_answers":["192.0.2.1"],"answer_match":"contains"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "A records",
+		},
+		{
+			name:        "contains missing answer",
+			config:      `{"host":"example.test","resolve_type":"A","expected_answers":["203.0.113.1"],"answer_match":"contains"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: `expected answer "203.0.113.1" not found`,
+		},
+		{
+			name:        "regex",
+			config:      `{"host":"example.test","resolve_type":"MX","expected_answers":["^mail\\.example\\.test\\."],"answer_match":"regex"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "MX records: mail.example.test. (priority: 10)",
+		},
+		{
+			name:        "names compare without trailing dot",
+			config:      `{"host":"www.example.test","resolve_type":"CNAME","expected_answers":["Example.Test"]}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "CNAME: example.test.",
+		},
+		{
+			name:        "txt strings are joined",
+			config:      `{"host":"example.test","resolve_type":"TXT","expected_answers":["v=spf1 -all"],"transport":"tcp"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "TXT records: v=spf1 -all",
+		},
+		{
+			name:        "no records",
+			config:      `{"host":"empty.test","resolve_type":"A"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "No A records found for empty.test",
+		},
+		{
+			name:        "nxdomain",
+			config:      `{"host":"missing.invalid","resolve_type":"A"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "DNS lookup failed: NXDOMAIN for missing.invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := strings.Replace(tt.config, "{", fmt.Sprintf(`{"resolver_server":"127.0.0.1","port":%d,`, port), 1)
+			result := executeDNS(t, executor, tt.name, config)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+		})
+	}
+}
+
+// dnsHeartbeatStore keeps the heartbeats of DNS checks, metadata goes through JSON like in the database
+type dnsHeartbeatStore struct {
+	heartbeat.Service
+	beats map[string][]*heartbeat.Model
+	err   error
+}
+
+func (d *dnsHeartbeatStore) record(t *testing.T, monitorID string, result *Result) {
+	t.Helper()
+	raw, err := json.Marshal(result.Metadata)
+	require.NoError(t, err)
+	var metadata shared.HeartBeatMetadata
+	require.NoError(t, json.Unmarshal(raw, &metadata))
+	// newest first
+	d.beats[monitorID] = append([]*heartbeat.Model{{MonitorID: monitorID, Status: result.Status, Metadata: metadata}}, d.beats[monitorID]...)
+}
+
+func (d *dnsHeartbeatStore) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	beats := d.beats[monitorID]
+	return beats[:min(limit, len(beats))], nil
+}
+
+func TestDNSExecutor_RecordChanges(t *testing.T) {
+	resolver := newFakeResolver(t, "192.0.2.1")
+	port, err := resolver.serveUDPAndTCP(t, "127.0.0.1", 0)
+	require.NoError(t, err)
+
+	store := &dnsHeartbeatStore{beats: map[string][]*heartbeat.Model{}}
+	executor := NewDNSExecutor(zap.NewNop().Sugar(), store)
+	config := fmt.Sprintf(`{"host":"example.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"A","alert_on_change":true}`, port)
+	check := func(id, config string) *Result {
+		result := executeDNS(t, executor, id, config)
+		if id != "" {
+			store.record(t, id, result)
+		}
+		return result
+	}
+
+	result := check("monitor1", config)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+
+	resolver.setA("203.0.113.66")
+	result = check("monitor1", config)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, "DNS records changed from [192.0.2.1] to [203.0.113.66]", result.Message)
+	assert.Equal(t, true, result.Metadata["records_changed"])
+
+	// The monitor stays down while the records differ from the baseline
+	result = check("monitor1", config)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, "DNS records changed from [192.0.2.1] to [203.0.113.66]", result.Message)
+
+	// A new executor, as after a restart, continues from the stored baseline
+	executor = NewDNSExecutor(zap.NewNop().Sugar(), store)
+	result = check("monitor1", config)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+
+	// Monitors keep their own baseline, dry runs record none
+	result = check("monitor2", config)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	result = check("", config)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.NotContains(t, result.Metadata, metadataBaselineRecords)
+
+	// Records going back to the baseline recover the monitor
+	resolver.setA("192.0.2.1")
+	result = check("monitor1", config)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+
+	// Checking once without the alert accepts the new records
+	resolver.setA("203.0.113.66")
+	result = check("monitor1", config)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	accept := strings.Replace(config, `"alert_on_change":true`, `"alert_on_change":false`, 1)
+	result = check("monitor1", accept)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, true, result.Metadata["records_changed"])
+	result = check("monitor1", config)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+}
+
+func TestDNSExecutor_BaselineLoadFailure(t *testing.T) {
+	resolver := newFakeResolver(t, "192.0.2.1")
+	port, err := resolver.serveUDPAndTCP(t, "127.0.0.1", 0)
+	require.NoError(t, err)
+
+	store := &dnsHeartbeatStore{beats: map[string][]*heartbeat.Model{}}
+	executor := NewDNSExecutor(zap.NewNop().Sugar(), store)
+	config := fmt.Sprintf(`{"host":"example.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"A","alert_on_change":true}`, port)
+	check := func(executor *DNSExecutor) *Result {
+		result := executeDNS(t, executor, "monitor1", config)
+		store.record(t, "monitor1", result)
+		return result
+	}
+
+	result := check(executor)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+
+	// The last known baseline is carried forward while heartbeats cannot be read
+	resolver.setA("203.0.113.66")
+	store.err = errors.New("database unavailable")
+	result = check(executor)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, "DNS records changed from [192.0.2.1] to [203.0.113.66]", result.Message)
+	assert.Equal(t, []string{"192.0.2.1"}, result.Metadata[metadataBaselineRecords])
+
+	// Without a known baseline the current records are not stored as the new one
+	result = check(NewDNSExecutor(zap.NewNop().Sugar(), store))
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.NotContains(t, result.Metadata, metadataBaselineRecords)
+
+	// Heartbeats written without the baseline fall back to the last known one
+	store.err = nil
+	result = check(executor)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, []string{"192.0.2.1"}, result.Metadata[metadataBaselineRecords])
+}
+
+func TestDNSExecutor_DNSSEC(t *testing.T) {
+	resolver := newFakeResolver(t)
+	port, err := resolver.serveUDPAndTCP(t, "127.0.0.1", 0)
+	require.NoError(t, err)
+
+	executor := NewDNSExecutor(zap.NewNop().Sugar(), nil)
+
+	result := executeDNS(t, executor, "signed", fmt.Sprintf(`{"host":"signed.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"A","dnssec":true}`, port))
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, "A records: 192.0.2.10 (DNSSEC validated)", result.Message)
+
+	result = executeDNS(t, executor, "unsigned", fmt.Sprintf(`{"host":"unsigned.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"A","dnssec":true}`, port))
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "DNSSEC validation failed: answer is not signed")
+
+	result = executeDNS(t, executor, "not-validated", fmt.Sprintf(`{"host":"example.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"MX","dnssec":true}`, port))
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "AD flag not set")
+}
+
+func TestDNSExecutor_EncryptedTransports(t *testing.T) {
+	resolver := newFakeResolver(t, "192.0.2.1")
+	cert := testCertificate()
+
+	// DNS-over-TLS
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	require.NoError(t, err)
+	dotServer := &dns.Server{Listener: listener, Net: "tcp-tls", Handler: resolver}
+	go dotServer.ActivateAndServe()
+	t.Cleanup(func() { dotServer.Shutdown() })
+	dotPort := listener.Addr().(*net.TCPAddr).Port
+
+	// DNS-over-HTTPS
+	dohServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		req := new(dns.Msg)
+		if r.Header.Get("Content-Type") != "application/dns-message" || req.Unpack(body) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		packed, _ := resolver.answer(req).Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(packed)
+	}))
+	defer dohServer.Close()
+
+	executor := NewDNSExecutor(zap.NewNop().Sugar(), nil)
+
+	tests := []struct {
+		name        string
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "dns over tls",
+			config:      fmt.Sprintf(`{"host":"example.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"A","transport":"tls","ignore_tls_errors":true}`, dotPort),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "A records: 192.0.2.1",
+		},
+		{
+			name:        "dns over tls with untrusted certificate",
+			config:      fmt.Sprintf(`{"host":"example.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"A","transport":"tls"}`, dotPort),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "DNS lookup failed",
+		},
+		{
+			name:        "dns over https",
+			config:      fmt.Sprintf(`{"host":"example.test","resolver_server":%q,"port":443,"resolve_type":"A","transport":"https","ignore_tls_errors":true}`, dohServer.URL+"/dns-query"),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "A records: 192.0.2.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executeDNS(t, executor, tt.name, tt.config)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+		})
+	}
+}
+
+func TestDNSExecutor_MultipleResolvers(t *testing.T) {
+	primary := newFakeResolver(t, "192.0.2.1")
+	port, err := primary.serveUDPAndTCP(t, "127.0.0.1", 0)
+	require.NoError(t, err)
+
+	secondary := newFakeResolver(t, "192.0.2.1")
+	if _, err := secondary.serveUDPAndTCP(t, "127.0.0.2", port); err != nil {
+		t.Skipf("cannot listen on 127.0.0.2: %v", err)
+	}
+	hijacked := newFakeResolver(t, "203.0.113.66")
+	if _, err := hijacked.serveUDPAndTCP(t, "127.0.0.3", port); err != nil {
+		t.Skipf("cannot listen on 127.0.0.3: %v", err)
+	}
+
+	executor := NewDNSExecutor(zap.NewNop().Sugar(), nil)
+	config := func(extra string) string {
+		return fmt.Sprintf(`{"host":"example.test","resolver_server":"127.0.0.1","port":%d,"resolve_type":"A",%s}`, port, extra)
+	}
+
+	result := executeDNS(t, executor, "agree", config(`"additional_resolvers":["127.0.0.2"]`))
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Len(t, result.Metadata["resolver_records"], 2)
+
+	result = executeDNS(t, executor, "disagree", config(`"additional_resolvers":["127.0.0.2","127.0.0.3"]`))
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "resolvers disagree: 127.0.0.1 [192.0.2.1], 127.0.0.2 [192.0.2.1], 127.0.0.3 [203.0.113.66]")
+
+	result = executeDNS(t, executor, "majority", config(`"additional_resolvers":["127.0.0.2","127.0.0.3"],"consistency":"majority"`))
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, []string{"192.0.2.1"}, result.Metadata[MetadataRecords])
+
+	result = executeDNS(t, executor, "no-majority", config(`"additional_resolvers":["127.0.0.3"],"consistency":"majority"`))
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+
+	result = executeDNS(t, executor, "any", config(`"additional_resolvers":["127.0.0.3"],"consistency":"any"`))
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+}
@@ -0,0 +1,310 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// dnsAnswer is the outcome of querying one resolver
+type dnsAnswer struct {
+	resolver string
+	records  []string
+	message  string
+	err      error
+}
+
+// queryDNS resolves the configured record through one resolver
+func queryDNS(ctx context.Context, cfg *DNSConfig, resolver string, timeout time.Duration) *dnsAnswer {
+	answer := &dnsAnswer{resolver: resolver}
+
+	qtype, ok := dns.StringToType[strings.ToUpper(cfg.ResolveType)]
+	if !ok {
+		answer.err = fmt.Errorf("unsupported record type: %s", cfg.ResolveType)
+		return answer
+	}
+
+	name := dns.Fqdn(cfg.Host)
+	if qtype == dns.TypePTR {
+		// PTR lookups take the IP address and query its reverse name
+		if reverse, err := dns.ReverseAddr(cfg.Host); err == nil {
+			name = reverse
+		}
+	}
+
+	ex := &dnsExchanger{cfg: cfg, resolver: resolver, timeout: timeout}
+	resp, err := ex.query(ctx, name, qtype)
+	if err != nil {
+		answer.err = err
+		return answer
+	}
+
+	if cfg.DNSSEC {
+		if err := ex.verifyDNSSEC(ctx, resp, qtype); err != nil {
+			answer.err = fmt.Errorf("DNSSEC validation failed: %w", err)
+			return answer
+		}
+	}
+
+	answer.records, answer.message = describeRecords(resp, qtype, name)
+	return answer
+}
+
+// describeRecords formats the answer records of the queried type and the check message
+func describeRecords(resp *dns.Msg, qtype uint16, name string) ([]string, string) {
+	var records []string
+	var soa *dns.SOA
+	canonical := ""
+
+	for _, rr := range resp.Answer {
+		switch record := rr.(type) {
+		case *dns.A:
+			if qtype == dns.TypeA {
+				records = append(records, record.A.String())
+			}
+		case *dns.AAAA:
+			if qtype == dns.TypeAAAA {
+				records = append(records, record.AAAA.String())
+			}
+		case *dns.CNAME:
+			canonical = record.Target
+		case *dns.MX:
+			records = append(records, fmt.Sprintf("%s (priority: %d)", record.Mx, record.Preference))
+		case *dns.NS:
+			records = append(records, record.Ns)
+		case *dns.TXT:
+			records = append(records, strings.Join(record.Txt, ""))
+		case *dns.PTR:
+			records = append(records, record.Ptr)
+		case *dns.SRV:
+			records = append(records, fmt.Sprintf("%s:%d (priority: %d, weight: %d)", record.Target, record.Port, record.Priority, record.Weight))
+		case *dns.CAA:
+			records = append(records, fmt.Sprintf("%d %s %q", record.Flag, record.Tag, record.Value))
+		case *dns.SOA:
+			if soa == nil {
+				soa = record
+				// Without the TTL, which counts down between checks and would look like a change
+				records = append(records, fmt.Sprintf("%s %s %d %d %d %d %d",
+					record.Ns, record.Mbox, record.Serial, record.Refresh, record.Retry, record.Expire, record.Minttl))
+			}
+		}
+	}
+
+	if qtype == dns.TypeCNAME {
+		// A name without alias is its own canonical name, like net.Resolver.LookupCNAME reports it
+		if canonical == "" && resp.Rcode == dns.RcodeSuccess {
+			canonical = name
+		}
+		if canonical == "" {
+			return nil, ""
+		}
+		return []string{canonical}, fmt.Sprintf("CNAME: %s", canonical)
+	}
+
+	if len(records) == 0 {
+		return nil, ""
+	}
+
+	typeName := dns.TypeToString[qtype]
+	switch qtype {
+	case dns.TypeSOA:
+		return records, fmt.Sprintf("SOA: Primary NS: %s, Admin: %s, Serial: %d, Refresh: %d, Retry: %d, Expire: %d, Min TTL: %d",
+			soa.Ns, soa.Mbox, soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.Minttl)
+	case dns.TypeTXT, dns.TypeCAA:
+		return records, fmt.Sprintf("%s records: %s", typeName, strings.Join(records, "; "))
+	default:
+		return records, fmt.Sprintf("%s records: %s", typeName, strings.Join(records, ", "))
+	}
+}
+
+// dnsExchanger sends queries to one resolver over the configured transport
+type dnsExchanger struct {
+	cfg      *DNSConfig
+	resolver string
+	timeout  time.Duration
+}
+
+func (e *dnsExchanger) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(name, qtype)
+	if e.cfg.DNSSEC {
+		msg.SetEdns0(4096, true)
+		msg.AuthenticatedData = true
+	}
+
+	resp, err := e.exchange(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, fmt.Errorf("%s for %s", dns.RcodeToString[resp.Rcode], strings.TrimSuffix(name, "."))
+	}
+	return resp, nil
+}
+
+func (e *dnsExchanger) exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         e.cfg.TLSServerName,
+		InsecureSkipVerify: e.cfg.IgnoreTlsErrors,
+	}
+
+	if e.cfg.Transport == "https" {
+		return e.exchangeHTTPS(ctx, msg, tlsConfig)
+	}
+
+	address := net.JoinHostPort(e.resolver, strconv.Itoa(e.cfg.Port))
+	client := &dns.Client{Net: "udp", Timeout: e.timeout}
+	switch e.cfg.Transport {
+	case "tcp":
+		client.Net = "tcp"
+	case "tls":
+		client.Net = "tcp-tls"
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName = e.resolver
+		}
+		client.TLSConfig = tlsConfig
+	}
+
+	resp, _, err := client.ExchangeContext(ctx, msg, address)
+	if err != nil {
+		return nil, err
+	}
+
+	// Truncated UDP answers are retried over TCP, like the system resolver does
+	if resp.Truncated && client.Net == "udp" {
+		client.Net = "tcp"
+		resp, _, err = client.ExchangeContext(ctx, msg, address)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// exchangeHTTPS sends the query as a DNS-over-HTTPS POST request (RFC 8484)
+func (e *dnsExchanger) exchangeHTTPS(ctx context.Context, msg *dns.Msg, tlsConfig *tls.Config) (*dns.Msg, error) {
+	// The ID is always 0 so responses can be cached by HTTP caches
+	query := msg.Copy()
+	query.Id = 0
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack DNS query: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.resolver, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	client := &http.Client{
+		Timeout:   e.timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("DoH server returned HTTP %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, err
+	}
+
+	answer := new(dns.Msg)
+	if err := answer.Unpack(body); err != nil {
+		return nil, fmt.Errorf("failed to parse DoH response: %w", err)
+	}
+	return answer, nil
+}
+
+// verifyDNSSEC requires the resolver to have validated the answer and checks the signatures
+// of the answer against the keys of the signing zone
+func (e *dnsExchanger) verifyDNSSEC(ctx context.Context, resp *dns.Msg, qtype uint16) error {
+	if !resp.AuthenticatedData {
+		return errors.New("resolver did not authenticate the answer (AD flag not set)")
+	}
+
+	var signatures []*dns.RRSIG
+	for _, rr := range resp.Answer {
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			signatures = append(signatures, sig)
+		}
+	}
+	if len(signatures) == 0 {
+		return errors.New("answer is not signed")
+	}
+
+	keys := make(map[string][]*dns.DNSKEY)
+	for _, sig := range signatures {
+		rrset := rrsetCoveredBy(resp.Answer, sig)
+		if len(rrset) == 0 {
+			continue
+		}
+
+		zoneKeys, ok := keys[sig.SignerName]
+		if !ok {
+			keyResp, err := e.query(ctx, sig.SignerName, dns.TypeDNSKEY)
+			if err != nil {
+				return fmt.Errorf("failed to fetch DNSKEY of %s: %w", sig.SignerName, err)
+			}
+			for _, rr := range keyResp.Answer {
+				if key, ok := rr.(*dns.DNSKEY); ok {
+					zoneKeys = append(zoneKeys, key)
+				}
+			}
+			keys[sig.SignerName] = zoneKeys
+		}
+
+		if err := verifySignature(sig, rrset, zoneKeys); err != nil {
+			return fmt.Errorf("%s %s: %w", dns.TypeToString[sig.TypeCovered], sig.Header().Name, err)
+		}
+	}
+
+	return nil
+}
+
+// rrsetCoveredBy returns the records of the answer the signature applies to
+func rrsetCoveredBy(answer []dns.RR, sig *dns.RRSIG) []dns.RR {
+	var rrset []dns.RR
+	for _, rr := range answer {
+		header := rr.Header()
+		if header.Rrtype == sig.TypeCovered && strings.EqualFold(header.Name, sig.Header().Name) {
+			rrset = append(rrset, rr)
+		}
+	}
+	return rrset
+}
+
+func verifySignature(sig *dns.RRSIG, rrset []dns.RR, keys []*dns.DNSKEY) error {
+	if !sig.ValidityPeriod(time.Now()) {
+		return errors.New("signature expired or not yet valid")
+	}
+	for _, key := range keys {
+		if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm {
+			continue
+		}
+		if err := sig.Verify(key, rrset); err != nil {
+			return fmt.Errorf("invalid signature: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("no DNSKEY with tag %d", sig.KeyTag)
+}
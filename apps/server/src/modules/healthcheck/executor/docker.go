@@ -148,6 +148,14 @@ func (e *DockerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Pr
 
 	endTime := time.Now().UTC()
 
+	var metadata Metadata
+	if container.State != nil {
+		metadata = Metadata{MetadataContainerState: container.State.Status}
+		if container.State.Health != nil {
+			metadata["health_status"] = container.State.Health.Status
+		}
+	}
+
 	if container.State != nil && container.State.Running {
 		if container.State.Health != nil && container.State.Health.Status != "healthy" {
 			// Handle different health statuses appropriately
@@ -158,12 +166,17 @@ func (e *DockerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Pr
 					Message:   container.State.Health.Status,
 					StartTime: start,
 					EndTime:   endTime,
+					Metadata:  metadata,
 				}
 			case "unhealthy":
-				return DownResult(fmt.Errorf("container is unhealthy: %s", container.State.Health.Status), start, endTime)
+				result := DownResult(fmt.Errorf("container is unhealthy: %s", container.State.Health.Status), start, endTime)
+				result.Metadata = metadata
+				return result
 			default:
 				// For any other non-healthy status, consider it down
-				return DownResult(fmt.Errorf("container health status: %s", container.State.Health.Status), start, endTime)
+				result := DownResult(fmt.Errorf("container health status: %s", container.State.Health.Status), start, endTime)
+				result.Metadata = metadata
+				return result
 			}
 		}
 		var message string
@@ -177,6 +190,7 @@ func (e *DockerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Pr
 			Message:   message,
 			StartTime: start,
 			EndTime:   endTime,
+			Metadata:  metadata,
 		}
 	}
 
@@ -184,5 +198,7 @@ func (e *DockerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Pr
 		return DownResult(fmt.Errorf("container state is nil"), start, endTime)
 	}
 
-	return DownResult(fmt.Errorf("container state is %s", container.State.Status), start, endTime)
+	result := DownResult(fmt.Errorf("container state is %s", container.State.Status), start, endTime)
+	result.Metadata = metadata
+	return result
 }
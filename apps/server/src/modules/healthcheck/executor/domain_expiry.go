@@ -0,0 +1,169 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/shared"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/idna"
+)
+
+type DomainExpiryConfig struct {
+	Domain string `json:"domain" validate:"required" example:"example.com"`
+	// RDAPBaseURL replaces the RDAP server found in the IANA bootstrap registry
+	RDAPBaseURL string `json:"rdap_base_url" validate:"omitempty,url" example:"https://rdap.verisign.com/com/v1/"`
+	// WhoisServer is queried when RDAP fails, by default the server is looked up at whois.iana.org
+	WhoisServer string `json:"whois_server" validate:"omitempty,hostname_port|hostname" example:"whois.verisign-grs.com"`
+	// NotifyDays are the days before the expiry notifications are sent at, the global setting
+	// is used when empty
+	NotifyDays []int `json:"notify_days" validate:"omitempty,dive,min=1" example:"30,14,7"`
+}
+
+// domainHoldStatuses are the status codes of domains removed from the DNS
+var domainHoldStatuses = []string{
+	"client hold",
+	"server hold",
+	"redemption period",
+	"pending delete",
+	"inactive",
+}
+
+type DomainExpiryExecutor struct {
+	logger *zap.SugaredLogger
+	// rdapServices caches the IANA RDAP bootstrap registry, it rarely changes
+	rdapMu       sync.Mutex
+	rdapServices map[string][]string
+	rdapLoadedAt time.Time
+}
+
+func NewDomainExpiryExecutor(logger *zap.SugaredLogger) *DomainExpiryExecutor {
+	return &DomainExpiryExecutor{
+		logger: logger,
+	}
+}
+
+func (s *DomainExpiryExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[DomainExpiryConfig](configJSON)
+}
+
+func (s *DomainExpiryExecutor) Validate(configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*DomainExpiryConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	domain, err := normalizeDomain(cfg.Domain)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(domain, ".") {
+		return fmt.Errorf("domain %q must include a top-level domain", cfg.Domain)
+	}
+	return nil
+}
+
+func (d *DomainExpiryExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := d.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*DomainExpiryConfig)
+
+	d.logger.Debugf("execute domain-expiry cfg: %+v", cfg)
+
+	domain, err := normalizeDomain(cfg.Domain)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now().UTC()
+	info, err := d.lookup(ctx, cfg, domain)
+	endTime := time.Now().UTC()
+
+	if err != nil {
+		d.logger.Infof("Domain lookup failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("domain lookup failed: %w", err), startTime, endTime)
+	}
+
+	info.DaysRemaining = certificate.CalculateDaysRemaining(info.ExpiresAt)
+
+	metadata := Metadata{
+		"expires_at":     info.ExpiresAt.Format(time.RFC3339),
+		"days_remaining": info.DaysRemaining,
+		"source":         info.Source,
+	}
+	if info.Registrar != "" {
+		metadata["registrar"] = info.Registrar
+	}
+	if len(info.Status) > 0 {
+		metadata["status"] = info.Status
+	}
+
+	result := &Result{
+		Status:     shared.MonitorStatusUp,
+		StartTime:  startTime,
+		EndTime:    endTime,
+		DomainInfo: info,
+		Metadata:   metadata,
+	}
+
+	hold := slices.IndexFunc(info.Status, func(status string) bool {
+		return slices.Contains(domainHoldStatuses, status)
+	})
+
+	switch {
+	case info.ExpiresAt.Before(time.Now()):
+		result.Status = shared.MonitorStatusDown
+		result.Message = fmt.Sprintf("Domain %s expired on %s", domain, info.ExpiresAt.Format(time.DateOnly))
+	case hold >= 0:
+		result.Status = shared.MonitorStatusDown
+		result.Message = fmt.Sprintf("Domain %s has status %q", domain, info.Status[hold])
+	default:
+		result.Message = fmt.Sprintf("Domain %s expires in %d days (%s)", domain, info.DaysRemaining, info.ExpiresAt.Format(time.DateOnly))
+		if info.Registrar != "" {
+			result.Message += fmt.Sprintf(", registrar: %s", info.Registrar)
+		}
+	}
+
+	d.logger.Infof("Domain lookup: %s, %s", m.Name, result.Message)
+	return result
+}
+
+// lookup asks RDAP for the registration data and falls back to WHOIS for registries
+// without RDAP service
+func (d *DomainExpiryExecutor) lookup(ctx context.Context, cfg *DomainExpiryConfig, domain string) (*shared.DomainInfo, error) {
+	info, rdapErr := d.lookupRDAP(ctx, cfg.RDAPBaseURL, domain)
+	if rdapErr == nil {
+		return info, nil
+	}
+	d.logger.Debugf("RDAP lookup of %s failed, trying WHOIS: %v", domain, rdapErr)
+
+	info, whoisErr := lookupWhois(ctx, cfg.WhoisServer, domain)
+	if whoisErr != nil {
+		return nil, fmt.Errorf("RDAP: %v, WHOIS: %v", rdapErr, whoisErr)
+	}
+	return info, nil
+}
+
+// normalizeDomain converts the domain to its lowercase ASCII form without trailing dot
+func normalizeDomain(domain string) (string, error) {
+	ascii, err := idna.Lookup.ToASCII(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+	if err != nil {
+		return "", fmt.Errorf("invalid domain %q: %w", domain, err)
+	}
+	return ascii, nil
+}
@@ -0,0 +1,253 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// startRDAPServer serves domain objects with the given expiry, unknown domains are not found
+func startRDAPServer(t *testing.T, expires time.Time, status string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domain/example.com" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		fmt.Fprintf(w, `{
+			"objectClassName": "domain",
+			"ldhName": "EXAMPLE.COM",
+			"status": [%q, "client transfer prohibited"],
+			"events": [
+				{"eventAction": "registration", "eventDate": "1995-08-14T04:00:00Z"},
+				{"eventAction": "expiration", "eventDate": %q}
+			],
+			"entities": [{
+				"objectClassName": "entity",
+				"roles": ["registrar"],
+				"vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]]
+			}]
+		}`, status, expires.Format(time.RFC3339))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// startWhoisServer answers every query with response and returns its address
+func startWhoisServer(t *testing.T, response string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			bufio.NewReader(conn).ReadString('\n')
+			fmt.Fprint(conn, response)
+			conn.Close()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestDomainExpiryExecutor_Validate(t *testing.T) {
+	executor := NewDomainExpiryExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid domain",
+			config:    `{"domain":"example.com"}`,
+			wantError: false,
+		},
+		{
+			name:      "valid with servers and thresholds",
+			config:    `{"domain":"example.com","rdap_base_url":"http://127.0.0.1:8080/rdap","whois_server":"127.0.0.1:4343","notify_days":[30,7]}`,
+			wantError: false,
+		},
+		{
+			name:      "internationalized domain",
+			config:    `{"domain":"bücher.de"}`,
+			wantError: false,
+		},
+		{
+			name:      "missing domain",
+			config:    `{}`,
+			wantError: true,
+		},
+		{
+			name:      "no top-level domain",
+			config:    `{"domain":"localhost"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid rdap url",
+			config:    `{"domain":"example.com","rdap_base_url":"not a url"}`,
+			wantError: true,
+		},
+		{
+			name:      "zero threshold",
+			config:    `{"domain":"example.com","notify_days":[0]}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDomainExpiryExecutor_ExecuteRDAP(t *testing.T) {
+	executor := NewDomainExpiryExecutor(zap.NewNop().Sugar())
+	expires := time.Now().Add(90*24*time.Hour + time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		expires     time.Time
+		status      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "registered",
+			expires:     expires,
+			status:      "active",
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "Domain example.com expires in 90 days",
+		},
+		{
+			name:        "expired",
+			expires:     time.Now().Add(-48 * time.Hour),
+			status:      "active",
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "expired on",
+		},
+		{
+			name:        "on hold",
+			expires:     expires,
+			status:      "Client Hold",
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: `has status "client hold"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startRDAPServer(t, tt.expires, tt.status)
+			result := executor.Execute(context.Background(), &Monitor{
+				ID:      "domain-monitor",
+				Type:    "domain-expiry",
+				Name:    "Domain Monitor",
+				Timeout: 5,
+				Config:  fmt.Sprintf(`{"domain":"Example.COM.","rdap_base_url":%q}`, server.URL+"/"),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+
+			require.NotNil(t, result.DomainInfo)
+			assert.Equal(t, "example.com", result.DomainInfo.Domain)
+			assert.Equal(t, "rdap", result.DomainInfo.Source)
+			assert.Equal(t, "Example Registrar, Inc.", result.DomainInfo.Registrar)
+			assert.True(t, tt.expires.Truncate(time.Second).Equal(result.DomainInfo.ExpiresAt))
+			assert.Equal(t, result.DomainInfo.DaysRemaining, result.Metadata["days_remaining"])
+			assert.Contains(t, result.DomainInfo.Status, "client transfer prohibited")
+		})
+	}
+}
+
+func TestDomainExpiryExecutor_ExecuteWhoisFallback(t *testing.T) {
+	// The RDAP server does not know the domain, the WHOIS server does
+	rdap := startRDAPServer(t, time.Now(), "active")
+	whois := startWhoisServer(t, "Domain Name: EXAMPLE.ORG\r\n"+
+		"Registrar: Example Registrar, Inc.\r\n"+
+		"Registry Expiry Date: 2099-08-13T04:00:00Z\r\n"+
+		"Domain Status: clientDeleteProhibited https://icann.org/epp#clientDeleteProhibited\r\n"+
+		"Domain Status: serverTransferProhibited https://icann.org/epp#serverTransferProhibited\r\n"+
+		">>> Last update of WHOIS database: 2025-08-10T12:00:00Z <<<\r\n")
+
+	executor := NewDomainExpiryExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "domain-expiry",
+		Name:    "Domain Monitor",
+		Timeout: 5,
+		Config:  fmt.Sprintf(`{"domain":"example.org","rdap_base_url":%q,"whois_server":%q}`, rdap.URL, whois),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	require.NotNil(t, result.DomainInfo)
+	assert.Equal(t, "whois", result.DomainInfo.Source)
+	assert.Equal(t, "Example Registrar, Inc.", result.DomainInfo.Registrar)
+	assert.Equal(t, time.Date(2099, 8, 13, 4, 0, 0, 0, time.UTC), result.DomainInfo.ExpiresAt)
+	assert.Equal(t, []string{"client delete prohibited", "server transfer prohibited"}, result.DomainInfo.Status)
+}
+
+func TestDomainExpiryExecutor_ExecuteNotFound(t *testing.T) {
+	rdap := startRDAPServer(t, time.Now(), "active")
+	whois := startWhoisServer(t, "No match for \"UNKNOWN.ORG\".\r\n")
+
+	executor := NewDomainExpiryExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "domain-expiry",
+		Name:    "Domain Monitor",
+		Timeout: 5,
+		Config:  fmt.Sprintf(`{"domain":"unknown.org","rdap_base_url":%q,"whois_server":%q}`, rdap.URL, whois),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, "domain lookup failed: RDAP: domain not found, WHOIS: domain not found", result.Message)
+	assert.Nil(t, result.DomainInfo)
+}
+
+func TestParseWhoisDate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2026-03-01T00:00:00Z", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026-03-01T10:20:30.0Z", time.Date(2026, 3, 1, 10, 20, 30, 0, time.UTC)},
+		{"2026-03-01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"2026.03.01", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"01-Mar-2026", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		got, err := parseWhoisDate(tt.value)
+		require.NoError(t, err, tt.value)
+		assert.Equal(t, tt.want, got, tt.value)
+	}
+
+	_, err := parseWhoisDate("soon")
+	assert.Error(t, err)
+}
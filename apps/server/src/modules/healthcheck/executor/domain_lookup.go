@@ -0,0 +1,316 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"peekaping/src/modules/shared"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// rdapBootstrapURL lists the RDAP servers of every top-level domain (RFC 9224)
+	rdapBootstrapURL = "https://data.iana.org/rdap/dns.json"
+	rdapBootstrapTTL = 24 * time.Hour
+
+	// whoisIANA refers to the WHOIS server of every top-level domain
+	whoisIANA = "whois.iana.org"
+
+	// whoisMaxResponse bounds the size of WHOIS and RDAP answers
+	whoisMaxResponse = 1 << 20
+)
+
+// rdapDomain is the part of an RDAP domain object (RFC 9083) the monitor reads
+type rdapDomain struct {
+	Status []string `json:"status"`
+	Events []struct {
+		EventAction string    `json:"eventAction"`
+		EventDate   time.Time `json:"eventDate"`
+	} `json:"events"`
+	Entities []struct {
+		Roles      []string          `json:"roles"`
+		VCardArray []json.RawMessage `json:"vcardArray"`
+	} `json:"entities"`
+}
+
+// lookupRDAP fetches the domain from the given RDAP server or the one of its top-level domain
+func (d *DomainExpiryExecutor) lookupRDAP(ctx context.Context, baseURL, domain string) (*shared.DomainInfo, error) {
+	if baseURL == "" {
+		var err error
+		baseURL, err = d.rdapServer(ctx, domain)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var object rdapDomain
+	if err := getJSON(ctx, strings.TrimSuffix(baseURL, "/")+"/domain/"+domain, "application/rdap+json", &object); err != nil {
+		return nil, err
+	}
+
+	info := &shared.DomainInfo{Domain: domain, Source: "rdap"}
+	for _, event := range object.Events {
+		if event.EventAction == "expiration" {
+			info.ExpiresAt = event.EventDate.UTC()
+		}
+	}
+	if info.ExpiresAt.IsZero() {
+		return nil, errors.New("RDAP response has no expiration event")
+	}
+
+	for _, status := range object.Status {
+		info.Status = append(info.Status, strings.ToLower(status))
+	}
+	for _, entity := range object.Entities {
+		if slices.Contains(entity.Roles, "registrar") {
+			info.Registrar = vcardName(entity.VCardArray)
+			break
+		}
+	}
+
+	return info, nil
+}
+
+// rdapServer finds the RDAP server of the domain in the bootstrap registry, the longest
+// matching suffix wins
+func (d *DomainExpiryExecutor) rdapServer(ctx context.Context, domain string) (string, error) {
+	d.rdapMu.Lock()
+	defer d.rdapMu.Unlock()
+
+	if d.rdapServices == nil || time.Since(d.rdapLoadedAt) > rdapBootstrapTTL {
+		var registry struct {
+			Services [][][]string `json:"services"`
+		}
+		if err := getJSON(ctx, rdapBootstrapURL, "application/json", &registry); err != nil {
+			return "", fmt.Errorf("failed to load RDAP bootstrap registry: %w", err)
+		}
+
+		services := make(map[string][]string)
+		for _, service := range registry.Services {
+			if len(service) != 2 {
+				continue
+			}
+			for _, tld := range service[0] {
+				services[strings.ToLower(tld)] = service[1]
+			}
+		}
+		d.rdapServices = services
+		d.rdapLoadedAt = time.Now()
+	}
+
+	labels := strings.Split(domain, ".")
+	for i := 1; i < len(labels); i++ {
+		urls := d.rdapServices[strings.Join(labels[i:], ".")]
+		// Prefer https, the registry lists plain http servers as well
+		for _, u := range urls {
+			if strings.HasPrefix(u, "https://") {
+				return u, nil
+			}
+		}
+		if len(urls) > 0 {
+			return urls[0], nil
+		}
+	}
+
+	return "", fmt.Errorf("no RDAP server for %s", domain)
+}
+
+func getJSON(ctx context.Context, url, accept string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errors.New("domain not found")
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%s returned HTTP %d", req.URL.Host, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, whoisMaxResponse)).Decode(target); err != nil {
+		return fmt.Errorf("invalid response from %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
+
+// vcardName returns the formatted name of a jCard (RFC 7095)
+func vcardName(vcard []json.RawMessage) string {
+	if len(vcard) != 2 {
+		return ""
+	}
+	var properties [][]any
+	if err := json.Unmarshal(vcard[1], &properties); err != nil {
+		return ""
+	}
+	for _, property := range properties {
+		if len(property) >= 4 && property[0] == "fn" {
+			if name, ok := property[3].(string); ok {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
+// lookupWhois queries the WHOIS server of the domain, found through whois.iana.org unless given
+func lookupWhois(ctx context.Context, server, domain string) (*shared.DomainInfo, error) {
+	if server == "" {
+		tld := domain[strings.LastIndex(domain, ".")+1:]
+		referral, err := queryWhois(ctx, whoisIANA, tld)
+		if err != nil {
+			return nil, err
+		}
+		server = whoisField(referral, "whois", "refer")
+		if server == "" {
+			return nil, fmt.Errorf("no WHOIS server for .%s", tld)
+		}
+	}
+
+	response, err := queryWhois(ctx, server, domain)
+	if err != nil {
+		return nil, err
+	}
+	return parseWhois(domain, response)
+}
+
+func queryWhois(ctx context.Context, server, query string) (string, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "43")
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return "", err
+	}
+
+	response, err := io.ReadAll(io.LimitReader(conn, whoisMaxResponse))
+	if err != nil {
+		return "", fmt.Errorf("failed to read WHOIS response from %s: %w", server, err)
+	}
+	return string(response), nil
+}
+
+var (
+	whoisExpiryFields    = []string{"registry expiry date", "registrar registration expiration date", "expiration date", "expiry date", "expire date", "expires", "expires on", "paid-till", "renewal date"}
+	whoisRegistrarFields = []string{"registrar", "sponsoring registrar", "registrar name"}
+	whoisStatusFields    = []string{"domain status", "status", "state"}
+	whoisNotFound        = []string{"no match", "not found", "no data found", "no entries found", "status: free", "status: available"}
+	whoisDateLayouts     = []string{
+		time.RFC3339,
+		"2006-01-02T15:04:05",
+		"2006-01-02 15:04:05 MST",
+		"2006-01-02 15:04:05",
+		"2006-01-02",
+		"2006.01.02",
+		"2006/01/02",
+		"02-Jan-2006",
+		"02.01.2006",
+		"January 2 2006",
+	}
+)
+
+// parseWhois reads the expiry date, registrar and status codes of a WHOIS response
+func parseWhois(domain, response string) (*shared.DomainInfo, error) {
+	info := &shared.DomainInfo{Domain: domain, Source: "whois"}
+
+	expiry := whoisField(response, whoisExpiryFields...)
+	if expiry == "" {
+		lower := strings.ToLower(response)
+		for _, marker := range whoisNotFound {
+			if strings.Contains(lower, marker) {
+				return nil, errors.New("domain not found")
+			}
+		}
+		return nil, errors.New("WHOIS response has no expiry date")
+	}
+	expiresAt, err := parseWhoisDate(expiry)
+	if err != nil {
+		return nil, err
+	}
+	info.ExpiresAt = expiresAt
+	info.Registrar = whoisField(response, whoisRegistrarFields...)
+
+	for _, value := range whoisFields(response, whoisStatusFields...) {
+		// "clientTransferProhibited https://icann.org/epp#clientTransferProhibited"
+		if fields := strings.Fields(value); len(fields) > 0 {
+			info.Status = append(info.Status, eppStatus(fields[0]))
+		}
+	}
+
+	return info, nil
+}
+
+// whoisField returns the first value of any of the keys
+func whoisField(response string, keys ...string) string {
+	for _, key := range keys {
+		if values := whoisFields(response, key); len(values) > 0 {
+			return values[0]
+		}
+	}
+	return ""
+}
+
+// whoisFields returns all non-empty values of the "key: value" lines with one of the keys
+func whoisFields(response string, keys ...string) []string {
+	var values []string
+	scanner := bufio.NewScanner(strings.NewReader(response))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if value != "" && slices.Contains(keys, strings.ToLower(strings.TrimSpace(key))) {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func parseWhoisDate(value string) (time.Time, error) {
+	for _, layout := range whoisDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown WHOIS date format: %q", value)
+}
+
+// eppStatus spells a WHOIS status like RDAP does, "clientTransferProhibited" becomes
+// "client transfer prohibited"
+func eppStatus(status string) string {
+	var b strings.Builder
+	for i, r := range status {
+		if unicode.IsUpper(r) && i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"peekaping/src/modules/certificate"
 	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_tag"
 	"peekaping/src/modules/shared"
 	"time"
 
@@ -12,13 +13,30 @@ import (
 )
 
 type Result struct {
-	Status    heartbeat.MonitorStatus
-	Message   string
-	StartTime time.Time
-	EndTime   time.Time
-	TLSInfo   *certificate.TLSInfo `json:"tls_info,omitempty"`
+	Status     heartbeat.MonitorStatus `json:"status"`
+	Message    string                  `json:"message"`
+	StartTime  time.Time               `json:"start_time"`
+	EndTime    time.Time               `json:"end_time"`
+	TLSInfo    *certificate.TLSInfo    `json:"tls_info,omitempty"`
+	DomainInfo *shared.DomainInfo      `json:"domain_info,omitempty"`
+	Metadata   Metadata                `json:"metadata,omitempty"`
 }
 
+// Metadata carries structured details of a check next to the free-text message
+type Metadata = shared.HeartBeatMetadata
+
+// Metadata keys shared by several executors
+const (
+	MetadataStatusCode     = "status_code"
+	MetadataResponseSize   = "response_size"
+	MetadataRecords        = "records"
+	MetadataRows           = "rows"
+	MetadataContainerState = "container_state"
+	// MetadataResponseTimeMs is a response time measured by the check itself, reported instead
+	// of the time the check took
+	MetadataResponseTimeMs = "response_time_ms"
+)
+
 type Monitor = shared.Monitor
 type Proxy = shared.Proxy
 
@@ -49,20 +67,30 @@ type Executor interface {
 type ExecutorRegistry struct {
 	logger   *zap.SugaredLogger
 	registry map[string]Executor
+	plugins  []*PluginDescriptor
 }
 
-func NewExecutorRegistry(logger *zap.SugaredLogger, heartbeatService heartbeat.Service) *ExecutorRegistry {
+func NewExecutorRegistry(
+	logger *zap.SugaredLogger,
+	heartbeatService heartbeat.Service,
+	monitorTagService monitor_tag.Service,
+) *ExecutorRegistry {
 	registry := make(map[string]Executor)
 
 	registry["http"] = NewHTTPExecutor(logger)
 	registry["http-keyword"] = NewHTTPExecutor(logger)
 	registry["http-json-query"] = NewHTTPExecutor(logger)
+	registry["http-steps"] = NewHTTPStepsExecutor(logger)
 	registry["push"] = NewPushExecutor(logger, heartbeatService)
 	registry["tcp"] = NewTCPExecutor(logger)
+	registry["udp"] = NewUDPExecutor(logger)
 	registry["ping"] = NewPingExecutor(logger)
-	registry["dns"] = NewDNSExecutor(logger)
+	registry["dns"] = NewDNSExecutor(logger, heartbeatService)
+	registry["ntp"] = NewNTPExecutor(logger)
 	registry["docker"] = NewDockerExecutor(logger)
 	registry["grpc-keyword"] = NewGRPCExecutor(logger)
+	registry["grpc-health"] = NewGRPCHealthExecutor(logger)
+	registry["websocket"] = NewWebSocketExecutor(logger)
 	registry["snmp"] = NewSnmpExecutor(logger)
 	registry["mongodb"] = NewMongoDBExecutor(logger)
 	registry["mysql"] = NewMySQLExecutor(logger)
@@ -72,6 +100,13 @@ func NewExecutorRegistry(logger *zap.SugaredLogger, heartbeatService heartbeat.S
 	registry["mqtt"] = NewMQTTExecutor(logger)
 	registry["rabbitmq"] = NewRabbitMQExecutor(logger)
 	registry["kafka-producer"] = NewKafkaProducerExecutor(logger)
+	registry["kafka-consumer"] = NewKafkaConsumerExecutor(logger)
+	registry["mail"] = NewMailExecutor(logger)
+	registry["tls"] = NewTLSExecutor(logger)
+	registry["ldap"] = NewLDAPExecutor(logger)
+	registry["ssh"] = NewSSHExecutor(logger)
+	registry["domain-expiry"] = NewDomainExpiryExecutor(logger)
+	registry["group"] = NewGroupExecutor(logger, heartbeatService, monitorTagService)
 
 	return &ExecutorRegistry{
 		registry: registry,
@@ -79,9 +114,11 @@ func NewExecutorRegistry(logger *zap.SugaredLogger, heartbeatService heartbeat.S
 	}
 }
 
-// func (f *ExecutorRegistry) RegisterExecutor(name string, executor Executor) {
-// 	f.registry[name] = executor
-// }
+// RegisterExecutor adds a monitor type, the registry is not safe for registrations once
+// checks are running
+func (f *ExecutorRegistry) RegisterExecutor(name string, executor Executor) {
+	f.registry[name] = executor
+}
 
 func (f *ExecutorRegistry) GetExecutor(name string) (Executor, bool) {
 	e, ok := f.registry[name]
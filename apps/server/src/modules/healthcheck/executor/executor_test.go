@@ -60,7 +60,7 @@ func TestExecutorRegistry_GetExecutor(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
 	heartbeatSvc := new(ExecutorMockHeartbeatService)
-	registry := NewExecutorRegistry(logger, heartbeatSvc)
+	registry := NewExecutorRegistry(logger, heartbeatSvc, nil)
 
 	tests := []struct {
 		name          string
@@ -121,7 +121,7 @@ func TestExecutorRegistry_ValidateConfig(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
 	heartbeatSvc := new(ExecutorMockHeartbeatService)
-	registry := NewExecutorRegistry(logger, heartbeatSvc)
+	registry := NewExecutorRegistry(logger, heartbeatSvc, nil)
 
 	tests := []struct {
 		name          string
@@ -267,7 +267,7 @@ func TestExecutorRegistry_ValidateConfig_Error_Logging(t *testing.T) {
 	// Setup with a logger that can be captured
 	logger := zap.NewNop().Sugar()
 	heartbeatSvc := new(ExecutorMockHeartbeatService)
-	registry := NewExecutorRegistry(logger, heartbeatSvc)
+	registry := NewExecutorRegistry(logger, heartbeatSvc, nil)
 
 	// Test that errors are properly logged
 	err := registry.ValidateConfig("http", `{"invalid": "config"}`)
@@ -279,7 +279,7 @@ func TestExecutorRegistry_Execute(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
 	heartbeatSvc := new(ExecutorMockHeartbeatService)
-	registry := NewExecutorRegistry(logger, heartbeatSvc)
+	registry := NewExecutorRegistry(logger, heartbeatSvc, nil)
 
 	heartbeatSvc.On("FindByMonitorIDPaginated", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return([]*heartbeat.Model{}, nil)
@@ -408,7 +408,7 @@ func TestNewExecutorRegistry(t *testing.T) {
 	heartbeatSvc := new(ExecutorMockHeartbeatService)
 
 	// Test registry creation
-	registry := NewExecutorRegistry(logger, heartbeatSvc)
+	registry := NewExecutorRegistry(logger, heartbeatSvc, nil)
 
 	// Verify registry is properly initialized
 	assert.NotNil(t, registry)
@@ -0,0 +1,182 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_tag"
+	"peekaping/src/modules/shared"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	GroupRuleAllUp   = "all_up"
+	GroupRuleAnyUp   = "any_up"
+	GroupRuleAtLeast = "at_least"
+)
+
+type GroupConfig struct {
+	MonitorIDs []string `json:"monitor_ids" example:"6830ad485361f19c598d6d90,6830ad485361f19c598d6d91"`
+	TagID      string   `json:"tag_id" example:"6830ad485361f19c598d6d92"`
+	Rule       string   `json:"rule" validate:"required,oneof=all_up any_up at_least" example:"all_up"`
+	MinUp      int      `json:"min_up" validate:"min=0" example:"2"`
+}
+
+// GroupExecutor derives the status of a monitor from the latest heartbeats of its members
+type GroupExecutor struct {
+	logger            *zap.SugaredLogger
+	heartbeatService  heartbeat.Service
+	monitorTagService monitor_tag.Service
+}
+
+func NewGroupExecutor(logger *zap.SugaredLogger, heartbeatService heartbeat.Service, monitorTagService monitor_tag.Service) *GroupExecutor {
+	return &GroupExecutor{
+		logger:            logger,
+		heartbeatService:  heartbeatService,
+		monitorTagService: monitorTagService,
+	}
+}
+
+func (g *GroupExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[GroupConfig](configJSON)
+}
+
+func (g *GroupExecutor) Validate(configJSON string) error {
+	cfgAny, err := g.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*GroupConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	if len(cfg.MonitorIDs) == 0 && cfg.TagID == "" {
+		return fmt.Errorf("either monitor_ids or tag_id is required")
+	}
+	if len(cfg.MonitorIDs) > 0 && cfg.TagID != "" {
+		return fmt.Errorf("monitor_ids and tag_id are mutually exclusive")
+	}
+	if cfg.Rule == GroupRuleAtLeast && cfg.MinUp < 1 {
+		return fmt.Errorf("min_up must be at least 1 for the at_least rule")
+	}
+
+	return nil
+}
+
+func (g *GroupExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	startTime := time.Now().UTC()
+
+	cfgAny, err := g.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, startTime, time.Now().UTC())
+	}
+	cfg := cfgAny.(*GroupConfig)
+
+	if g.heartbeatService == nil {
+		return DownResult(fmt.Errorf("group monitors can only run on the server"), startTime, time.Now().UTC())
+	}
+
+	memberIDs, err := g.members(ctx, m.ID, cfg)
+	if err != nil {
+		return DownResult(fmt.Errorf("failed to resolve group members: %w", err), startTime, time.Now().UTC())
+	}
+	if len(memberIDs) == 0 {
+		return DownResult(fmt.Errorf("group has no members"), startTime, time.Now().UTC())
+	}
+
+	up, down, maintenance := 0, 0, 0
+	for _, id := range memberIDs {
+		beats, err := g.heartbeatService.FindByMonitorIDPaginated(ctx, id, 1, 0, nil, false)
+		if err != nil {
+			return DownResult(fmt.Errorf("failed to fetch heartbeat of member %s: %w", id, err), startTime, time.Now().UTC())
+		}
+		// Members without heartbeats yet or still retrying count as not up
+		if len(beats) == 0 {
+			down++
+			continue
+		}
+		switch beats[0].Status {
+		// A degraded member is slow but still serving
+		case shared.MonitorStatusUp, shared.MonitorStatusDegraded:
+			up++
+		case shared.MonitorStatusMaintenance:
+			maintenance++
+		default:
+			down++
+		}
+	}
+
+	status, message := evaluateGroup(cfg, up, down, maintenance)
+	g.logger.Debugf("group %s: %s", m.Name, message)
+
+	return &Result{
+		Status:    status,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   time.Now().UTC(),
+	}
+}
+
+// members returns the monitors of the group, never including the group itself
+func (g *GroupExecutor) members(ctx context.Context, groupID string, cfg *GroupConfig) ([]string, error) {
+	ids := cfg.MonitorIDs
+	if cfg.TagID != "" {
+		if g.monitorTagService == nil {
+			return nil, fmt.Errorf("tag lookup is not available")
+		}
+		rels, err := g.monitorTagService.FindByTagID(ctx, cfg.TagID)
+		if err != nil {
+			return nil, err
+		}
+		ids = make([]string, 0, len(rels))
+		for _, rel := range rels {
+			ids = append(ids, rel.MonitorID)
+		}
+	}
+
+	seen := make(map[string]bool, len(ids))
+	members := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == groupID || seen[id] {
+			continue
+		}
+		seen[id] = true
+		members = append(members, id)
+	}
+	return members, nil
+}
+
+// evaluateGroup applies the aggregation rule. Members in maintenance are left out, a group
+// whose members are all in maintenance is in maintenance itself.
+func evaluateGroup(cfg *GroupConfig, up, down, maintenance int) (shared.MonitorStatus, string) {
+	total := up + down
+	message := fmt.Sprintf("%d of %d members up", up, total)
+	if maintenance > 0 {
+		message += fmt.Sprintf(", %d in maintenance", maintenance)
+	}
+
+	if total == 0 {
+		return shared.MonitorStatusMaintenance, message
+	}
+
+	var ok bool
+	switch cfg.Rule {
+	case GroupRuleAllUp:
+		ok = down == 0
+	case GroupRuleAnyUp:
+		ok = up > 0
+	case GroupRuleAtLeast:
+		ok = up >= cfg.MinUp
+		message += fmt.Sprintf(" (at least %d required)", cfg.MinUp)
+	default:
+		return shared.MonitorStatusDown, fmt.Sprintf("unknown group rule: %s", cfg.Rule)
+	}
+
+	if ok {
+		return shared.MonitorStatusUp, message
+	}
+	return shared.MonitorStatusDown, message
+}
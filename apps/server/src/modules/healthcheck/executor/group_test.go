@@ -0,0 +1,101 @@
+package executor
+
+import (
+	"context"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_tag"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+)
+
+type groupMockTagService struct {
+	monitor_tag.Service
+	monitors map[string][]string
+}
+
+func (g *groupMockTagService) FindByTagID(ctx context.Context, tagID string) ([]*monitor_tag.Model, error) {
+	var rels []*monitor_tag.Model
+	for _, id := range g.monitors[tagID] {
+		rels = append(rels, &monitor_tag.Model{MonitorID: id, TagID: tagID})
+	}
+	return rels, nil
+}
+
+func TestGroupExecutor_Validate(t *testing.T) {
+	executor := NewGroupExecutor(zap.NewNop().Sugar(), nil, nil)
+
+	tests := []struct {
+		name    string
+		config  string
+		wantErr bool
+	}{
+		{"by ids", `{"monitor_ids":["a","b"],"rule":"all_up"}`, false},
+		{"by tag", `{"tag_id":"t1","rule":"any_up"}`, false},
+		{"at least", `{"monitor_ids":["a","b"],"rule":"at_least","min_up":1}`, false},
+		{"no members", `{"rule":"all_up"}`, true},
+		{"ids and tag", `{"monitor_ids":["a"],"tag_id":"t1","rule":"all_up"}`, true},
+		{"at least without min", `{"monitor_ids":["a"],"rule":"at_least"}`, true},
+		{"unknown rule", `{"monitor_ids":["a"],"rule":"most_up"}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGroupExecutor_Execute(t *testing.T) {
+	heartbeatSvc := new(PushMockHeartbeatService)
+	latest := map[string]shared.MonitorStatus{
+		"api":   shared.MonitorStatusUp,
+		"db":    shared.MonitorStatusUp,
+		"queue": shared.MonitorStatusDown,
+		"cache": shared.MonitorStatusMaintenance,
+		"cdn":   shared.MonitorStatusDegraded,
+	}
+	for id, status := range latest {
+		heartbeatSvc.On("FindByMonitorIDPaginated", mock.Anything, id, 1, 0, (*bool)(nil), false).
+			Return([]*heartbeat.Model{{MonitorID: id, Status: status}}, nil)
+	}
+	heartbeatSvc.On("FindByMonitorIDPaginated", mock.Anything, "new", 1, 0, (*bool)(nil), false).
+		Return([]*heartbeat.Model{}, nil)
+
+	tagSvc := &groupMockTagService{monitors: map[string][]string{
+		"checkout": {"api", "db", "queue", "group"},
+	}}
+	executor := NewGroupExecutor(zap.NewNop().Sugar(), heartbeatSvc, tagSvc)
+
+	tests := []struct {
+		name    string
+		config  string
+		status  shared.MonitorStatus
+		message string
+	}{
+		{"all up", `{"monitor_ids":["api","db"],"rule":"all_up"}`, shared.MonitorStatusUp, "2 of 2 members up"},
+		{"all up with a down member", `{"monitor_ids":["api","queue"],"rule":"all_up"}`, shared.MonitorStatusDown, "1 of 2 members up"},
+		{"any up", `{"monitor_ids":["api","queue"],"rule":"any_up"}`, shared.MonitorStatusUp, "1 of 2 members up"},
+		{"at least by tag ignores the group", `{"tag_id":"checkout","rule":"at_least","min_up":2}`, shared.MonitorStatusUp, "2 of 3 members up (at least 2 required)"},
+		{"at least not reached", `{"monitor_ids":["api","queue","new"],"rule":"at_least","min_up":2}`, shared.MonitorStatusDown, "1 of 3 members up"},
+		{"maintenance is left out", `{"monitor_ids":["api","cache"],"rule":"all_up"}`, shared.MonitorStatusUp, "1 of 1 members up, 1 in maintenance"},
+		{"degraded counts as up", `{"monitor_ids":["api","cdn"],"rule":"all_up"}`, shared.MonitorStatusUp, "2 of 2 members up"},
+		{"only maintenance", `{"monitor_ids":["cache"],"rule":"all_up"}`, shared.MonitorStatusMaintenance, "0 of 0 members up"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), &Monitor{ID: "group", Name: "checkout", Config: tt.config}, nil)
+			assert.Equal(t, tt.status, result.Status)
+			assert.Contains(t, result.Message, tt.message)
+		})
+	}
+}
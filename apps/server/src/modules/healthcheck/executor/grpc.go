@@ -3,6 +3,7 @@ package executor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"peekaping/src/modules/shared"
 	"regexp"
@@ -18,13 +19,15 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
 	"google.golang.org/protobuf/types/dynamicpb"
 )
 
 type GRPCConfig struct {
-	GrpcUrl         string `json:"grpcUrl" validate:"required" example:"localhost:50051"`
-	GrpcProtobuf    string `json:"grpcProtobuf" validate:"required"`
+	GrpcUrl string `json:"grpcUrl" validate:"required" example:"localhost:50051"`
+	// GrpcProtobuf is optional when the server supports reflection
+	GrpcProtobuf    string `json:"grpcProtobuf"`
 	GrpcServiceName string `json:"grpcServiceName" validate:"required" example:"Health"`
 	GrpcMethod      string `json:"grpcMethod" validate:"required" example:"check"`
 	GrpcEnableTls   bool   `json:"grpcEnableTls"`
@@ -139,12 +142,21 @@ func (g *GRPCExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 	}
 }
 
+// errReflectionUnavailable marks failures to describe the method through server reflection,
+// as opposed to failures of the call itself
+var errReflectionUnavailable = errors.New("server reflection unavailable")
+
 // executeGRPCCall performs a real gRPC call with dynamic protobuf handling
 func (g *GRPCExecutor) executeGRPCCall(ctx context.Context, conn *grpc.ClientConn, cfg *GRPCConfig) (string, error) {
 	// Try to use gRPC server reflection first
 	response, err := g.tryReflectionCall(ctx, conn, cfg)
-	if err == nil {
-		return response, nil
+	if err == nil || !errors.Is(err, errReflectionUnavailable) {
+		return response, err
+	}
+
+	// Without a protobuf definition reflection is the only way to describe the method
+	if cfg.GrpcProtobuf == "" {
+		return "", fmt.Errorf("no grpcProtobuf given and %w", err)
 	}
 
 	g.logger.Debugf("Reflection call failed, trying direct call: %v", err)
@@ -153,31 +165,189 @@ func (g *GRPCExecutor) executeGRPCCall(ctx context.Context, conn *grpc.ClientCon
 	return g.tryDirectCall(ctx, conn, cfg)
 }
 
-// tryReflectionCall attempts to use gRPC server reflection to make the call
+// tryReflectionCall describes the method through gRPC server reflection and invokes it with
+// dynamic messages
 func (g *GRPCExecutor) tryReflectionCall(ctx context.Context, conn *grpc.ClientConn, cfg *GRPCConfig) (string, error) {
+	reflectionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Create reflection client
 	reflectionClient := grpc_reflection_v1alpha.NewServerReflectionClient(conn)
-
-	// Get service descriptor using reflection
-	stream, err := reflectionClient.ServerReflectionInfo(ctx)
+	stream, err := reflectionClient.ServerReflectionInfo(reflectionCtx)
 	if err != nil {
-		return "", fmt.Errorf("failed to create reflection stream: %w", err)
+		return "", fmt.Errorf("%w: failed to create reflection stream: %v", errReflectionUnavailable, err)
 	}
 	defer stream.CloseSend()
 
-	// Request service descriptor
-	err = stream.Send(&grpc_reflection_v1alpha.ServerReflectionRequest{
+	resolver := &reflectionResolver{stream: stream}
+
+	serviceName, err := resolver.resolveService(cfg.GrpcServiceName)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errReflectionUnavailable, err)
+	}
+
+	files, err := resolver.filesContainingSymbol(serviceName)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errReflectionUnavailable, err)
+	}
+
+	descriptor, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errReflectionUnavailable, err)
+	}
+	serviceDesc, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return "", fmt.Errorf("%w: %s is not a service", errReflectionUnavailable, serviceName)
+	}
+
+	// Method names are matched case-insensitively, the UI suggests "check" for Health/Check
+	var methodDesc protoreflect.MethodDescriptor
+	methods := serviceDesc.Methods()
+	for i := 0; i < methods.Len(); i++ {
+		if strings.EqualFold(string(methods.Get(i).Name()), cfg.GrpcMethod) {
+			methodDesc = methods.Get(i)
+			break
+		}
+	}
+	if methodDesc == nil {
+		return "", fmt.Errorf("method %s not found in service %s", cfg.GrpcMethod, serviceName)
+	}
+	if methodDesc.IsStreamingClient() || methodDesc.IsStreamingServer() {
+		return "", fmt.Errorf("streaming method %s is not supported", methodDesc.FullName())
+	}
+
+	requestMsg := dynamicpb.NewMessage(methodDesc.Input())
+	responseMsg := dynamicpb.NewMessage(methodDesc.Output())
+
+	if cfg.GrpcBody != "" {
+		if err := protojson.Unmarshal([]byte(cfg.GrpcBody), requestMsg); err != nil {
+			return "", fmt.Errorf("failed to unmarshal request body: %w", err)
+		}
+	}
+
+	methodName := fmt.Sprintf("/%s/%s", serviceName, methodDesc.Name())
+	g.logger.Debugf("Invoking method through reflection: %s", methodName)
+
+	if err := conn.Invoke(ctx, methodName, requestMsg, responseMsg); err != nil {
+		return "", err
+	}
+
+	responseJSON, err := protojson.Marshal(responseMsg)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal response: %w", err)
+	}
+
+	g.logger.Debugf("gRPC response: %s", string(responseJSON))
+	return string(responseJSON), nil
+}
+
+// reflectionResolver fetches descriptors over a server reflection stream
+type reflectionResolver struct {
+	stream grpc_reflection_v1alpha.ServerReflection_ServerReflectionInfoClient
+}
+
+func (r *reflectionResolver) request(req *grpc_reflection_v1alpha.ServerReflectionRequest) (*grpc_reflection_v1alpha.ServerReflectionResponse, error) {
+	if err := r.stream.Send(req); err != nil {
+		return nil, fmt.Errorf("failed to send reflection request: %w", err)
+	}
+	resp, err := r.stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive reflection response: %w", err)
+	}
+	if errResp := resp.GetErrorResponse(); errResp != nil {
+		return nil, fmt.Errorf("reflection error: %s", errResp.GetErrorMessage())
+	}
+	return resp, nil
+}
+
+// resolveService returns the fully qualified name of the service, accepting the name with or
+// without its package
+func (r *reflectionResolver) resolveService(name string) (string, error) {
+	resp, err := r.request(&grpc_reflection_v1alpha.ServerReflectionRequest{
 		MessageRequest: &grpc_reflection_v1alpha.ServerReflectionRequest_ListServices{
 			ListServices: "",
 		},
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to send reflection request: %w", err)
+		return "", err
 	}
 
-	// This is a simplified reflection implementation
-	// For now, return an error to fall back to direct call
-	return "", fmt.Errorf("reflection not fully implemented, falling back to direct call")
+	for _, service := range resp.GetListServicesResponse().GetService() {
+		if service.GetName() == name || strings.HasSuffix(service.GetName(), "."+name) {
+			return service.GetName(), nil
+		}
+	}
+	return "", fmt.Errorf("service %s not found", name)
+}
+
+// filesContainingSymbol loads the file defining symbol together with all its dependencies
+func (r *reflectionResolver) filesContainingSymbol(symbol string) (*protoregistry.Files, error) {
+	resp, err := r.request(&grpc_reflection_v1alpha.ServerReflectionRequest{
+		MessageRequest: &grpc_reflection_v1alpha.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: symbol,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	pending, err := addFileDescriptors(files, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if _, ok := files[name]; ok {
+			continue
+		}
+
+		resp, err := r.request(&grpc_reflection_v1alpha.ServerReflectionRequest{
+			MessageRequest: &grpc_reflection_v1alpha.ServerReflectionRequest_FileByFilename{
+				FileByFilename: name,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		missing, err := addFileDescriptors(files, resp)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, missing...)
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, file)
+	}
+	return protodesc.NewFiles(set)
+}
+
+// addFileDescriptors stores the descriptors of a reflection response and returns the
+// dependencies that are not loaded yet
+func addFileDescriptors(files map[string]*descriptorpb.FileDescriptorProto, resp *grpc_reflection_v1alpha.ServerReflectionResponse) ([]string, error) {
+	var added []*descriptorpb.FileDescriptorProto
+	for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		file := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(raw, file); err != nil {
+			return nil, fmt.Errorf("failed to parse file descriptor: %w", err)
+		}
+		files[file.GetName()] = file
+		added = append(added, file)
+	}
+
+	var missing []string
+	for _, file := range added {
+		for _, dependency := range file.GetDependency() {
+			if _, ok := files[dependency]; !ok {
+				missing = append(missing, dependency)
+			}
+		}
+	}
+	return missing, nil
 }
 
 // tryDirectCall attempts a direct gRPC call using common proto patterns
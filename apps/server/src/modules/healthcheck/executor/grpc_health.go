@@ -0,0 +1,227 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"peekaping/src/modules/shared"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type GRPCHealthConfig struct {
+	GrpcUrl string `json:"grpcUrl" validate:"required" example:"localhost:50051"`
+	// GrpcServiceName is the service whose health is checked, empty checks the whole server
+	GrpcServiceName string `json:"grpcServiceName" validate:"omitempty" example:"helloworld.Greeter"`
+	// GrpcWatch reads the first status sent by Health/Watch instead of calling Health/Check
+	GrpcWatch           bool   `json:"grpcWatch"`
+	GrpcEnableTls       bool   `json:"grpcEnableTls"`
+	GrpcIgnoreTlsErrors bool   `json:"grpcIgnoreTlsErrors"`
+	GrpcServerName      string `json:"grpcServerName" validate:"omitempty" example:"api.internal"`
+	TlsCert             string `json:"tlsCert" validate:"omitempty"`
+	TlsKey              string `json:"tlsKey" validate:"omitempty"`
+	TlsCa               string `json:"tlsCa" validate:"omitempty"`
+	// GrpcMetadata is a JSON object of headers sent with the call
+	GrpcMetadata string `json:"grpcMetadata" validate:"omitempty,json" example:"{\"authorization\":\"Bearer token\"}"`
+}
+
+type GRPCHealthExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewGRPCHealthExecutor(logger *zap.SugaredLogger) *GRPCHealthExecutor {
+	return &GRPCHealthExecutor{
+		logger: logger,
+	}
+}
+
+func (g *GRPCHealthExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[GRPCHealthConfig](configJSON)
+}
+
+func (g *GRPCHealthExecutor) Validate(configJSON string) error {
+	cfgAny, err := g.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*GRPCHealthConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	usesTLSOptions := cfg.GrpcIgnoreTlsErrors || cfg.GrpcServerName != "" || cfg.TlsCert != "" || cfg.TlsKey != "" || cfg.TlsCa != ""
+	if usesTLSOptions && !cfg.GrpcEnableTls {
+		return fmt.Errorf("TLS options require grpcEnableTls")
+	}
+	if _, err := g.buildTLSConfig(cfg); err != nil {
+		return err
+	}
+	if _, err := parseGRPCMetadata(cfg.GrpcMetadata); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// buildTLSConfig creates the TLS configuration of the connection, nil when TLS is disabled
+func (g *GRPCHealthExecutor) buildTLSConfig(cfg *GRPCHealthConfig) (*tls.Config, error) {
+	if !cfg.GrpcEnableTls {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: cfg.GrpcIgnoreTlsErrors,
+		ServerName:         cfg.GrpcServerName,
+	}
+
+	// Load client certificate and key if provided
+	if cfg.TlsCert != "" || cfg.TlsKey != "" {
+		// Both certificate and key must be provided together
+		if cfg.TlsCert == "" || cfg.TlsKey == "" {
+			return nil, fmt.Errorf("both certificate and key must be provided for client authentication")
+		}
+
+		cert, err := tls.X509KeyPair([]byte(cfg.TlsCert), []byte(cfg.TlsKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	// Load CA certificate if provided
+	if cfg.TlsCa != "" {
+		caCertPool := x509.NewCertPool()
+		if !caCertPool.AppendCertsFromPEM([]byte(cfg.TlsCa)) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+		tlsConfig.RootCAs = caCertPool
+	}
+
+	return tlsConfig, nil
+}
+
+func parseGRPCMetadata(raw string) (metadata.MD, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(raw), &headers); err != nil {
+		return nil, fmt.Errorf("grpcMetadata must be a JSON object of strings: %w", err)
+	}
+	return metadata.New(headers), nil
+}
+
+func (g *GRPCHealthExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	startTime := time.Now().UTC()
+
+	cfgAny, err := g.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(fmt.Errorf("invalid config: %w", err), startTime, time.Now().UTC())
+	}
+	cfg := cfgAny.(*GRPCHealthConfig)
+
+	g.logger.Debugf("execute grpc-health cfg: %s service=%q watch=%t", cfg.GrpcUrl, cfg.GrpcServiceName, cfg.GrpcWatch)
+
+	tlsConfig, err := g.buildTLSConfig(cfg)
+	if err != nil {
+		return DownResult(err, startTime, time.Now().UTC())
+	}
+	md, err := parseGRPCMetadata(cfg.GrpcMetadata)
+	if err != nil {
+		return DownResult(err, startTime, time.Now().UTC())
+	}
+
+	var opts []grpc.DialOption
+	if tlsConfig != nil {
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	conn, err := grpc.NewClient(cfg.GrpcUrl, opts...)
+	if err != nil {
+		return DownResult(fmt.Errorf("failed to create gRPC client: %w", err), startTime, time.Now().UTC())
+	}
+	defer conn.Close()
+
+	callCtx, callCancel := context.WithTimeout(ctx, time.Duration(m.Timeout)*time.Second)
+	defer callCancel()
+	if md != nil {
+		callCtx = metadata.NewOutgoingContext(callCtx, md)
+	}
+
+	servingStatus, err := g.healthStatus(callCtx, grpc_health_v1.NewHealthClient(conn), cfg)
+	endTime := time.Now().UTC()
+
+	if err != nil {
+		g.logger.Infof("gRPC health check failed: %s, %s", m.Name, err.Error())
+		code := status.Code(err)
+		message := fmt.Sprintf("gRPC health check failed: %v", err)
+		switch code {
+		case codes.NotFound:
+			message = fmt.Sprintf("gRPC health check failed: service %q is unknown to the server", cfg.GrpcServiceName)
+		case codes.Unimplemented:
+			message = "gRPC health check failed: server does not implement grpc.health.v1.Health"
+		}
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   message,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Metadata:  Metadata{"grpc_code": code.String()},
+		}
+	}
+
+	result := &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("gRPC health status: %s", servingStatus),
+		StartTime: startTime,
+		EndTime:   endTime,
+		Metadata:  Metadata{"serving_status": servingStatus.String()},
+	}
+	if servingStatus != grpc_health_v1.HealthCheckResponse_SERVING {
+		g.logger.Infof("gRPC health check failed: %s, %s", m.Name, servingStatus)
+		result.Status = shared.MonitorStatusDown
+		return result
+	}
+
+	g.logger.Infof("gRPC health check successful: %s", m.Name)
+	return result
+}
+
+// healthStatus asks the server for the serving status through Check, or the first update of Watch
+func (g *GRPCHealthExecutor) healthStatus(ctx context.Context, client grpc_health_v1.HealthClient, cfg *GRPCHealthConfig) (grpc_health_v1.HealthCheckResponse_ServingStatus, error) {
+	request := &grpc_health_v1.HealthCheckRequest{Service: cfg.GrpcServiceName}
+
+	if !cfg.GrpcWatch {
+		response, err := client.Check(ctx, request)
+		if err != nil {
+			return grpc_health_v1.HealthCheckResponse_UNKNOWN, err
+		}
+		return response.GetStatus(), nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, request)
+	if err != nil {
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN, err
+	}
+	response, err := stream.Recv()
+	if err != nil {
+		return grpc_health_v1.HealthCheckResponse_UNKNOWN, err
+	}
+	return response.GetStatus(), nil
+}
@@ -0,0 +1,268 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// startHealthServer serves the standard health service with reflection enabled. When
+// requiredToken is set, calls without a matching x-token header are rejected.
+func startHealthServer(t *testing.T, useTLS bool, requiredToken string) (string, *health.Server) {
+	t.Helper()
+
+	var opts []grpc.ServerOption
+	if useTLS {
+		cert := testCertificate()
+		opts = append(opts, grpc.Creds(credentials.NewServerTLSFromCert(&cert)))
+	}
+	if requiredToken != "" {
+		opts = append(opts, grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if tokens := md.Get("x-token"); len(tokens) != 1 || tokens[0] != requiredToken {
+				return nil, status.Error(codes.Unauthenticated, "missing token")
+			}
+			return handler(ctx, req)
+		}))
+	}
+
+	server := grpc.NewServer(opts...)
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders.Orders", grpc_health_v1.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("billing.Billing", grpc_health_v1.HealthCheckResponse_NOT_SERVING)
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String(), healthServer
+}
+
+func TestGRPCHealthExecutor_Validate(t *testing.T) {
+	executor := NewGRPCHealthExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "server health",
+			config:    `{"grpcUrl":"localhost:50051"}`,
+			wantError: false,
+		},
+		{
+			name:      "service watch with tls and metadata",
+			config:    `{"grpcUrl":"localhost:50051","grpcServiceName":"orders.Orders","grpcWatch":true,"grpcEnableTls":true,"grpcServerName":"api.internal","grpcMetadata":"{\"x-token\":\"secret\"}"}`,
+			wantError: false,
+		},
+		{
+			name:      "missing url",
+			config:    `{"grpcServiceName":"orders.Orders"}`,
+			wantError: true,
+		},
+		{
+			name:      "tls options without tls",
+			config:    `{"grpcUrl":"localhost:50051","grpcIgnoreTlsErrors":true}`,
+			wantError: true,
+		},
+		{
+			name:      "client certificate without key",
+			config:    `{"grpcUrl":"localhost:50051","grpcEnableTls":true,"tlsCert":"cert"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid ca",
+			config:    `{"grpcUrl":"localhost:50051","grpcEnableTls":true,"tlsCa":"not a certificate"}`,
+			wantError: true,
+		},
+		{
+			name:      "metadata is not an object of strings",
+			config:    `{"grpcUrl":"localhost:50051","grpcMetadata":"{\"x-retries\":3}"}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGRPCHealthExecutor_Execute(t *testing.T) {
+	address, _ := startHealthServer(t, false, "")
+	tlsAddress, _ := startHealthServer(t, true, "")
+	tokenAddress, _ := startHealthServer(t, false, "secret")
+
+	executor := NewGRPCHealthExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "server serving",
+			config:      fmt.Sprintf(`{"grpcUrl":%q}`, address),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "gRPC health status: SERVING",
+		},
+		{
+			name:        "service serving",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"orders.Orders"}`, address),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "SERVING",
+		},
+		{
+			name:        "service not serving",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"billing.Billing"}`, address),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "NOT_SERVING",
+		},
+		{
+			name:        "unknown service",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"unknown.Service"}`, address),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: `service "unknown.Service" is unknown to the server`,
+		},
+		{
+			name:        "watch",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"orders.Orders","grpcWatch":true}`, address),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "SERVING",
+		},
+		{
+			name:        "watch unknown service",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"unknown.Service","grpcWatch":true}`, address),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "SERVICE_UNKNOWN",
+		},
+		{
+			name:        "tls with ignored certificate errors",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcEnableTls":true,"grpcIgnoreTlsErrors":true}`, tlsAddress),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "SERVING",
+		},
+		{
+			name:        "tls with untrusted certificate",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcEnableTls":true}`, tlsAddress),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "gRPC health check failed",
+		},
+		{
+			name:        "metadata header",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcMetadata":"{\"x-token\":\"secret\"}"}`, tokenAddress),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "SERVING",
+		},
+		{
+			name:        "missing metadata header",
+			config:      fmt.Sprintf(`{"grpcUrl":%q}`, tokenAddress),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "missing token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "grpc-health",
+				Name:    "gRPC Health Monitor",
+				Timeout: 5,
+				Config:  tt.config,
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+		})
+	}
+}
+
+func TestGRPCExecutor_ExecuteReflection(t *testing.T) {
+	address, _ := startHealthServer(t, false, "")
+	executor := NewGRPCExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "short service name",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"Health","grpcMethod":"check","grpcBody":"{\"service\":\"orders.Orders\"}","keyword":"SERVING"}`, address),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "keyword [SERVING] is found",
+		},
+		{
+			name:        "full service name",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"grpc.health.v1.Health","grpcMethod":"Check","grpcBody":"{\"service\":\"billing.Billing\"}","keyword":"NOT_SERVING"}`, address),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "keyword [NOT_SERVING] is found",
+		},
+		{
+			name:        "call error",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"Health","grpcMethod":"check","grpcBody":"{\"service\":\"unknown.Service\"}"}`, address),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "NotFound",
+		},
+		{
+			name:        "unknown method",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"Health","grpcMethod":"ping"}`, address),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "method ping not found in service grpc.health.v1.Health",
+		},
+		{
+			name:        "streaming method",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"Health","grpcMethod":"watch"}`, address),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "streaming method grpc.health.v1.Health.Watch is not supported",
+		},
+		{
+			name:        "unknown service without protobuf",
+			config:      fmt.Sprintf(`{"grpcUrl":%q,"grpcServiceName":"Orders","grpcMethod":"list"}`, address),
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "no grpcProtobuf given",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "grpc-keyword",
+				Name:    "gRPC Monitor",
+				Timeout: 5,
+				Config:  tt.config,
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+		})
+	}
+}
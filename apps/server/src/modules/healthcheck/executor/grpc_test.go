@@ -161,8 +161,8 @@ func TestGRPCExecutor_Validate(t *testing.T) {
 				"grpcServiceName": "Health",
 				"grpcMethod": "check"
 			}`,
-			expectedError: true,
-			description:   "grpcProtobuf is required",
+			expectedError: false,
+			description:   "grpcProtobuf is optional, the method is described through server reflection",
 		},
 		{
 			name: "missing grpcServiceName",
@@ -276,9 +276,9 @@ func TestGRPCExecutor_Execute(t *testing.T) {
 				Timeout:  5,
 				Config:   `{}`,
 			},
-			expectedStatus: shared.MonitorStatusUp,
-			expectMessage:  "gRPC call successful",
-			description:    "Empty config uses mock response which succeeds",
+			expectedStatus: shared.MonitorStatusDown,
+			expectMessage:  "no grpcProtobuf given",
+			description:    "Empty config has neither a protobuf definition nor a server to reflect on",
 		},
 		{
 			name: "inverted keyword match",
@@ -631,6 +631,11 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 
 	h.logger.Infof("HTTP response status: %s, %d", m.Name, resp.StatusCode)
 
+	metadata := Metadata{MetadataStatusCode: resp.StatusCode}
+	if resp.Request != nil && resp.Request.URL != nil {
+		metadata["final_url"] = resp.Request.URL.String()
+	}
+
 	// Extract TLS information if available
 	var tlsInfo *certificate.TLSInfo
 	if strings.HasPrefix(cfg.Url, "https://") && activeTLSInterceptor != nil {
@@ -644,6 +649,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 			StartTime: startTime,
 			EndTime:   endTime,
 			TLSInfo:   tlsInfo,
+			Metadata:  metadata,
 		}
 	}
 
@@ -656,9 +662,11 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 			StartTime: startTime,
 			EndTime:   endTime,
 			TLSInfo:   tlsInfo,
+			Metadata:  metadata,
 		}
 	}
 	var responseBody = string(bodyBytes)
+	metadata[MetadataResponseSize] = len(bodyBytes)
 	h.logger.Debugf("Response body length: %d", len(responseBody))
 
 	// Check keyword if specified
@@ -676,6 +684,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 				StartTime: startTime,
 				EndTime:   endTime,
 				TLSInfo:   tlsInfo,
+				Metadata:  metadata,
 			}
 		}
 	}
@@ -690,6 +699,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 				StartTime: startTime,
 				EndTime:   endTime,
 				TLSInfo:   tlsInfo,
+				Metadata:  metadata,
 			}
 		}
 		if !isValid {
@@ -705,6 +715,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 				StartTime: startTime,
 				EndTime:   endTime,
 				TLSInfo:   tlsInfo,
+				Metadata:  metadata,
 			}
 		}
 	}
@@ -715,5 +726,6 @@ func (h *HTTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 		StartTime: startTime,
 		EndTime:   endTime,
 		TLSInfo:   tlsInfo,
+		Metadata:  metadata,
 	}
 }
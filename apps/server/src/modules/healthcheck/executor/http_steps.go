@@ -0,0 +1,380 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/shared"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+	"go.uber.org/zap"
+)
+
+// httpStepVariable matches a {{name}} reference to a variable in the fields of a step
+var httpStepVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+var httpStepVariableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type HTTPStepsConfig struct {
+	Steps []HTTPStep `json:"steps" validate:"required,min=1,max=20,dive"`
+	// Variables are available to every step, next to the ones extracted by earlier steps
+	Variables       map[string]string `json:"variables" validate:"omitempty"`
+	MaxRedirects    int               `json:"max_redirects" validate:"omitempty,min=0"`
+	IgnoreTlsErrors bool              `json:"ignore_tls_errors"`
+	CheckCertExpiry bool              `json:"check_cert_expiry"`
+}
+
+// HTTPStep is one request of the transaction, Url, Headers, Body, Keyword and ExpectedValue
+// may reference variables as {{name}}
+type HTTPStep struct {
+	Name                string   `json:"name" validate:"required,max=100" example:"login"`
+	Url                 string   `json:"url" validate:"required" example:"https://api.example.com/login"`
+	Method              string   `json:"method" validate:"required,oneof=GET POST PUT DELETE PATCH HEAD OPTIONS"`
+	Headers             string   `json:"headers" validate:"omitempty,json"`
+	Encoding            string   `json:"encoding" validate:"omitempty,oneof=json form xml text"`
+	Body                string   `json:"body" validate:"omitempty"`
+	AcceptedStatusCodes []string `json:"accepted_statuscodes" validate:"omitempty,dive,oneof=2XX 3XX 4XX 5XX"`
+
+	// Response validation fields
+	Keyword       string `json:"keyword,omitempty"`
+	InvertKeyword bool   `json:"invert_keyword,omitempty"`
+	JsonQuery     string `json:"json_query,omitempty"`
+	JsonCondition string `json:"json_condition,omitempty" validate:"omitempty,oneof='==' '!=' '>' '<' '>=' '<='"`
+	ExpectedValue string `json:"expected_value,omitempty"`
+	MaxLatencyMs  int    `json:"max_latency_ms,omitempty" validate:"omitempty,min=1" example:"500"`
+
+	Extract []HTTPStepExtraction `json:"extract,omitempty" validate:"omitempty,dive"`
+}
+
+// HTTPStepExtraction stores a part of the response in a variable for the following steps
+type HTTPStepExtraction struct {
+	Variable string `json:"variable" validate:"required" example:"token"`
+	Source   string `json:"source" validate:"required,oneof=json header regex" example:"json"`
+	// Expression is a gjson path, a header name or a regular expression whose first group, or
+	// whole match without groups, becomes the value
+	Expression string `json:"expression" validate:"required" example:"data.access_token"`
+}
+
+type HTTPStepsExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewHTTPStepsExecutor(logger *zap.SugaredLogger) *HTTPStepsExecutor {
+	return &HTTPStepsExecutor{
+		logger: logger,
+	}
+}
+
+func (s *HTTPStepsExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[HTTPStepsConfig](configJSON)
+}
+
+func (s *HTTPStepsExecutor) Validate(configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*HTTPStepsConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	// Every referenced variable must be known before the step runs
+	defined := make(map[string]bool)
+	for name := range cfg.Variables {
+		if !httpStepVariableName.MatchString(name) {
+			return fmt.Errorf("invalid variable name %q", name)
+		}
+		defined[name] = true
+	}
+
+	for i, step := range cfg.Steps {
+		label := fmt.Sprintf("step %d (%s)", i+1, step.Name)
+
+		for _, field := range []string{step.Url, step.Headers, step.Body, step.Keyword, step.ExpectedValue} {
+			for _, match := range httpStepVariable.FindAllStringSubmatch(field, -1) {
+				if !defined[match[1]] {
+					return fmt.Errorf("%s uses undefined variable %q", label, match[1])
+				}
+			}
+		}
+		if !httpStepVariable.MatchString(step.Url) {
+			if u, err := url.ParseRequestURI(step.Url); err != nil || u.Host == "" {
+				return fmt.Errorf("%s has an invalid url", label)
+			}
+		}
+
+		for _, extraction := range step.Extract {
+			if !httpStepVariableName.MatchString(extraction.Variable) {
+				return fmt.Errorf("%s extracts into invalid variable name %q", label, extraction.Variable)
+			}
+			if extraction.Source == "regex" {
+				if _, err := regexp.Compile(extraction.Expression); err != nil {
+					return fmt.Errorf("%s has an invalid regex: %w", label, err)
+				}
+			}
+			defined[extraction.Variable] = true
+		}
+	}
+	return nil
+}
+
+func (h *HTTPStepsExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := h.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*HTTPStepsConfig)
+
+	h.logger.Debugf("execute http-steps: %s, %d steps", m.Name, len(cfg.Steps))
+
+	// The monitor timeout bounds the whole transaction
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	baseTransport := &http.Transport{}
+	if cfg.IgnoreTlsErrors {
+		baseTransport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+	}
+	tlsInterceptor := NewTLSInterceptor(buildProxyTransport(baseTransport, proxyModel))
+
+	// Cookies set by a step, a session after login for instance, are sent by the following ones
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{
+		Transport: tlsInterceptor,
+		Jar:       jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if cfg.MaxRedirects == 0 {
+				return fmt.Errorf("redirects disabled: max_redirects set to 0")
+			}
+			if len(via) > cfg.MaxRedirects {
+				return fmt.Errorf("too many redirects: followed %d redirects, maximum allowed is %d", len(via), cfg.MaxRedirects)
+			}
+			return nil
+		},
+	}
+
+	variables := make(map[string]string, len(cfg.Variables))
+	for name, value := range cfg.Variables {
+		variables[name] = value
+	}
+
+	steps := make([]Metadata, 0, len(cfg.Steps))
+	metadata := Metadata{"steps": steps}
+
+	var tlsInfo *certificate.TLSInfo
+	startTime := time.Now().UTC()
+
+	for i, step := range cfg.Steps {
+		stepMetadata := Metadata{"name": step.Name}
+		duration, err := h.runStep(ctx, client, &step, variables, stepMetadata)
+		stepMetadata["duration_ms"] = durationMs(duration)
+		steps = append(steps, stepMetadata)
+		metadata["steps"] = steps
+
+		// The first certificate of the transaction is the one tracked, later steps may
+		// call other hosts
+		if tlsInfo == nil {
+			tlsInfo = tlsInterceptor.GetTLSInfo()
+		}
+
+		if err != nil {
+			stepMetadata["error"] = err.Error()
+			metadata["failed_step"] = i + 1
+			message := fmt.Sprintf("Step %d (%s) failed: %v", i+1, step.Name, err)
+			h.logger.Infof("HTTP steps check failed: %s, %s", m.Name, message)
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   message,
+				StartTime: startTime,
+				EndTime:   time.Now().UTC(),
+				TLSInfo:   tlsInfo,
+				Metadata:  metadata,
+			}
+		}
+	}
+
+	endTime := time.Now().UTC()
+	message := fmt.Sprintf("%d/%d steps passed in %v", len(cfg.Steps), len(cfg.Steps), endTime.Sub(startTime).Round(time.Millisecond))
+
+	h.logger.Infof("HTTP steps check successful: %s, %s", m.Name, message)
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+		TLSInfo:   tlsInfo,
+		Metadata:  metadata,
+	}
+}
+
+// bodyEscaper returns how values are escaped in a body of the given encoding, so quotes or
+// separators in an extracted value cannot break the body
+func bodyEscaper(encoding string) func(string) string {
+	switch encoding {
+	case "json":
+		// the reference sits inside a JSON string, the quotes added by Marshal are dropped
+		return func(value string) string {
+			raw, _ := json.Marshal(value)
+			return string(raw[1 : len(raw)-1])
+		}
+	case "form":
+		return url.QueryEscape
+	case "xml":
+		return func(value string) string {
+			var escaped strings.Builder
+			xml.EscapeText(&escaped, []byte(value))
+			return escaped.String()
+		}
+	default:
+		return func(value string) string { return value }
+	}
+}
+
+// runStep sends the request of the step, checks the response and extracts its variables,
+// returning the time until the response was read
+func (h *HTTPStepsExecutor) runStep(ctx context.Context, client *http.Client, step *HTTPStep, variables map[string]string, metadata Metadata) (time.Duration, error) {
+	renderEscaped := func(value string, escape func(string) string) string {
+		return httpStepVariable.ReplaceAllStringFunc(value, func(ref string) string {
+			return escape(variables[httpStepVariable.FindStringSubmatch(ref)[1]])
+		})
+	}
+	render := func(value string) string {
+		return renderEscaped(value, func(v string) string { return v })
+	}
+
+	var body io.Reader
+	if step.Body != "" {
+		body = strings.NewReader(renderEscaped(step.Body, bodyEscaper(step.Encoding)))
+	}
+	req, err := http.NewRequestWithContext(ctx, step.Method, render(step.Url), body)
+	if err != nil {
+		return 0, err
+	}
+	setDefaultHeaders(req)
+
+	switch step.Encoding {
+	case "json":
+		req.Header.Set("Content-Type", "application/json")
+	case "form":
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	case "xml":
+		req.Header.Set("Content-Type", "application/xml")
+	case "text":
+		req.Header.Set("Content-Type", "text/plain")
+	}
+
+	if step.Headers != "" {
+		headersMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(step.Headers), &headersMap); err != nil {
+			return 0, fmt.Errorf("invalid headers json: %w", err)
+		}
+		for k, v := range headersMap {
+			req.Header.Set(k, render(v))
+		}
+	}
+
+	startTime := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return time.Since(startTime), err
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	duration := time.Since(startTime)
+	metadata[MetadataStatusCode] = resp.StatusCode
+	if err != nil {
+		return duration, fmt.Errorf("failed to read response body: %w", err)
+	}
+	responseBody := string(bodyBytes)
+	metadata[MetadataResponseSize] = len(bodyBytes)
+
+	accepted := step.AcceptedStatusCodes
+	if len(accepted) == 0 {
+		accepted = []string{"2XX"}
+	}
+	if !isStatusAccepted(resp.StatusCode, accepted) {
+		return duration, fmt.Errorf("HTTP request failed with status: %d", resp.StatusCode)
+	}
+
+	if step.MaxLatencyMs > 0 && duration > time.Duration(step.MaxLatencyMs)*time.Millisecond {
+		return duration, fmt.Errorf("response took %v, more than %dms", duration.Round(time.Millisecond), step.MaxLatencyMs)
+	}
+
+	keyword := render(step.Keyword)
+	if !checkKeyword(responseBody, keyword, step.InvertKeyword) {
+		if step.InvertKeyword {
+			return duration, fmt.Errorf("Keyword check failed: keyword '%s' found in response (expected absent)", keyword)
+		}
+		return duration, fmt.Errorf("Keyword check failed: keyword '%s' not found in response", keyword)
+	}
+
+	expected := render(step.ExpectedValue)
+	valid, err := checkJsonQuery(responseBody, step.JsonQuery, step.JsonCondition, expected)
+	if err != nil {
+		return duration, fmt.Errorf("JSON query validation error: %w", err)
+	}
+	if !valid {
+		condition := step.JsonCondition
+		if condition == "" {
+			condition = "=="
+		}
+		return duration, fmt.Errorf("JSON query validation failed: query '%s' with condition '%s' and expected value '%s'",
+			step.JsonQuery, condition, expected)
+	}
+
+	for _, extraction := range step.Extract {
+		value, err := extractStepVariable(resp, responseBody, &extraction)
+		if err != nil {
+			return duration, fmt.Errorf("failed to extract %s: %w", extraction.Variable, err)
+		}
+		variables[extraction.Variable] = value
+	}
+
+	return duration, nil
+}
+
+func extractStepVariable(resp *http.Response, body string, extraction *HTTPStepExtraction) (string, error) {
+	switch extraction.Source {
+	case "json":
+		result := gjson.Get(body, extraction.Expression)
+		if !result.Exists() {
+			return "", fmt.Errorf("JSON query path not found: %s", extraction.Expression)
+		}
+		return result.String(), nil
+	case "header":
+		if _, ok := resp.Header[http.CanonicalHeaderKey(extraction.Expression)]; !ok {
+			return "", fmt.Errorf("header %s not in response", extraction.Expression)
+		}
+		return resp.Header.Get(extraction.Expression), nil
+	case "regex":
+		re, err := regexp.Compile(extraction.Expression)
+		if err != nil {
+			return "", err
+		}
+		match := re.FindStringSubmatch(body)
+		if match == nil {
+			return "", fmt.Errorf("regex %s does not match the response", extraction.Expression)
+		}
+		if len(match) > 1 {
+			return match[1], nil
+		}
+		return match[0], nil
+	default:
+		return "", errors.New("unknown extraction source " + extraction.Source)
+	}
+}
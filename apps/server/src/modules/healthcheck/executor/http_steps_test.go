@@ -0,0 +1,317 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// startStepsServer serves a login flow: POST /login sets a session cookie, GET /token returns
+// a token for the session, GET /api requires the token and POST /logout ends the session
+func startStepsServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		var credentials struct{ User, Password string }
+		json.NewDecoder(r.Body).Decode(&credentials)
+		if credentials.User != "monitor" || credentials.Password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s1"})
+		w.Header().Set("X-Request-Id", "req-42")
+		w.Write([]byte(`<html><input name="csrf" value="c5rf"></html>`))
+	})
+	mux.HandleFunc("GET /token", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "s1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte(`{"data":{"access_token":"t0k3n","expires_in":300}}`))
+	})
+	mux.HandleFunc("GET /api", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer t0k3n" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"status":"ok","items":3,"request":"` + r.URL.Query().Get("request") + `"}`))
+	})
+	mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`ok`))
+	})
+	mux.HandleFunc("POST /logout", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-CSRF-Token") != "c5rf" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+const stepsLogin = `{"name":"login","url":"%[1]s/login","method":"POST","encoding":"json","body":"{\"user\":\"{{user}}\",\"password\":\"secret\"}",
+	"extract":[{"variable":"csrf","source":"regex","expression":"name=\"csrf\" value=\"([^\"]+)\""},{"variable":"request_id","source":"header","expression":"x-request-id"}]}`
+
+func TestHTTPStepsExecutor_Validate(t *testing.T) {
+	executor := NewHTTPStepsExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid single step",
+			config:    `{"steps":[{"name":"home","url":"https://example.com","method":"GET"}]}`,
+			wantError: false,
+		},
+		{
+			name:      "valid variables from earlier steps",
+			config:    `{"variables":{"host":"https://example.com"},"steps":[{"name":"token","url":"{{host}}/token","method":"POST","extract":[{"variable":"token","source":"json","expression":"access_token"}]},{"name":"api","url":"{{host}}/api","method":"GET","headers":"{\"Authorization\":\"Bearer {{token}}\"}","max_latency_ms":500}]}`,
+			wantError: false,
+		},
+		{
+			name:      "no steps",
+			config:    `{"steps":[]}`,
+			wantError: true,
+		},
+		{
+			name:      "step without name",
+			config:    `{"steps":[{"url":"https://example.com","method":"GET"}]}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid url",
+			config:    `{"steps":[{"name":"home","url":"example","method":"GET"}]}`,
+			wantError: true,
+		},
+		{
+			name:      "variable used before extraction",
+			config:    `{"steps":[{"name":"api","url":"https://example.com/api","method":"GET","headers":"{\"Authorization\":\"Bearer {{token}}\"}"},{"name":"token","url":"https://example.com/token","method":"GET","extract":[{"variable":"token","source":"json","expression":"token"}]}]}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid regex",
+			config:    `{"steps":[{"name":"home","url":"https://example.com","method":"GET","extract":[{"variable":"id","source":"regex","expression":"id=(["}]}]}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown extraction source",
+			config:    `{"steps":[{"name":"home","url":"https://example.com","method":"GET","extract":[{"variable":"id","source":"cookie","expression":"id"}]}]}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid variable name",
+			config:    `{"steps":[{"name":"home","url":"https://example.com","method":"GET","extract":[{"variable":"my-id","source":"json","expression":"id"}]}]}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestHTTPStepsExecutor_Execute(t *testing.T) {
+	server := startStepsServer(t)
+	executor := NewHTTPStepsExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name           string
+		steps          []string
+		wantStatus     shared.MonitorStatus
+		wantMessage    string
+		wantFailedStep int
+	}{
+		{
+			name: "full transaction",
+			steps: []string{
+				stepsLogin,
+				`{"name":"token","url":"%[1]s/token","method":"GET","extract":[{"variable":"token","source":"json","expression":"data.access_token"}]}`,
+				`{"name":"api","url":"%[1]s/api?request={{request_id}}","method":"GET","headers":"{\"Authorization\":\"Bearer {{token}}\"}","keyword":"{{request_id}}","json_query":"items","json_condition":">=","expected_value":"3"}`,
+				`{"name":"logout","url":"%[1]s/logout","method":"POST","headers":"{\"X-CSRF-Token\":\"{{csrf}}\"}"}`,
+			},
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "4/4 steps passed in",
+		},
+		{
+			name: "status assertion identifies the step",
+			steps: []string{
+				`{"name":"api","url":"%[1]s/api","method":"GET"}`,
+			},
+			wantStatus:     shared.MonitorStatusDown,
+			wantMessage:    "Step 1 (api) failed: HTTP request failed with status: 401",
+			wantFailedStep: 1,
+		},
+		{
+			name: "accepted status codes",
+			steps: []string{
+				`{"name":"api","url":"%[1]s/api","method":"GET","accepted_statuscodes":["4XX"]}`,
+			},
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "1/1 steps passed",
+		},
+		{
+			name: "json assertion",
+			steps: []string{
+				stepsLogin,
+				`{"name":"token","url":"%[1]s/token","method":"GET","json_query":"data.expires_in","json_condition":">","expected_value":"600"}`,
+			},
+			wantStatus:     shared.MonitorStatusDown,
+			wantMessage:    "Step 2 (token) failed: JSON query validation failed",
+			wantFailedStep: 2,
+		},
+		{
+			name: "missing extraction",
+			steps: []string{
+				stepsLogin,
+				`{"name":"token","url":"%[1]s/token","method":"GET","extract":[{"variable":"token","source":"json","expression":"data.refresh_token"}]}`,
+			},
+			wantStatus:     shared.MonitorStatusDown,
+			wantMessage:    "Step 2 (token) failed: failed to extract token: JSON query path not found",
+			wantFailedStep: 2,
+		},
+		{
+			name: "latency assertion",
+			steps: []string{
+				`{"name":"slow","url":"%[1]s/slow","method":"GET","max_latency_ms":10}`,
+			},
+			wantStatus:     shared.MonitorStatusDown,
+			wantMessage:    "Step 1 (slow) failed: response took",
+			wantFailedStep: 1,
+		},
+		{
+			name: "keyword assertion",
+			steps: []string{
+				stepsLogin,
+				`{"name":"token","url":"%[1]s/token","method":"GET","keyword":"t0k3n","invert_keyword":true}`,
+			},
+			wantStatus:     shared.MonitorStatusDown,
+			wantMessage:    "Step 2 (token) failed: Keyword check failed: keyword 't0k3n' found in response",
+			wantFailedStep: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := `{"variables":{"user":"monitor"},"steps":[`
+			for i, step := range tt.steps {
+				if i > 0 {
+					config += ","
+				}
+				config += fmt.Sprintf(step, server.URL)
+			}
+			config += `]}`
+			require.NoError(t, executor.Validate(config))
+
+			result := executor.Execute(context.Background(), &Monitor{
+				ID:      "steps-monitor",
+				Type:    "http-steps",
+				Name:    "Steps Monitor",
+				Timeout: 5,
+				Config:  config,
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+			assert.Nil(t, result.TLSInfo)
+
+			steps, ok := result.Metadata["steps"].([]Metadata)
+			require.True(t, ok)
+			if tt.wantFailedStep > 0 {
+				assert.Equal(t, tt.wantFailedStep, result.Metadata["failed_step"])
+				require.Len(t, steps, tt.wantFailedStep)
+				assert.NotEmpty(t, steps[tt.wantFailedStep-1]["error"])
+			} else {
+				assert.Len(t, steps, len(tt.steps))
+				assert.NotContains(t, result.Metadata, "failed_step")
+			}
+			for _, step := range steps {
+				assert.Contains(t, step, "duration_ms")
+				assert.Contains(t, step, MetadataStatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPStepsExecutor_EscapesBodyVariables(t *testing.T) {
+	const name = `O"Brien & Sons=1`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /customer", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"name": name})
+	})
+	mux.HandleFunc("POST /json", func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Name string }
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Name != name {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	mux.HandleFunc("POST /form", func(w http.ResponseWriter, r *http.Request) {
+		if r.ParseForm() != nil || r.PostForm.Get("name") != name || r.PostForm.Get("plan") != "pro" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	config := fmt.Sprintf(`{"steps":[
+		{"name":"customer","url":"%[1]s/customer","method":"GET","extract":[{"variable":"name","source":"json","expression":"name"}]},
+		{"name":"json","url":"%[1]s/json","method":"POST","encoding":"json","body":"{\"name\":\"{{name}}\"}"},
+		{"name":"form","url":"%[1]s/form","method":"POST","encoding":"form","body":"name={{name}}&plan=pro"}
+	]}`, server.URL)
+	executor := NewHTTPStepsExecutor(zap.NewNop().Sugar())
+	require.NoError(t, executor.Validate(config))
+
+	result := executor.Execute(context.Background(), &Monitor{
+		ID:      "steps-monitor",
+		Type:    "http-steps",
+		Name:    "Steps Monitor",
+		Timeout: 5,
+		Config:  config,
+	}, nil)
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+}
+
+func TestHTTPStepsExecutor_ExecuteTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	executor := NewHTTPStepsExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "http-steps",
+		Name:    "Steps Monitor",
+		Timeout: 5,
+		Config:  fmt.Sprintf(`{"ignore_tls_errors":true,"steps":[{"name":"a","url":"%[1]s","method":"GET"},{"name":"b","url":"%[1]s","method":"GET","json_query":"ok","expected_value":"true"}]}`, server.URL),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	require.NotNil(t, result.TLSInfo)
+	assert.NotNil(t, result.TLSInfo.CertInfo)
+}
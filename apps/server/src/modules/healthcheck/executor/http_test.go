@@ -826,6 +826,46 @@ func TestHTTPExecutor_Execute_WithHeaders(t *testing.T) {
 	assert.Equal(t, shared.MonitorStatusUp, result.Status)
 }
 
+func TestHTTPExecutor_Execute_Metadata(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	executor := NewHTTPExecutor(logger)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	monitor := &Monitor{
+		ID:       "monitor1",
+		Type:     "http",
+		Name:     "Test Monitor",
+		Interval: 30,
+		Timeout:  5,
+		Config: `{
+			"url": "` + server.URL + `",
+			"method": "GET",
+			"encoding": "json",
+			"accepted_statuscodes": ["2XX"],
+			"authMethod": "none"
+		}`,
+	}
+
+	result := executor.Execute(context.Background(), monitor, nil)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status)
+	assert.Equal(t, http.StatusOK, result.Metadata[MetadataStatusCode])
+	assert.Equal(t, 5, result.Metadata[MetadataResponseSize])
+
+	// Rejected status codes are still reported
+	monitor.Config = strings.Replace(monitor.Config, server.URL, server.URL+"/missing", 1)
+	result = executor.Execute(context.Background(), monitor, nil)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Equal(t, http.StatusNotFound, result.Metadata[MetadataStatusCode])
+}
+
 func TestHTTPExecutor_Execute_InvalidConfig(t *testing.T) {
 	// Setup
 	logger := zap.NewNop().Sugar()
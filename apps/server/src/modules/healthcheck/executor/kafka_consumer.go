@@ -0,0 +1,268 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"peekaping/src/modules/shared"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM/sarama"
+	"go.uber.org/zap"
+)
+
+type KafkaConsumerConfig struct {
+	Brokers []string `json:"brokers" validate:"required" example:"[\"localhost:9092\"]"`
+	Topic   string   `json:"topic" validate:"required" example:"orders"`
+	// ConsumerGroup enables the lag check of the group on the topic
+	ConsumerGroup string `json:"consumer_group" example:"order-processor"`
+	// MaxLag is the total lag over all partitions above which the monitor is DOWN, 0 only reports it
+	MaxLag      int64                    `json:"max_lag" validate:"min=0" example:"1000"`
+	SSL         bool                     `json:"ssl" example:"false"`
+	SASLOptions KafkaProducerSASLOptions `json:"sasl_options"`
+}
+
+type KafkaConsumerExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewKafkaConsumerExecutor(logger *zap.SugaredLogger) *KafkaConsumerExecutor {
+	return &KafkaConsumerExecutor{
+		logger: logger,
+	}
+}
+
+func (k *KafkaConsumerExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[KafkaConsumerConfig](configJSON)
+}
+
+func (k *KafkaConsumerExecutor) Validate(configJSON string) error {
+	cfg, err := k.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+
+	kafkaCfg := cfg.(*KafkaConsumerConfig)
+
+	if err := validateKafkaBrokers(kafkaCfg.Brokers); err != nil {
+		return err
+	}
+
+	// Validate topic name
+	if strings.TrimSpace(kafkaCfg.Topic) == "" {
+		return fmt.Errorf("topic name cannot be empty")
+	}
+
+	if kafkaCfg.MaxLag > 0 && strings.TrimSpace(kafkaCfg.ConsumerGroup) == "" {
+		return fmt.Errorf("consumer_group is required when max_lag is set")
+	}
+
+	if err := validateKafkaSASL(kafkaCfg.SASLOptions); err != nil {
+		return err
+	}
+
+	return GenericValidator(kafkaCfg)
+}
+
+func (k *KafkaConsumerExecutor) Execute(ctx context.Context, monitor *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := k.Unmarshal(monitor.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*KafkaConsumerConfig)
+
+	k.logger.Debugf("execute kafka consumer cfg: %s %s %s", cfg.Brokers, cfg.Topic, cfg.ConsumerGroup)
+
+	startTime := time.Now().UTC()
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	config := sarama.NewConfig()
+	config.Net.DialTimeout = timeout
+	config.Net.ReadTimeout = timeout
+	config.Net.WriteTimeout = timeout
+	// Only the monitored topic is needed, and the monitor retries on its own
+	config.Metadata.Full = false
+	config.Metadata.Retry.Max = 0
+	config.Metadata.AllowAutoTopicCreation = false
+
+	if err := configureKafkaClient(config, monitor.ID, cfg.SSL, cfg.SASLOptions); err != nil {
+		return DownResult(err, startTime, time.Now().UTC())
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// sarama does not take a context, the check is abandoned when the monitor times out and the
+	// client is closed once its own timeouts expire
+	checkDone := make(chan *Result, 1)
+	go func() {
+		checkDone <- k.check(cfg, config, startTime)
+	}()
+
+	select {
+	case <-checkCtx.Done():
+		k.logger.Infof("Kafka consumer check timeout: %s", monitor.Name)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("Kafka check timeout after %ds", monitor.Timeout),
+			StartTime: startTime,
+			EndTime:   time.Now().UTC(),
+		}
+	case result := <-checkDone:
+		if result.Status == shared.MonitorStatusUp {
+			k.logger.Infof("Kafka consumer check successful: %s, %s", monitor.Name, result.Message)
+		} else {
+			k.logger.Infof("Kafka consumer check failed: %s, %s", monitor.Name, result.Message)
+		}
+		return result
+	}
+}
+
+func (k *KafkaConsumerExecutor) check(cfg *KafkaConsumerConfig, config *sarama.Config, startTime time.Time) *Result {
+	client, err := sarama.NewClient(cfg.Brokers, config)
+	if err != nil {
+		return DownResult(fmt.Errorf("failed to connect to Kafka: %w", err), startTime, time.Now().UTC())
+	}
+	defer func() {
+		if closeErr := client.Close(); closeErr != nil {
+			k.logger.Debugf("Error closing Kafka client: %v", closeErr)
+		}
+	}()
+
+	partitions, err := client.Partitions(cfg.Topic)
+	if errors.Is(err, sarama.ErrUnknownTopicOrPartition) {
+		return DownResult(fmt.Errorf("topic '%s' is not available: %w", cfg.Topic, err), startTime, time.Now().UTC())
+	}
+	if err != nil {
+		return DownResult(fmt.Errorf("failed to fetch metadata from Kafka: %w", err), startTime, time.Now().UTC())
+	}
+	slices.Sort(partitions)
+
+	// A partition without leader can not be read or written, one whose in-sync replicas fell
+	// behind its replicas still works but loses redundancy
+	offline := []int32{}
+	underReplicated := []int32{}
+	for _, partition := range partitions {
+		if _, err := client.Leader(cfg.Topic, partition); err != nil {
+			offline = append(offline, partition)
+			continue
+		}
+		replicas, _ := client.Replicas(cfg.Topic, partition)
+		isr, _ := client.InSyncReplicas(cfg.Topic, partition)
+		if len(isr) < len(replicas) {
+			underReplicated = append(underReplicated, partition)
+		}
+	}
+
+	metadata := Metadata{
+		"brokers":                     len(client.Brokers()),
+		"partitions":                  len(partitions),
+		"offline_partitions":          offline,
+		"under_replicated_partitions": underReplicated,
+	}
+
+	result := &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("Topic '%s' has %d partitions on %d brokers", cfg.Topic, len(partitions), len(client.Brokers())),
+		StartTime: startTime,
+		Metadata:  metadata,
+	}
+
+	var lag int64
+	if cfg.ConsumerGroup != "" {
+		var partitionLag map[string]int64
+		lag, partitionLag, err = k.consumerLag(client, cfg.ConsumerGroup, cfg.Topic, partitions, offline, metadata)
+		if err != nil {
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   err.Error(),
+				StartTime: startTime,
+				EndTime:   time.Now().UTC(),
+				Metadata:  metadata,
+			}
+		}
+		metadata["lag"] = lag
+		metadata["partition_lag"] = partitionLag
+		result.Message += fmt.Sprintf(", consumer group '%s' lag is %d", cfg.ConsumerGroup, lag)
+	}
+	result.EndTime = time.Now().UTC()
+
+	switch {
+	case len(offline) > 0:
+		result.Status = shared.MonitorStatusDown
+		result.Message = fmt.Sprintf("%d of %d partitions of topic '%s' are offline: %v", len(offline), len(partitions), cfg.Topic, offline)
+	case cfg.MaxLag > 0 && lag > cfg.MaxLag:
+		result.Status = shared.MonitorStatusDown
+		result.Message = fmt.Sprintf("Consumer group '%s' lag is %d, above the threshold of %d", cfg.ConsumerGroup, lag, cfg.MaxLag)
+	case len(underReplicated) > 0:
+		result.Status = shared.MonitorStatusDegraded
+		result.Message = fmt.Sprintf("%d of %d partitions of topic '%s' are under-replicated: %v", len(underReplicated), len(partitions), cfg.Topic, underReplicated)
+	}
+
+	return result
+}
+
+// consumerLag sums the difference between the newest offset and the committed offset of the
+// group over the online partitions. Partitions without committed offset count from the oldest
+// retained message
+func (k *KafkaConsumerExecutor) consumerLag(client sarama.Client, group, topic string, partitions, offline []int32, metadata Metadata) (int64, map[string]int64, error) {
+	// Closing the admin would close the shared client
+	admin, err := sarama.NewClusterAdminFromClient(client)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create Kafka admin: %w", err)
+	}
+
+	groups, err := admin.DescribeConsumerGroups([]string{group})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to describe consumer group '%s': %w", group, err)
+	}
+	if len(groups) == 0 || groups[0].State == "Dead" {
+		return 0, nil, fmt.Errorf("consumer group '%s' does not exist", group)
+	}
+	if !errors.Is(groups[0].Err, sarama.ErrNoError) {
+		return 0, nil, fmt.Errorf("failed to describe consumer group '%s': %w", group, groups[0].Err)
+	}
+	metadata["group_state"] = groups[0].State
+	metadata["group_members"] = len(groups[0].Members)
+
+	offsets, err := admin.ListConsumerGroupOffsets(group, map[string][]int32{topic: partitions})
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to fetch offsets of consumer group '%s': %w", group, err)
+	}
+	if !errors.Is(offsets.Err, sarama.ErrNoError) {
+		return 0, nil, fmt.Errorf("failed to fetch offsets of consumer group '%s': %w", group, offsets.Err)
+	}
+
+	var total int64
+	partitionLag := make(map[string]int64, len(partitions))
+	for _, partition := range partitions {
+		if slices.Contains(offline, partition) {
+			continue
+		}
+
+		committed := int64(-1)
+		if block := offsets.GetBlock(topic, partition); block != nil && errors.Is(block.Err, sarama.ErrNoError) {
+			committed = block.Offset
+		}
+
+		newest, err := client.GetOffset(topic, partition, sarama.OffsetNewest)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to fetch newest offset of partition %d: %w", partition, err)
+		}
+		if committed < 0 {
+			committed, err = client.GetOffset(topic, partition, sarama.OffsetOldest)
+			if err != nil {
+				return 0, nil, fmt.Errorf("failed to fetch oldest offset of partition %d: %w", partition, err)
+			}
+		}
+
+		lag := max(newest-committed, 0)
+		partitionLag[strconv.Itoa(int(partition))] = lag
+		total += lag
+	}
+
+	return total, partitionLag, nil
+}
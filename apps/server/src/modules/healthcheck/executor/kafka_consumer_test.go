@@ -0,0 +1,265 @@
+package executor
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type kafkaTestPartition struct {
+	leader   int32
+	replicas []int32
+	isr      []int32
+	newest   int64
+	oldest   int64
+	// committed is the offset of the "processor" group, -1 when it has none
+	committed int64
+}
+
+// startKafkaBroker serves the "orders" topic with the given partitions and the "processor"
+// consumer group on a mock broker with ID 1
+func startKafkaBroker(t *testing.T, partitions []kafkaTestPartition) *sarama.MockBroker {
+	t.Helper()
+
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+
+	metadata := &sarama.MetadataResponse{Version: sarama.NewMetadataRequest(sarama.NewConfig().Version, nil).Version}
+	metadata.AddBroker(broker.Addr(), 1)
+	metadata.ControllerID = 1
+	offsets := sarama.NewMockOffsetResponse(t)
+	groupOffsets := sarama.NewMockOffsetFetchResponse(t)
+	for i, p := range partitions {
+		partition := int32(i)
+		kerr := sarama.ErrNoError
+		if p.leader < 0 {
+			kerr = sarama.ErrLeaderNotAvailable
+		}
+		metadata.AddTopicPartition("orders", partition, p.leader, p.replicas, p.isr, nil, kerr)
+		offsets.SetOffset("orders", partition, sarama.OffsetNewest, p.newest).
+			SetOffset("orders", partition, sarama.OffsetOldest, p.oldest)
+		groupOffsets.SetOffset("processor", "orders", partition, p.committed, "", sarama.ErrNoError)
+	}
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockWrapper(metadata),
+		"OffsetRequest":   offsets,
+		"FindCoordinatorRequest": sarama.NewMockFindCoordinatorResponse(t).
+			SetCoordinator(sarama.CoordinatorGroup, "processor", broker).
+			SetCoordinator(sarama.CoordinatorGroup, "unknown", broker),
+		"DescribeGroupsRequest": sarama.NewMockDescribeGroupsResponse(t).
+			AddGroupDescription("processor", &sarama.GroupDescription{GroupId: "processor", State: "Stable"}),
+		"OffsetFetchRequest": groupOffsets,
+	})
+
+	return broker
+}
+
+func TestKafkaConsumerExecutor_Validate(t *testing.T) {
+	executor := NewKafkaConsumerExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid topic check",
+			config:    `{"brokers":["localhost:9092"],"topic":"orders","sasl_options":{"mechanism":"None"}}`,
+			wantError: false,
+		},
+		{
+			name:      "valid lag check with SASL",
+			config:    `{"brokers":["localhost:9092"],"topic":"orders","consumer_group":"processor","max_lag":1000,"ssl":true,"sasl_options":{"mechanism":"SCRAM-SHA-512","username":"monitor","password":"secret"}}`,
+			wantError: false,
+		},
+		{
+			name:      "invalid broker",
+			config:    `{"brokers":["localhost"],"topic":"orders","sasl_options":{"mechanism":"None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "missing topic",
+			config:    `{"brokers":["localhost:9092"],"sasl_options":{"mechanism":"None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "max lag without consumer group",
+			config:    `{"brokers":["localhost:9092"],"topic":"orders","max_lag":10,"sasl_options":{"mechanism":"None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "negative max lag",
+			config:    `{"brokers":["localhost:9092"],"topic":"orders","consumer_group":"processor","max_lag":-1,"sasl_options":{"mechanism":"None"}}`,
+			wantError: true,
+		},
+		{
+			name:      "SASL without password",
+			config:    `{"brokers":["localhost:9092"],"topic":"orders","sasl_options":{"mechanism":"PLAIN","username":"monitor"}}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestKafkaConsumerExecutor_Execute(t *testing.T) {
+	healthy := []kafkaTestPartition{
+		{leader: 1, replicas: []int32{1}, isr: []int32{1}, newest: 100, oldest: 0, committed: 80},
+		{leader: 1, replicas: []int32{1}, isr: []int32{1}, newest: 50, oldest: 40, committed: -1},
+	}
+
+	tests := []struct {
+		name        string
+		partitions  []kafkaTestPartition
+		config      map[string]any
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+		wantLag     any
+	}{
+		{
+			name:        "healthy topic",
+			partitions:  healthy,
+			config:      map[string]any{"topic": "orders"},
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "Topic 'orders' has 2 partitions on 1 brokers",
+		},
+		{
+			name:        "lag within threshold",
+			partitions:  healthy,
+			config:      map[string]any{"topic": "orders", "consumer_group": "processor", "max_lag": 100},
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "Topic 'orders' has 2 partitions on 1 brokers, consumer group 'processor' lag is 30",
+			wantLag:     int64(30),
+		},
+		{
+			name:        "lag above threshold",
+			partitions:  healthy,
+			config:      map[string]any{"topic": "orders", "consumer_group": "processor", "max_lag": 25},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "Consumer group 'processor' lag is 30, above the threshold of 25",
+			wantLag:     int64(30),
+		},
+		{
+			name:        "unknown consumer group",
+			partitions:  healthy,
+			config:      map[string]any{"topic": "orders", "consumer_group": "unknown"},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "consumer group 'unknown' does not exist",
+		},
+		{
+			name:        "unknown topic",
+			partitions:  healthy,
+			config:      map[string]any{"topic": "payments"},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "topic 'payments' is not available",
+		},
+		{
+			name: "under-replicated partition",
+			partitions: []kafkaTestPartition{
+				{leader: 1, replicas: []int32{1, 2}, isr: []int32{1, 2}, newest: 10, committed: 10},
+				{leader: 1, replicas: []int32{1, 2}, isr: []int32{1}, newest: 10, committed: 10},
+			},
+			config:      map[string]any{"topic": "orders", "consumer_group": "processor", "max_lag": 5},
+			wantStatus:  shared.MonitorStatusDegraded,
+			wantMessage: "1 of 2 partitions of topic 'orders' are under-replicated: [1]",
+			wantLag:     int64(0),
+		},
+		{
+			name: "offline partition",
+			partitions: []kafkaTestPartition{
+				{leader: 1, replicas: []int32{1}, isr: []int32{1}, newest: 10, committed: 10},
+				{leader: -1, replicas: []int32{2}, isr: []int32{}, newest: 10, committed: 0},
+			},
+			config:      map[string]any{"topic": "orders", "consumer_group": "processor"},
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "1 of 2 partitions of topic 'orders' are offline: [1]",
+			wantLag:     int64(0),
+		},
+	}
+
+	executor := NewKafkaConsumerExecutor(zap.NewNop().Sugar())
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := startKafkaBroker(t, tt.partitions)
+
+			tt.config["brokers"] = []string{broker.Addr()}
+			tt.config["sasl_options"] = map[string]any{"mechanism": "None"}
+			config, _ := json.Marshal(tt.config)
+			require.NoError(t, executor.Validate(string(config)))
+
+			result := executor.Execute(context.Background(), &Monitor{
+				ID:      "kafka-monitor",
+				Type:    "kafka-consumer",
+				Name:    "Kafka Consumer Monitor",
+				Timeout: 5,
+				Config:  string(config),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+			if tt.wantLag != nil {
+				assert.Equal(t, tt.wantLag, result.Metadata["lag"])
+				assert.Equal(t, "Stable", result.Metadata["group_state"])
+			}
+		})
+	}
+}
+
+func TestKafkaConsumerExecutor_UnderReplicatedMessage(t *testing.T) {
+	broker := startKafkaBroker(t, []kafkaTestPartition{
+		{leader: 1, replicas: []int32{1, 2}, isr: []int32{1, 2}, newest: 10},
+		{leader: 1, replicas: []int32{1, 2}, isr: []int32{1}, newest: 10},
+	})
+
+	executor := NewKafkaConsumerExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		ID:      "kafka-monitor",
+		Type:    "kafka-consumer",
+		Name:    "Kafka Consumer Monitor",
+		Timeout: 5,
+		Config:  `{"brokers":["` + broker.Addr() + `"],"topic":"orders","sasl_options":{"mechanism":"None"}}`,
+	}, nil)
+
+	// The message is stored as it is, degraded results of the executor carry no response time
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDegraded, result.Status)
+	assert.Equal(t, "1 of 2 partitions of topic 'orders' are under-replicated: [1]", result.Message)
+}
+
+func TestKafkaConsumerExecutor_ExecuteUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	executor := NewKafkaConsumerExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "kafka-consumer",
+		Name:    "Kafka Consumer Monitor",
+		Timeout: 2,
+		Config:  `{"brokers":["` + addr + `"],"topic":"orders","sasl_options":{"mechanism":"None"}}`,
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "failed to fetch metadata from Kafka")
+}
@@ -49,20 +49,8 @@ func (k *KafkaProducerExecutor) Validate(configJSON string) error {
 
 	kafkaCfg := cfg.(*KafkaProducerConfig)
 
-	// Validate brokers list is not empty
-	if len(kafkaCfg.Brokers) == 0 {
-		return fmt.Errorf("brokers list cannot be empty")
-	}
-
-	// Validate each broker address format
-	for _, broker := range kafkaCfg.Brokers {
-		if strings.TrimSpace(broker) == "" {
-			return fmt.Errorf("broker address cannot be empty")
-		}
-		// Basic validation for host:port format
-		if !strings.Contains(broker, ":") {
-			return fmt.Errorf("broker address must be in host:port format: %s", broker)
-		}
+	if err := validateKafkaBrokers(kafkaCfg.Brokers); err != nil {
+		return err
 	}
 
 	// Validate topic name
@@ -75,14 +63,8 @@ func (k *KafkaProducerExecutor) Validate(configJSON string) error {
 		return fmt.Errorf("message cannot be empty")
 	}
 
-	// Validate SASL mechanism if provided
-	if kafkaCfg.SASLOptions.Mechanism != "" && kafkaCfg.SASLOptions.Mechanism != "None" {
-		if kafkaCfg.SASLOptions.Username == "" {
-			return fmt.Errorf("username is required when SASL mechanism is specified")
-		}
-		if kafkaCfg.SASLOptions.Password == "" {
-			return fmt.Errorf("password is required when SASL mechanism is specified")
-		}
+	if err := validateKafkaSASL(kafkaCfg.SASLOptions); err != nil {
+		return err
 	}
 
 	return GenericValidator(kafkaCfg)
@@ -107,35 +89,11 @@ func (k *KafkaProducerExecutor) Execute(ctx context.Context, monitor *Monitor, p
 	config.Producer.Timeout = time.Duration(monitor.Timeout) * time.Second
 	config.Metadata.AllowAutoTopicCreation = cfg.AllowAutoTopicCreation
 
-	// Configure SSL if enabled
-	if cfg.SSL {
-		config.Net.TLS.Enable = true
-		config.Net.TLS.Config = &tls.Config{
-			InsecureSkipVerify: false,
-		}
+	// Configure SSL, SASL and the client ID
+	if err := configureKafkaClient(config, monitor.ID, cfg.SSL, cfg.SASLOptions); err != nil {
+		return DownResult(err, startTime, time.Now().UTC())
 	}
 
-	// Configure SASL if specified
-	if cfg.SASLOptions.Mechanism != "" && cfg.SASLOptions.Mechanism != "None" {
-		config.Net.SASL.Enable = true
-		config.Net.SASL.User = cfg.SASLOptions.Username
-		config.Net.SASL.Password = cfg.SASLOptions.Password
-
-		switch cfg.SASLOptions.Mechanism {
-		case "PLAIN":
-			config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
-		case "SCRAM-SHA-256":
-			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
-		case "SCRAM-SHA-512":
-			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
-		default:
-			return DownResult(fmt.Errorf("unsupported SASL mechanism: %s", cfg.SASLOptions.Mechanism), startTime, time.Now().UTC())
-		}
-	}
-
-	// Set client ID
-	config.ClientID = fmt.Sprintf("peekaping-monitor-%s", monitor.ID)
-
 	// Create producer
 	producer, err := sarama.NewSyncProducer(cfg.Brokers, config)
 	if err != nil {
@@ -206,3 +164,68 @@ func (k *KafkaProducerExecutor) Execute(ctx context.Context, monitor *Monitor, p
 		}
 	}
 }
+
+func validateKafkaBrokers(brokers []string) error {
+	// Validate brokers list is not empty
+	if len(brokers) == 0 {
+		return fmt.Errorf("brokers list cannot be empty")
+	}
+
+	// Validate each broker address format
+	for _, broker := range brokers {
+		if strings.TrimSpace(broker) == "" {
+			return fmt.Errorf("broker address cannot be empty")
+		}
+		// Basic validation for host:port format
+		if !strings.Contains(broker, ":") {
+			return fmt.Errorf("broker address must be in host:port format: %s", broker)
+		}
+	}
+	return nil
+}
+
+func validateKafkaSASL(options KafkaProducerSASLOptions) error {
+	// Validate SASL mechanism if provided
+	if options.Mechanism != "" && options.Mechanism != "None" {
+		if options.Username == "" {
+			return fmt.Errorf("username is required when SASL mechanism is specified")
+		}
+		if options.Password == "" {
+			return fmt.Errorf("password is required when SASL mechanism is specified")
+		}
+	}
+	return nil
+}
+
+// configureKafkaClient applies the SSL and SASL options shared by the Kafka monitors
+func configureKafkaClient(config *sarama.Config, monitorID string, ssl bool, options KafkaProducerSASLOptions) error {
+	// Configure SSL if enabled
+	if ssl {
+		config.Net.TLS.Enable = true
+		config.Net.TLS.Config = &tls.Config{
+			InsecureSkipVerify: false,
+		}
+	}
+
+	// Configure SASL if specified
+	if options.Mechanism != "" && options.Mechanism != "None" {
+		config.Net.SASL.Enable = true
+		config.Net.SASL.User = options.Username
+		config.Net.SASL.Password = options.Password
+
+		switch options.Mechanism {
+		case "PLAIN":
+			config.Net.SASL.Mechanism = sarama.SASLTypePlaintext
+		case "SCRAM-SHA-256":
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA256
+		case "SCRAM-SHA-512":
+			config.Net.SASL.Mechanism = sarama.SASLTypeSCRAMSHA512
+		default:
+			return fmt.Errorf("unsupported SASL mechanism: %s", options.Mechanism)
+		}
+	}
+
+	// Set client ID
+	config.ClientID = fmt.Sprintf("peekaping-monitor-%s", monitorID)
+	return nil
+}
@@ -0,0 +1,264 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/shared"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"go.uber.org/zap"
+)
+
+type LDAPConfig struct {
+	Host string `json:"host" validate:"required" example:"dc01.example.com"`
+	// Port defaults to 636 for tls and 389 otherwise
+	Port            int    `json:"port" validate:"omitempty,min=1,max=65535" example:"389"`
+	Security        string `json:"security" validate:"omitempty,oneof=none starttls tls" example:"starttls"`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors" example:"false"`
+	CheckCertExpiry bool   `json:"check_cert_expiry" example:"true"`
+	// BindDN and BindPassword authenticate a service account, the bind is anonymous without them
+	BindDN       string `json:"bind_dn" validate:"omitempty" example:"CN=svc-monitor,OU=Service Accounts,DC=example,DC=com"`
+	BindPassword string `json:"bind_password" validate:"omitempty" example:"secret"`
+
+	// Search fields, the search only runs when BaseDN is set
+	BaseDN     string `json:"base_dn" validate:"omitempty" example:"DC=example,DC=com"`
+	Filter     string `json:"filter" validate:"omitempty" example:"(sAMAccountName=svc-monitor)"`
+	Scope      string `json:"scope" validate:"omitempty,oneof=base one sub" example:"sub"`
+	MinEntries int    `json:"min_entries" validate:"omitempty,min=0" example:"1"`
+	// Attribute and ExpectedValue require an entry whose attribute has the value
+	Attribute     string `json:"attribute" validate:"omitempty" example:"userAccountControl"`
+	ExpectedValue string `json:"expected_value" validate:"omitempty" example:"512"`
+}
+
+var ldapScopes = map[string]int{
+	"base": ldap.ScopeBaseObject,
+	"one":  ldap.ScopeSingleLevel,
+	"sub":  ldap.ScopeWholeSubtree,
+}
+
+// ldapSession is what an LDAP server revealed during a check
+type ldapSession struct {
+	tlsInfo *certificate.TLSInfo
+	entries []*ldap.Entry
+}
+
+type LDAPExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewLDAPExecutor(logger *zap.SugaredLogger) *LDAPExecutor {
+	return &LDAPExecutor{
+		logger: logger,
+	}
+}
+
+func (s *LDAPExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[LDAPConfig](configJSON)
+}
+
+func (s *LDAPExecutor) Validate(configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*LDAPConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	if (cfg.BindDN == "") != (cfg.BindPassword == "") {
+		return errors.New("bind_dn and bind_password must be set together")
+	}
+	if cfg.BindDN != "" && (cfg.Security == "" || cfg.Security == "none") {
+		return errors.New("bind as a service account requires starttls or tls security, credentials are never sent in plain text")
+	}
+	if cfg.BaseDN == "" && (cfg.Filter != "" || cfg.MinEntries > 0 || cfg.Attribute != "") {
+		return errors.New("search fields require base_dn")
+	}
+	if cfg.ExpectedValue != "" && cfg.Attribute == "" {
+		return errors.New("expected_value requires attribute")
+	}
+	if cfg.Filter != "" {
+		if _, err := ldap.CompileFilter(cfg.Filter); err != nil {
+			return fmt.Errorf("invalid filter: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *LDAPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := s.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*LDAPConfig)
+
+	if cfg.Port == 0 {
+		cfg.Port = 389
+		if cfg.Security == "tls" {
+			cfg.Port = 636
+		}
+	}
+
+	s.logger.Debugf("execute ldap cfg: %s:%d, security %q, bind dn %q", cfg.Host, cfg.Port, cfg.Security, cfg.BindDN)
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now().UTC()
+	session, err := s.check(ctx, cfg, timeout)
+	endTime := time.Now().UTC()
+
+	var tlsInfo *certificate.TLSInfo
+	if session != nil {
+		tlsInfo = session.tlsInfo
+	}
+
+	if err != nil {
+		s.logger.Infof("LDAP check failed: %s, %s", m.Name, err.Error())
+		result := DownResult(fmt.Errorf("LDAP check failed: %w", err), startTime, endTime)
+		result.TLSInfo = tlsInfo
+		return result
+	}
+
+	message := "LDAP anonymous bind successful"
+	if cfg.BindDN != "" {
+		message = fmt.Sprintf("LDAP bind successful as %s", cfg.BindDN)
+	}
+	if tlsInfo != nil {
+		message += " over TLS"
+	}
+
+	metadata := Metadata{}
+	if cfg.BaseDN != "" {
+		metadata["entries"] = len(session.entries)
+		message += fmt.Sprintf(", %d entries found", len(session.entries))
+	}
+
+	s.logger.Infof("LDAP check successful: %s, %s", m.Name, message)
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+		TLSInfo:   tlsInfo,
+		Metadata:  metadata,
+	}
+}
+
+// check connects to the server, switches to TLS as configured, binds and runs the search
+func (s *LDAPExecutor) check(ctx context.Context, cfg *LDAPConfig, timeout time.Duration) (*ldapSession, error) {
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer func() { conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	session := &ldapSession{}
+
+	if cfg.Security == "starttls" {
+		if err := ldapStartTLS(conn); err != nil {
+			return session, fmt.Errorf("StartTLS failed: %w", err)
+		}
+	}
+	if cfg.Security == "starttls" || cfg.Security == "tls" {
+		tlsConn, tlsInfo, err := handshakeTLS(ctx, conn, cfg.Host, cfg.IgnoreTlsErrors)
+		session.tlsInfo = tlsInfo
+		if err != nil {
+			return session, err
+		}
+		conn = tlsConn
+	}
+
+	client := ldap.NewConn(conn, cfg.Security == "tls")
+	client.SetTimeout(timeout)
+	client.Start()
+	defer client.Close()
+
+	if cfg.BindDN != "" {
+		err = client.Bind(cfg.BindDN, cfg.BindPassword)
+	} else {
+		err = client.UnauthenticatedBind("")
+	}
+	if err != nil {
+		return session, fmt.Errorf("bind failed: %w", err)
+	}
+
+	if cfg.BaseDN == "" {
+		return session, nil
+	}
+
+	filter := cfg.Filter
+	if filter == "" {
+		filter = "(objectClass=*)"
+	}
+	scope, ok := ldapScopes[cfg.Scope]
+	if !ok {
+		scope = ldap.ScopeWholeSubtree
+	}
+
+	var attributes []string
+	if cfg.Attribute != "" {
+		attributes = []string{cfg.Attribute}
+	}
+
+	result, err := client.Search(ldap.NewSearchRequest(
+		cfg.BaseDN, scope, ldap.NeverDerefAliases, 0, int(timeout.Seconds()), false,
+		filter, attributes, nil,
+	))
+	// Servers cap the number of entries they return, the entries up to the cap still count
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return session, fmt.Errorf("search failed: %w", err)
+	}
+	if result != nil {
+		session.entries = result.Entries
+	}
+
+	if len(session.entries) < cfg.MinEntries {
+		return session, fmt.Errorf("search returned %d entries, expected at least %d", len(session.entries), cfg.MinEntries)
+	}
+
+	if cfg.Attribute != "" {
+		if err := checkLDAPAttribute(session.entries, cfg.Attribute, cfg.ExpectedValue); err != nil {
+			return session, err
+		}
+	}
+
+	return session, nil
+}
+
+// checkLDAPAttribute requires an entry with the attribute, holding the expected value if given
+func checkLDAPAttribute(entries []*ldap.Entry, attribute, expected string) error {
+	var seen []string
+	for _, entry := range entries {
+		values := entry.GetEqualFoldAttributeValues(attribute)
+		if len(values) == 0 {
+			continue
+		}
+		if expected == "" || slices.Contains(values, expected) {
+			return nil
+		}
+		seen = append(seen, values...)
+	}
+
+	if len(seen) == 0 {
+		return fmt.Errorf("no entry has the attribute %s", attribute)
+	}
+	return fmt.Errorf("attribute %s is %s, expected %s", attribute, strings.Join(seen, ", "), expected)
+}
@@ -0,0 +1,281 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// ldapTestEntries is the directory served by fakeLDAP, every search returns all of it
+var ldapTestEntries = []*ldap.Entry{
+	ldap.NewEntry("CN=svc-monitor,DC=example,DC=com", map[string][]string{"userAccountControl": {"512"}}),
+	ldap.NewEntry("CN=backup,DC=example,DC=com", map[string][]string{"userAccountControl": {"514"}}),
+}
+
+func ldapResult(messageID int64, tag ber.Tag, code uint16, entry *ldap.Entry) *ber.Packet {
+	message := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "MessageID"))
+
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	if entry != nil {
+		response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.DN, "DN"))
+		attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for _, attribute := range entry.Attributes {
+			pair := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			pair.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, attribute.Name, "Type"))
+			values := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range attribute.Values {
+				values.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			pair.AppendChild(values)
+			attributes.AppendChild(pair)
+		}
+		response.AppendChild(attributes)
+	} else {
+		response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, code, "Result Code"))
+		response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+		response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+	}
+	message.AppendChild(response)
+	return message
+}
+
+// fakeLDAP answers StartTLS, simple binds as CN=svc-monitor with password "secret" or
+// anonymous, and searches
+func fakeLDAP(c *mailConn) {
+	for {
+		packet, err := ber.ReadPacket(c.conn)
+		if err != nil || len(packet.Children) < 2 {
+			return
+		}
+		messageID := packet.Children[0].Value.(int64)
+		request := packet.Children[1]
+
+		switch request.Tag {
+		case ldap.ApplicationBindRequest:
+			dn := request.Children[1].Value.(string)
+			password := request.Children[2].Data.String()
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			if (dn == "" && password == "") || (dn == "CN=svc-monitor,DC=example,DC=com" && password == "secret") {
+				code = ldap.LDAPResultSuccess
+			}
+			c.conn.Write(ldapResult(messageID, ldap.ApplicationBindResponse, code, nil).Bytes())
+		case ldap.ApplicationSearchRequest:
+			sizeLimit := int(request.Children[3].Value.(int64))
+			code := uint16(ldap.LDAPResultSuccess)
+			for i, entry := range ldapTestEntries {
+				if sizeLimit > 0 && i >= sizeLimit {
+					code = ldap.LDAPResultSizeLimitExceeded
+					break
+				}
+				c.conn.Write(ldapResult(messageID, ldap.ApplicationSearchResultEntry, 0, entry).Bytes())
+			}
+			c.conn.Write(ldapResult(messageID, ldap.ApplicationSearchResultDone, code, nil).Bytes())
+		case ldap.ApplicationExtendedRequest:
+			c.conn.Write(ldapResult(messageID, ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess, nil).Bytes())
+			c.startTLS()
+		case ldap.ApplicationUnbindRequest:
+			return
+		}
+	}
+}
+
+func TestLDAPExecutor_Validate(t *testing.T) {
+	executor := NewLDAPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid anonymous bind",
+			config:    `{"host":"ldap.example.com"}`,
+			wantError: false,
+		},
+		{
+			name:      "valid service account bind with search",
+			config:    `{"host":"dc01.example.com","security":"starttls","bind_dn":"CN=svc,DC=example,DC=com","bind_password":"secret","base_dn":"DC=example,DC=com","filter":"(objectClass=user)","scope":"one","min_entries":1,"attribute":"mail","expected_value":"svc@example.com"}`,
+			wantError: false,
+		},
+		{
+			name:      "missing host",
+			config:    `{"security":"tls"}`,
+			wantError: true,
+		},
+		{
+			name:      "bind without tls",
+			config:    `{"host":"ldap.example.com","bind_dn":"CN=svc,DC=example,DC=com","bind_password":"secret"}`,
+			wantError: true,
+		},
+		{
+			name:      "bind dn without password",
+			config:    `{"host":"ldap.example.com","security":"tls","bind_dn":"CN=svc,DC=example,DC=com"}`,
+			wantError: true,
+		},
+		{
+			name:      "filter without base dn",
+			config:    `{"host":"ldap.example.com","filter":"(uid=svc)"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid filter",
+			config:    `{"host":"ldap.example.com","base_dn":"DC=example,DC=com","filter":"uid=svc"}`,
+			wantError: true,
+		},
+		{
+			name:      "expected value without attribute",
+			config:    `{"host":"ldap.example.com","base_dn":"DC=example,DC=com","expected_value":"512"}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown scope",
+			config:    `{"host":"ldap.example.com","base_dn":"DC=example,DC=com","scope":"children"}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestLDAPExecutor_Execute(t *testing.T) {
+	executor := NewLDAPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		implicitTLS bool
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+		wantEntries int
+		wantTLS     bool
+	}{
+		{
+			name:        "anonymous bind",
+			config:      `{"host":"127.0.0.1","port":%d}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "LDAP anonymous bind successful",
+			wantEntries: -1,
+		},
+		{
+			name:        "starttls bind and search",
+			config:      `{"host":"127.0.0.1","port":%d,"security":"starttls","ignore_tls_errors":true,"bind_dn":"CN=svc-monitor,DC=example,DC=com","bind_password":"secret","base_dn":"DC=example,DC=com","min_entries":2}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "LDAP bind successful as CN=svc-monitor,DC=example,DC=com over TLS, 2 entries found",
+			wantEntries: 2,
+			wantTLS:     true,
+		},
+		{
+			name:        "ldaps attribute value",
+			implicitTLS: true,
+			config:      `{"host":"127.0.0.1","port":%d,"security":"tls","ignore_tls_errors":true,"base_dn":"DC=example,DC=com","attribute":"useraccountcontrol","expected_value":"514"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "over TLS, 2 entries found",
+			wantEntries: 2,
+			wantTLS:     true,
+		},
+		{
+			name:        "wrong credentials",
+			config:      `{"host":"127.0.0.1","port":%d,"security":"starttls","ignore_tls_errors":true,"bind_dn":"CN=svc-monitor,DC=example,DC=com","bind_password":"wrong"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "bind failed",
+			wantEntries: -1,
+			wantTLS:     true,
+		},
+		{
+			name:        "untrusted certificate",
+			config:      `{"host":"127.0.0.1","port":%d,"security":"starttls"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "certificate verification failed",
+			wantEntries: -1,
+			wantTLS:     true,
+		},
+		{
+			name:        "too few entries",
+			config:      `{"host":"127.0.0.1","port":%d,"base_dn":"DC=example,DC=com","min_entries":3}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "search returned 2 entries, expected at least 3",
+			wantEntries: -1,
+		},
+		{
+			name:        "unexpected attribute value",
+			config:      `{"host":"127.0.0.1","port":%d,"base_dn":"DC=example,DC=com","attribute":"userAccountControl","expected_value":"66048"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "attribute userAccountControl is 512, 514, expected 66048",
+			wantEntries: -1,
+		},
+		{
+			name:        "missing attribute",
+			config:      `{"host":"127.0.0.1","port":%d,"base_dn":"DC=example,DC=com","attribute":"mail"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "no entry has the attribute mail",
+			wantEntries: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := serveMail(t, tt.implicitTLS, fakeLDAP)
+			monitor := &Monitor{
+				ID:      "ldap-monitor",
+				Type:    "ldap",
+				Name:    "LDAP Monitor",
+				Timeout: 5,
+				Config:  fmt.Sprintf(tt.config, port),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+
+			if tt.wantEntries >= 0 {
+				assert.Equal(t, tt.wantEntries, result.Metadata["entries"])
+			}
+
+			if tt.wantTLS {
+				require.NotNil(t, result.TLSInfo)
+				require.NotNil(t, result.TLSInfo.CertInfo)
+				assert.False(t, result.TLSInfo.Valid)
+			} else {
+				assert.Nil(t, result.TLSInfo)
+			}
+		})
+	}
+}
+
+func TestLDAPExecutor_ExecuteConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	executor := NewLDAPExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "ldap",
+		Name:    "LDAP Monitor",
+		Timeout: 2,
+		Config:  fmt.Sprintf(`{"host":"127.0.0.1","port":%d}`, port),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "connection failed")
+}
@@ -0,0 +1,486 @@
+package executor
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/textproto"
+	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type MailConfig struct {
+	Protocol             string   `json:"protocol" validate:"required,oneof=smtp imap pop3" example:"smtp"`
+	Host                 string   `json:"host" validate:"required" example:"mail.example.com"`
+	Port                 int      `json:"port" validate:"required,min=1,max=65535" example:"587"`
+	Security             string   `json:"security" validate:"omitempty,oneof=none starttls tls" example:"starttls"`
+	Username             string   `json:"username" validate:"omitempty" example:"monitor@example.com"`
+	Password             string   `json:"password" validate:"omitempty" example:"secret"`
+	ExpectedBanner       string   `json:"expected_banner" validate:"omitempty" example:"ESMTP"`
+	RequiredCapabilities []string `json:"required_capabilities" validate:"omitempty" example:"SIZE,PIPELINING"`
+	IgnoreTlsErrors      bool     `json:"ignore_tls_errors" example:"false"`
+	CheckCertExpiry      bool     `json:"check_cert_expiry" example:"true"`
+}
+
+// mailSession is what a mail server revealed during a check
+type mailSession struct {
+	banner        string
+	capabilities  []string
+	tlsInfo       *certificate.TLSInfo
+	authenticated bool
+}
+
+type MailExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewMailExecutor(logger *zap.SugaredLogger) *MailExecutor {
+	return &MailExecutor{
+		logger: logger,
+	}
+}
+
+func (s *MailExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[MailConfig](configJSON)
+}
+
+func (s *MailExecutor) Validate(configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	mailCfg := cfg.(*MailConfig)
+	if err := GenericValidator(mailCfg); err != nil {
+		return err
+	}
+
+	if mailCfg.Username != "" && (mailCfg.Security == "" || mailCfg.Security == "none") {
+		return fmt.Errorf("login requires starttls or tls security, credentials are never sent in plain text")
+	}
+	if mailCfg.Password != "" && mailCfg.Username == "" {
+		return fmt.Errorf("username is required when a password is set")
+	}
+
+	return nil
+}
+
+func (s *MailExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := s.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*MailConfig)
+
+	s.logger.Debugf("execute mail cfg: %s %s:%d", cfg.Protocol, cfg.Host, cfg.Port)
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now().UTC()
+	session, err := s.check(ctx, cfg, timeout)
+	endTime := time.Now().UTC()
+
+	var tlsInfo *certificate.TLSInfo
+	if session != nil {
+		tlsInfo = session.tlsInfo
+	}
+
+	if err != nil {
+		s.logger.Infof("Mail check failed: %s, %s", m.Name, err.Error())
+		result := DownResult(fmt.Errorf("%s check failed: %w", strings.ToUpper(cfg.Protocol), err), startTime, endTime)
+		result.TLSInfo = tlsInfo
+		return result
+	}
+
+	s.logger.Infof("Mail check successful: %s", m.Name)
+
+	message := fmt.Sprintf("%s server ready: %s", strings.ToUpper(cfg.Protocol), session.banner)
+	if tlsInfo != nil {
+		message += ", TLS"
+	}
+	if session.authenticated {
+		message += ", login successful"
+	}
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+		TLSInfo:   tlsInfo,
+		Metadata: Metadata{
+			"protocol":     cfg.Protocol,
+			"banner":       session.banner,
+			"capabilities": session.capabilities,
+		},
+	}
+}
+
+// check connects to the mail server and walks through greeting, capabilities, TLS and login
+func (s *MailExecutor) check(ctx context.Context, cfg *MailConfig, timeout time.Duration) (*mailSession, error) {
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("connection failed: %w", err)
+	}
+	defer func() { conn.Close() }()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	session := &mailSession{}
+
+	if cfg.Security == "tls" {
+		tlsConn, tlsInfo, err := handshakeTLS(ctx, conn, cfg.Host, cfg.IgnoreTlsErrors)
+		session.tlsInfo = tlsInfo
+		if err != nil {
+			return session, err
+		}
+		conn = tlsConn
+	}
+
+	// upgrade switches the session to TLS after a successful STARTTLS command
+	upgrade := func() (*textproto.Conn, error) {
+		tlsConn, tlsInfo, err := handshakeTLS(ctx, conn, cfg.Host, cfg.IgnoreTlsErrors)
+		session.tlsInfo = tlsInfo
+		if err != nil {
+			return nil, err
+		}
+		conn = tlsConn
+		return textproto.NewConn(conn), nil
+	}
+
+	text := textproto.NewConn(conn)
+	switch cfg.Protocol {
+	case "smtp":
+		err = checkSMTP(text, cfg, session, upgrade)
+	case "imap":
+		err = checkIMAP(text, cfg, session, upgrade)
+	case "pop3":
+		err = checkPOP3(text, cfg, session, upgrade)
+	default:
+		err = fmt.Errorf("unsupported protocol: %s", cfg.Protocol)
+	}
+	if err != nil {
+		return session, err
+	}
+
+	if cfg.ExpectedBanner != "" && !strings.Contains(session.banner, cfg.ExpectedBanner) {
+		return session, fmt.Errorf("banner %q does not contain %q", session.banner, cfg.ExpectedBanner)
+	}
+	if missing := missingCapabilities(session.capabilities, cfg.RequiredCapabilities); len(missing) > 0 {
+		return session, fmt.Errorf("missing capabilities: %s", strings.Join(missing, ", "))
+	}
+
+	return session, nil
+}
+
+func checkSMTP(text *textproto.Conn, cfg *MailConfig, session *mailSession, upgrade func() (*textproto.Conn, error)) error {
+	_, banner, err := text.ReadResponse(220)
+	if err != nil {
+		return fmt.Errorf("unexpected greeting: %w", err)
+	}
+	session.banner = firstLine(banner)
+
+	ehlo := func() error {
+		id, err := text.Cmd("EHLO localhost")
+		if err != nil {
+			return err
+		}
+		text.StartResponse(id)
+		_, msg, err := text.ReadResponse(250)
+		text.EndResponse(id)
+		if err != nil {
+			return fmt.Errorf("EHLO rejected: %w", err)
+		}
+		// The first line greets the client, the others list the extensions
+		lines := strings.Split(msg, "\n")
+		session.capabilities = lines[1:]
+		return nil
+	}
+
+	if err := ehlo(); err != nil {
+		return err
+	}
+
+	if cfg.Security == "starttls" {
+		if !hasCapability(session.capabilities, "STARTTLS") {
+			return errors.New("server does not offer STARTTLS")
+		}
+		if err := smtpCommand(text, "STARTTLS", 220); err != nil {
+			return err
+		}
+		if text, err = upgrade(); err != nil {
+			return err
+		}
+		if err := ehlo(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Username != "" {
+		if err := smtpAuth(text, cfg, session.capabilities); err != nil {
+			return err
+		}
+		session.authenticated = true
+	}
+
+	// The check already succeeded, a server closing the connection early is not a failure
+	smtpCommand(text, "QUIT", 221)
+	return nil
+}
+
+// smtpAuth logs in with PLAIN, or LOGIN when the server does not offer PLAIN
+func smtpAuth(text *textproto.Conn, cfg *MailConfig, capabilities []string) error {
+	var mechanisms []string
+	for _, capability := range capabilities {
+		fields := strings.Fields(strings.ToUpper(capability))
+		if len(fields) > 0 && fields[0] == "AUTH" {
+			mechanisms = fields[1:]
+		}
+	}
+
+	has := func(mechanism string) bool {
+		for _, m := range mechanisms {
+			if m == mechanism {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case has("PLAIN"):
+		credentials := base64.StdEncoding.EncodeToString([]byte("\x00" + cfg.Username + "\x00" + cfg.Password))
+		if err := smtpCommand(text, "AUTH PLAIN "+credentials, 235); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	case has("LOGIN"):
+		if err := smtpCommand(text, "AUTH LOGIN", 334); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		if err := smtpCommand(text, base64.StdEncoding.EncodeToString([]byte(cfg.Username)), 334); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		if err := smtpCommand(text, base64.StdEncoding.EncodeToString([]byte(cfg.Password)), 235); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	default:
+		return fmt.Errorf("server offers no supported AUTH mechanism (%s)", strings.Join(mechanisms, " "))
+	}
+
+	return nil
+}
+
+func smtpCommand(text *textproto.Conn, command string, expectCode int) error {
+	id, err := text.Cmd("%s", command)
+	if err != nil {
+		return err
+	}
+	text.StartResponse(id)
+	defer text.EndResponse(id)
+	_, _, err = text.ReadResponse(expectCode)
+	return err
+}
+
+func checkIMAP(text *textproto.Conn, cfg *MailConfig, session *mailSession, upgrade func() (*textproto.Conn, error)) error {
+	greeting, err := text.ReadLine()
+	if err != nil {
+		return fmt.Errorf("failed to read greeting: %w", err)
+	}
+	if !strings.HasPrefix(greeting, "* OK") {
+		return fmt.Errorf("unexpected greeting: %s", greeting)
+	}
+	session.banner = strings.TrimSpace(strings.TrimPrefix(greeting, "* OK"))
+
+	tag := 0
+	command := func(format string, args ...any) ([]string, error) {
+		tag++
+		id := fmt.Sprintf("a%d", tag)
+		if err := text.PrintfLine(id+" "+format, args...); err != nil {
+			return nil, err
+		}
+		var untagged []string
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(line, "* ") {
+				untagged = append(untagged, line[2:])
+				continue
+			}
+			if strings.HasPrefix(line, id+" ") {
+				status := strings.TrimPrefix(line, id+" ")
+				if !strings.HasPrefix(status, "OK") {
+					return nil, errors.New(status)
+				}
+				return untagged, nil
+			}
+		}
+	}
+
+	capability := func() error {
+		untagged, err := command("CAPABILITY")
+		if err != nil {
+			return fmt.Errorf("CAPABILITY failed: %w", err)
+		}
+		for _, line := range untagged {
+			if fields := strings.Fields(line); len(fields) > 0 && strings.EqualFold(fields[0], "CAPABILITY") {
+				session.capabilities = fields[1:]
+			}
+		}
+		return nil
+	}
+
+	if err := capability(); err != nil {
+		return err
+	}
+
+	if cfg.Security == "starttls" {
+		if !hasCapability(session.capabilities, "STARTTLS") {
+			return errors.New("server does not offer STARTTLS")
+		}
+		if _, err := command("STARTTLS"); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+		if text, err = upgrade(); err != nil {
+			return err
+		}
+		if err := capability(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Username != "" {
+		if hasCapability(session.capabilities, "LOGINDISABLED") {
+			return errors.New("server does not allow LOGIN")
+		}
+		if _, err := command("LOGIN %s %s", imapQuote(cfg.Username), imapQuote(cfg.Password)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		session.authenticated = true
+	}
+
+	command("LOGOUT")
+	return nil
+}
+
+// imapQuote renders s as an IMAP quoted string
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func checkPOP3(text *textproto.Conn, cfg *MailConfig, session *mailSession, upgrade func() (*textproto.Conn, error)) error {
+	command := func(format string, args ...any) (string, error) {
+		if err := text.PrintfLine(format, args...); err != nil {
+			return "", err
+		}
+		return readPOP3Status(text)
+	}
+
+	greeting, err := readPOP3Status(text)
+	if err != nil {
+		return fmt.Errorf("unexpected greeting: %w", err)
+	}
+	session.banner = greeting
+
+	capa := func() error {
+		if _, err := command("CAPA"); err != nil {
+			// CAPA is optional in POP3, servers without it simply list no capabilities
+			session.capabilities = nil
+			return nil
+		}
+		lines, err := text.ReadDotLines()
+		if err != nil {
+			return fmt.Errorf("CAPA failed: %w", err)
+		}
+		session.capabilities = lines
+		return nil
+	}
+
+	if err := capa(); err != nil {
+		return err
+	}
+
+	if cfg.Security == "starttls" {
+		if !hasCapability(session.capabilities, "STLS") {
+			return errors.New("server does not offer STLS")
+		}
+		if _, err := command("STLS"); err != nil {
+			return fmt.Errorf("STLS failed: %w", err)
+		}
+		if text, err = upgrade(); err != nil {
+			return err
+		}
+		if err := capa(); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Username != "" {
+		if _, err := command("USER %s", cfg.Username); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		if _, err := command("PASS %s", cfg.Password); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+		session.authenticated = true
+	}
+
+	command("QUIT")
+	return nil
+}
+
+func readPOP3Status(text *textproto.Conn) (string, error) {
+	line, err := text.ReadLine()
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return "", errors.New(line)
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, "+OK")), nil
+}
+
+// hasCapability reports whether the capability keyword is advertised, comparing the first
+// word of every capability case-insensitively
+func hasCapability(capabilities []string, keyword string) bool {
+	for _, capability := range capabilities {
+		if strings.EqualFold(capability, keyword) {
+			return true
+		}
+		if fields := strings.Fields(capability); len(fields) > 0 && strings.EqualFold(fields[0], keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func missingCapabilities(capabilities []string, required []string) []string {
+	var missing []string
+	for _, keyword := range required {
+		if !hasCapability(capabilities, keyword) {
+			missing = append(missing, keyword)
+		}
+	}
+	return missing
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
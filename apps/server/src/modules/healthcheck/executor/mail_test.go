@@ -0,0 +1,368 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http/httptest"
+	"net/textproto"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// testCertificate returns a self-signed certificate for 127.0.0.1 and example.com
+func testCertificate() tls.Certificate {
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	return server.TLS.Certificates[0]
+}
+
+// mailConn is the server side of a fake mail session
+type mailConn struct {
+	conn net.Conn
+	text *textproto.Conn
+	cert tls.Certificate
+}
+
+func (c *mailConn) startTLS() {
+	c.conn = tls.Server(c.conn, &tls.Config{Certificates: []tls.Certificate{c.cert}})
+	c.text = textproto.NewConn(c.conn)
+}
+
+func (c *mailConn) send(lines ...string) {
+	for _, line := range lines {
+		c.text.PrintfLine("%s", line)
+	}
+}
+
+// serveMail accepts a single connection and hands it to handle, returning the listening port
+func serveMail(t *testing.T, implicitTLS bool, handle func(c *mailConn)) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	cert := testCertificate()
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		c := &mailConn{conn: conn, text: textproto.NewConn(conn), cert: cert}
+		if implicitTLS {
+			c.startTLS()
+		}
+		defer c.conn.Close()
+		handle(c)
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func fakeSMTP(c *mailConn) {
+	c.send("220 mail.example.com ESMTP ready")
+	secure := false
+	for {
+		line, err := c.text.ReadLine()
+		if err != nil {
+			return
+		}
+		switch {
+		case strings.HasPrefix(line, "EHLO"):
+			if secure {
+				c.send("250-mail.example.com", "250-SIZE 10240000", "250 AUTH PLAIN LOGIN")
+			} else {
+				c.send("250-mail.example.com", "250-SIZE 10240000", "250 STARTTLS")
+			}
+		case line == "STARTTLS":
+			c.send("220 go ahead")
+			c.startTLS()
+			secure = true
+		case strings.HasPrefix(line, "AUTH PLAIN "):
+			// base64 of "\x00user\x00pass"
+			if strings.TrimPrefix(line, "AUTH PLAIN ") == "AHVzZXIAcGFzcw==" {
+				c.send("235 authenticated")
+			} else {
+				c.send("535 invalid credentials")
+			}
+		case line == "QUIT":
+			c.send("221 bye")
+			return
+		default:
+			c.send("502 unknown command")
+		}
+	}
+}
+
+func fakeIMAP(c *mailConn) {
+	c.send("* OK [CAPABILITY IMAP4rev1] Dovecot ready.")
+	for {
+		line, err := c.text.ReadLine()
+		if err != nil {
+			return
+		}
+		tag, command, _ := strings.Cut(line, " ")
+		switch {
+		case command == "CAPABILITY":
+			c.send("* CAPABILITY IMAP4rev1 IDLE AUTH=PLAIN", tag+" OK done")
+		case strings.HasPrefix(command, "LOGIN "):
+			if command == `LOGIN "user" "pa\"ss"` {
+				c.send(tag + " OK logged in")
+			} else {
+				c.send(tag + " NO [AUTHENTICATIONFAILED] invalid credentials")
+			}
+		case command == "LOGOUT":
+			c.send("* BYE", tag+" OK done")
+			return
+		default:
+			c.send(tag + " BAD unknown command")
+		}
+	}
+}
+
+func fakePOP3(c *mailConn) {
+	c.send("+OK POP3 ready")
+	for {
+		line, err := c.text.ReadLine()
+		if err != nil {
+			return
+		}
+		switch {
+		case line == "CAPA":
+			c.send("+OK capability list follows", "USER", "UIDL", "STLS", ".")
+		case line == "STLS":
+			c.send("+OK begin TLS")
+			c.startTLS()
+		case strings.HasPrefix(line, "USER "):
+			c.send("+OK send password")
+		case strings.HasPrefix(line, "PASS "):
+			if line == "PASS pass" {
+				c.send("+OK logged in")
+			} else {
+				c.send("-ERR invalid credentials")
+			}
+		case line == "QUIT":
+			c.send("+OK bye")
+			return
+		default:
+			c.send("-ERR unknown command")
+		}
+	}
+}
+
+func TestMailExecutor_Validate(t *testing.T) {
+	executor := NewMailExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid smtp",
+			config:    `{"protocol":"smtp","host":"mail.example.com","port":25}`,
+			wantError: false,
+		},
+		{
+			name:      "valid imap with login over tls",
+			config:    `{"protocol":"imap","host":"mail.example.com","port":993,"security":"tls","username":"user","password":"pass"}`,
+			wantError: false,
+		},
+		{
+			name:      "unknown protocol",
+			config:    `{"protocol":"nntp","host":"mail.example.com","port":119}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown security",
+			config:    `{"protocol":"smtp","host":"mail.example.com","port":25,"security":"ssl"}`,
+			wantError: true,
+		},
+		{
+			name:      "missing host",
+			config:    `{"protocol":"smtp","port":25}`,
+			wantError: true,
+		},
+		{
+			name:      "login without tls",
+			config:    `{"protocol":"pop3","host":"mail.example.com","port":110,"username":"user","password":"pass"}`,
+			wantError: true,
+		},
+		{
+			name:      "password without username",
+			config:    `{"protocol":"smtp","host":"mail.example.com","port":465,"security":"tls","password":"pass"}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown field",
+			config:    `{"protocol":"smtp","host":"mail.example.com","port":25,"helo":"x"}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMailExecutor_Execute(t *testing.T) {
+	executor := NewMailExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		implicitTLS bool
+		server      func(c *mailConn)
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+		wantTLS     bool
+	}{
+		{
+			name:        "smtp banner and capabilities",
+			server:      fakeSMTP,
+			config:      `{"protocol":"smtp","host":"127.0.0.1","port":%d,"expected_banner":"ESMTP","required_capabilities":["size","STARTTLS"]}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "SMTP server ready: mail.example.com ESMTP ready",
+		},
+		{
+			name:        "smtp unexpected banner",
+			server:      fakeSMTP,
+			config:      `{"protocol":"smtp","host":"127.0.0.1","port":%d,"expected_banner":"Postfix"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "does not contain",
+		},
+		{
+			name:        "smtp missing capability",
+			server:      fakeSMTP,
+			config:      `{"protocol":"smtp","host":"127.0.0.1","port":%d,"required_capabilities":["PIPELINING"]}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "missing capabilities: PIPELINING",
+		},
+		{
+			name:        "smtp starttls and auth",
+			server:      fakeSMTP,
+			config:      `{"protocol":"smtp","host":"127.0.0.1","port":%d,"security":"starttls","ignore_tls_errors":true,"username":"user","password":"pass"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "TLS, login successful",
+			wantTLS:     true,
+		},
+		{
+			name:        "smtp wrong credentials",
+			server:      fakeSMTP,
+			config:      `{"protocol":"smtp","host":"127.0.0.1","port":%d,"security":"starttls","ignore_tls_errors":true,"username":"user","password":"wrong"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "authentication failed",
+			wantTLS:     true,
+		},
+		{
+			name:        "smtp untrusted certificate",
+			server:      fakeSMTP,
+			config:      `{"protocol":"smtp","host":"127.0.0.1","port":%d,"security":"starttls"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "certificate verification failed",
+			wantTLS:     true,
+		},
+		{
+			name:        "imap implicit tls and login",
+			implicitTLS: true,
+			server:      fakeIMAP,
+			config:      `{"protocol":"imap","host":"127.0.0.1","port":%d,"security":"tls","ignore_tls_errors":true,"username":"user","password":"pa\"ss","required_capabilities":["IDLE"]}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "IMAP server ready: [CAPABILITY IMAP4rev1] Dovecot ready., TLS, login successful",
+			wantTLS:     true,
+		},
+		{
+			name:        "imap without starttls",
+			server:      fakeIMAP,
+			config:      `{"protocol":"imap","host":"127.0.0.1","port":%d,"security":"starttls"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "server does not offer STARTTLS",
+		},
+		{
+			name:        "pop3 stls and login",
+			server:      fakePOP3,
+			config:      `{"protocol":"pop3","host":"127.0.0.1","port":%d,"security":"starttls","ignore_tls_errors":true,"username":"user","password":"pass"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "POP3 server ready: POP3 ready, TLS, login successful",
+			wantTLS:     true,
+		},
+		{
+			name: "smtp not ready",
+			server: func(c *mailConn) {
+				c.send("554 no service")
+			},
+			config:      `{"protocol":"smtp","host":"127.0.0.1","port":%d}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "unexpected greeting",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := serveMail(t, tt.implicitTLS, tt.server)
+			monitor := &Monitor{
+				ID:      "mail-monitor",
+				Type:    "mail",
+				Name:    "Mail Monitor",
+				Timeout: 5,
+				Config:  fmt.Sprintf(tt.config, port),
+			}
+
+			result := executor.Execute(context.Background(), monitor, nil)
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+
+			if tt.wantTLS {
+				require.NotNil(t, result.TLSInfo)
+				require.NotNil(t, result.TLSInfo.CertInfo)
+				assert.False(t, result.TLSInfo.Valid)
+			} else {
+				assert.Nil(t, result.TLSInfo)
+			}
+		})
+	}
+}
+
+func TestMailExecutor_ExecuteConnectionRefused(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	executor := NewMailExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "mail",
+		Name:    "Mail Monitor",
+		Timeout: 2,
+		Config:  fmt.Sprintf(`{"protocol":"smtp","host":"127.0.0.1","port":%d}`, port),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "connection failed")
+}
+
+func TestHasCapability(t *testing.T) {
+	capabilities := []string{"SIZE 10240000", "AUTH PLAIN LOGIN", "STARTTLS"}
+
+	assert.True(t, hasCapability(capabilities, "size"))
+	assert.True(t, hasCapability(capabilities, "AUTH PLAIN LOGIN"))
+	assert.True(t, hasCapability(capabilities, "STARTTLS"))
+	assert.False(t, hasCapability(capabilities, "PLAIN"))
+	assert.Equal(t, []string{"8BITMIME"}, missingCapabilities(capabilities, []string{"SIZE", "8BITMIME"}))
+}
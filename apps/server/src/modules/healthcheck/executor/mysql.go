@@ -159,7 +159,7 @@ func (m *MySQLExecutor) Execute(ctx context.Context, monitor *Monitor, proxyMode
 		}
 	}
 
-	message, err := m.mysqlQuery(ctx, cfg.ConnectionString, query, time.Duration(monitor.Timeout)*time.Second)
+	rowCount, err := m.mysqlQuery(ctx, cfg.ConnectionString, query, time.Duration(monitor.Timeout)*time.Second)
 	endTime := time.Now().UTC()
 
 	if err != nil {
@@ -176,23 +176,24 @@ func (m *MySQLExecutor) Execute(ctx context.Context, monitor *Monitor, proxyMode
 	m.logger.Infof("MySQL query successful: %s, ping: %dms", monitor.Name, ping)
 	return &Result{
 		Status:    shared.MonitorStatusUp,
-		Message:   fmt.Sprintf("Query successful, ping: %dms, %s", ping, message),
+		Message:   fmt.Sprintf("Query successful, ping: %dms, Rows: %d", ping, rowCount),
 		StartTime: startTime,
 		EndTime:   endTime,
+		Metadata:  Metadata{MetadataRows: rowCount},
 	}
 }
 
-func (m *MySQLExecutor) mysqlQuery(ctx context.Context, connectionString, query string, timeout time.Duration) (string, error) {
+func (m *MySQLExecutor) mysqlQuery(ctx context.Context, connectionString, query string, timeout time.Duration) (int, error) {
 	// Parse the mysql:// URL format and convert to DSN
 	dsn, err := m.parseMySQLURL(connectionString)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse MySQL connection string: %w", err)
+		return 0, fmt.Errorf("failed to parse MySQL connection string: %w", err)
 	}
 
 	// Open connection
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		return "", fmt.Errorf("failed to open MySQL connection: %w", err)
+		return 0, fmt.Errorf("failed to open MySQL connection: %w", err)
 	}
 	defer db.Close()
 
@@ -202,13 +203,13 @@ func (m *MySQLExecutor) mysqlQuery(ctx context.Context, connectionString, query
 
 	// Test connection
 	if err := db.PingContext(ctx); err != nil {
-		return "", fmt.Errorf("failed to ping MySQL database: %w", err)
+		return 0, fmt.Errorf("failed to ping MySQL database: %w", err)
 	}
 
 	// Execute query
 	rows, err := db.QueryContext(ctx, query)
 	if err != nil {
-		return "", fmt.Errorf("failed to execute query: %w", err)
+		return 0, fmt.Errorf("failed to execute query: %w", err)
 	}
 	defer rows.Close()
 
@@ -219,8 +220,8 @@ func (m *MySQLExecutor) mysqlQuery(ctx context.Context, connectionString, query
 	}
 
 	if err := rows.Err(); err != nil {
-		return "", fmt.Errorf("error while iterating rows: %w", err)
+		return 0, fmt.Errorf("error while iterating rows: %w", err)
 	}
 
-	return fmt.Sprintf("Rows: %d", rowCount), nil
+	return rowCount, nil
 }
@@ -0,0 +1,255 @@
+package executor
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// ntpEpochOffset is the number of seconds between the NTP era 0 (1900) and the Unix epoch
+	ntpEpochOffset = 2208988800
+	ntpPacketSize  = 48
+
+	ntpModeClient = 3
+	ntpModeServer = 4
+	ntpLeapAlarm  = 3
+	// ntpStratumUnsynchronized marks a server without a working time source
+	ntpStratumUnsynchronized = 16
+)
+
+type NTPConfig struct {
+	Host string `json:"host" validate:"required" example:"pool.ntp.org"`
+	Port int    `json:"port" validate:"omitempty,min=1,max=65535" example:"123"`
+	// MaxOffset is the largest difference between the server clock and the Peekaping host
+	// still considered UP
+	MaxOffset *float64 `json:"max_offset_ms" validate:"omitempty,min=0" example:"500"`
+	// MaxStratum is the highest stratum still considered UP, any synchronized stratum by default
+	MaxStratum int `json:"max_stratum" validate:"omitempty,min=1,max=15" example:"4"`
+}
+
+// ntpPacket is the header of an NTP packet (RFC 5905)
+type ntpPacket struct {
+	Leap           uint8
+	Version        uint8
+	Mode           uint8
+	Stratum        uint8
+	RootDelay      time.Duration
+	RootDispersion time.Duration
+	ReferenceID    [4]byte
+	Originate      time.Time
+	Receive        time.Time
+	Transmit       time.Time
+}
+
+func parseNTPPacket(data []byte) (*ntpPacket, error) {
+	if len(data) < ntpPacketSize {
+		return nil, fmt.Errorf("NTP response of %d bytes is too short", len(data))
+	}
+
+	packet := &ntpPacket{
+		Leap:           data[0] >> 6,
+		Version:        (data[0] >> 3) & 0x07,
+		Mode:           data[0] & 0x07,
+		Stratum:        data[1],
+		RootDelay:      ntpShortDuration(binary.BigEndian.Uint32(data[4:8])),
+		RootDispersion: ntpShortDuration(binary.BigEndian.Uint32(data[8:12])),
+		Originate:      ntpTime(binary.BigEndian.Uint64(data[24:32])),
+		Receive:        ntpTime(binary.BigEndian.Uint64(data[32:40])),
+		Transmit:       ntpTime(binary.BigEndian.Uint64(data[40:48])),
+	}
+	copy(packet.ReferenceID[:], data[12:16])
+
+	if packet.Mode != ntpModeServer {
+		return nil, fmt.Errorf("unexpected NTP mode %d in response", packet.Mode)
+	}
+	return packet, nil
+}
+
+// syncError explains why the server clock cannot be trusted, nil when it is synchronized
+func (p *ntpPacket) syncError() error {
+	switch {
+	case p.Stratum == 0:
+		// Kiss-o'-Death packet, the reference ID carries the reason
+		return fmt.Errorf("NTP server sent kiss-o'-death %q", strings.TrimRight(string(p.ReferenceID[:]), "\x00"))
+	case p.Stratum >= ntpStratumUnsynchronized:
+		return fmt.Errorf("NTP server is unsynchronized (stratum %d)", p.Stratum)
+	case p.Leap == ntpLeapAlarm:
+		return errors.New("NTP server clock is not synchronized (leap indicator alarm)")
+	}
+	return nil
+}
+
+// referenceID is the upstream server of secondary servers or the time source of primary ones
+func (p *ntpPacket) referenceID() string {
+	if p.Stratum == 1 {
+		return strings.TrimRight(string(p.ReferenceID[:]), "\x00")
+	}
+	return net.IP(p.ReferenceID[:]).String()
+}
+
+// ntpTime converts a 64-bit NTP timestamp
+func ntpTime(timestamp uint64) time.Time {
+	seconds := int64(timestamp>>32) - ntpEpochOffset
+	fraction := (int64(timestamp&0xffffffff) * int64(time.Second)) >> 32
+	return time.Unix(seconds, fraction)
+}
+
+func toNTPTime(t time.Time) uint64 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := (uint64(t.Nanosecond()) << 32) / uint64(time.Second)
+	return seconds<<32 | fraction
+}
+
+// ntpShortDuration converts a 32-bit NTP short format duration
+func ntpShortDuration(value uint32) time.Duration {
+	return time.Duration((int64(value) * int64(time.Second)) >> 16)
+}
+
+type NTPExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewNTPExecutor(logger *zap.SugaredLogger) *NTPExecutor {
+	return &NTPExecutor{
+		logger: logger,
+	}
+}
+
+func (s *NTPExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[NTPConfig](configJSON)
+}
+
+func (s *NTPExecutor) Validate(configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	return GenericValidator(cfg.(*NTPConfig))
+}
+
+func (n *NTPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := n.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*NTPConfig)
+
+	if cfg.Port == 0 {
+		cfg.Port = 123
+	}
+
+	n.logger.Debugf("execute ntp cfg: %+v", cfg)
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	startTime := time.Now().UTC()
+	packet, offset, delay, err := queryNTP(ctx, net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)))
+	endTime := time.Now().UTC()
+
+	if err != nil {
+		n.logger.Infof("NTP query failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("NTP query failed: %w", err), startTime, endTime)
+	}
+
+	metadata := Metadata{
+		"stratum":            int(packet.Stratum),
+		"leap":               int(packet.Leap),
+		"reference_id":       packet.referenceID(),
+		"offset_ms":          durationMs(offset),
+		"delay_ms":           durationMs(delay),
+		"root_delay_ms":      durationMs(packet.RootDelay),
+		"root_dispersion_ms": durationMs(packet.RootDispersion),
+	}
+
+	down := func(message string) *Result {
+		n.logger.Infof("NTP check failed: %s, %s", m.Name, message)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   message,
+			StartTime: startTime,
+			EndTime:   endTime,
+			Metadata:  metadata,
+		}
+	}
+
+	if err := packet.syncError(); err != nil {
+		return down(err.Error())
+	}
+	if cfg.MaxStratum > 0 && int(packet.Stratum) > cfg.MaxStratum {
+		return down(fmt.Sprintf("NTP server stratum %d exceeds %d", packet.Stratum, cfg.MaxStratum))
+	}
+
+	summary := fmt.Sprintf("stratum %d, offset %v, delay %v", packet.Stratum, offset.Round(time.Millisecond), delay.Round(time.Microsecond))
+	if cfg.MaxOffset != nil && durationMs(offset.Abs()) > *cfg.MaxOffset {
+		return down(fmt.Sprintf("Clock offset %v exceeds %vms: %s", offset.Round(time.Millisecond), *cfg.MaxOffset, summary))
+	}
+
+	n.logger.Infof("NTP check successful: %s, %s", m.Name, summary)
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("NTP server synchronized, %s", summary),
+		StartTime: startTime,
+		EndTime:   endTime,
+		Metadata:  metadata,
+	}
+}
+
+// queryNTP sends a client request and returns the answer together with the offset of the
+// server clock to the local clock and the round trip delay
+func queryNTP(ctx context.Context, address string) (*ntpPacket, time.Duration, time.Duration, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	request := make([]byte, ntpPacketSize)
+	request[0] = 4<<3 | ntpModeClient
+
+	// The server copies the transmit timestamp into the originate timestamp of its answer
+	sentAt := time.Now()
+	transmit := toNTPTime(sentAt)
+	binary.BigEndian.PutUint64(request[40:], transmit)
+
+	if _, err := conn.Write(request); err != nil {
+		return nil, 0, 0, err
+	}
+
+	response := make([]byte, 512)
+	n, err := conn.Read(response)
+	receivedAt := time.Now()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	packet, err := parseNTPPacket(response[:n])
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if binary.BigEndian.Uint64(response[24:32]) != transmit {
+		return nil, 0, 0, errors.New("NTP response does not answer the request")
+	}
+
+	// RFC 5905: offset = ((T2 - T1) + (T3 - T4)) / 2, delay = (T4 - T1) - (T3 - T2)
+	offset := (packet.Receive.Sub(sentAt) + packet.Transmit.Sub(receivedAt)) / 2
+	delay := receivedAt.Sub(sentAt) - packet.Transmit.Sub(packet.Receive)
+
+	return packet, offset, max(delay, 0), nil
+}
@@ -0,0 +1,165 @@
+package executor
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// ntpServer describes the answers of a fake NTP server
+type ntpServer struct {
+	skew      time.Duration
+	stratum   byte
+	leap      byte
+	originate func(request []byte) uint64
+}
+
+func (s ntpServer) reply(request []byte) []byte {
+	if len(request) < ntpPacketSize {
+		return nil
+	}
+	response := make([]byte, ntpPacketSize)
+	response[0] = s.leap<<6 | 4<<3 | ntpModeServer
+	response[1] = s.stratum
+	binary.BigEndian.PutUint32(response[4:], 0x00000800) // root delay 31.25ms
+	copy(response[12:], []byte{192, 0, 2, 1})
+
+	originate := binary.BigEndian.Uint64(request[40:48])
+	if s.originate != nil {
+		originate = s.originate(request)
+	}
+	binary.BigEndian.PutUint64(response[24:], originate)
+	now := time.Now().Add(s.skew)
+	binary.BigEndian.PutUint64(response[32:], toNTPTime(now))
+	binary.BigEndian.PutUint64(response[40:], toNTPTime(now))
+	return response
+}
+
+func TestNTPExecutor_Validate(t *testing.T) {
+	executor := NewNTPExecutor(zap.NewNop().Sugar())
+
+	assert.NoError(t, executor.Validate(`{"host":"pool.ntp.org"}`))
+	assert.NoError(t, executor.Validate(`{"host":"pool.ntp.org","port":123,"max_offset_ms":250,"max_stratum":3}`))
+	assert.Error(t, executor.Validate(`{"port":123}`))
+	assert.Error(t, executor.Validate(`{"host":"pool.ntp.org","max_offset_ms":-1}`))
+	assert.Error(t, executor.Validate(`{"host":"pool.ntp.org","max_stratum":16}`))
+	assert.Error(t, executor.Validate(`{"host":"pool.ntp.org","port":70000}`))
+}
+
+func TestNTPExecutor_Execute(t *testing.T) {
+	executor := NewNTPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		server      ntpServer
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "synchronized",
+			server:      ntpServer{stratum: 2},
+			config:      `{"host":"127.0.0.1","port":%d,"max_offset_ms":500}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "NTP server synchronized, stratum 2",
+		},
+		{
+			name:        "offset exceeded",
+			server:      ntpServer{stratum: 2, skew: 3 * time.Second},
+			config:      `{"host":"127.0.0.1","port":%d,"max_offset_ms":500}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "Clock offset 3",
+		},
+		{
+			name:        "negative offset exceeded",
+			server:      ntpServer{stratum: 2, skew: -2 * time.Second},
+			config:      `{"host":"127.0.0.1","port":%d,"max_offset_ms":500}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "Clock offset -2",
+		},
+		{
+			name:        "offset without threshold",
+			server:      ntpServer{stratum: 2, skew: 3 * time.Second},
+			config:      `{"host":"127.0.0.1","port":%d}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "NTP server synchronized",
+		},
+		{
+			name:        "stratum 16",
+			server:      ntpServer{stratum: 16},
+			config:      `{"host":"127.0.0.1","port":%d}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "NTP server is unsynchronized (stratum 16)",
+		},
+		{
+			name:        "leap indicator alarm",
+			server:      ntpServer{stratum: 3, leap: ntpLeapAlarm},
+			config:      `{"host":"127.0.0.1","port":%d}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "leap indicator alarm",
+		},
+		{
+			name:        "stratum above maximum",
+			server:      ntpServer{stratum: 5},
+			config:      `{"host":"127.0.0.1","port":%d,"max_stratum":3}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "NTP server stratum 5 exceeds 3",
+		},
+		{
+			name:        "answer to another request",
+			server:      ntpServer{stratum: 2, originate: func([]byte) uint64 { return 42 }},
+			config:      `{"host":"127.0.0.1","port":%d}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "NTP response does not answer the request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := serveUDP(t, tt.server.reply)
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "ntp",
+				Name:    "NTP Monitor",
+				Timeout: 1,
+				Config:  fmt.Sprintf(tt.config, port),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+		})
+	}
+}
+
+func TestNTPExecutor_ExecuteMetadata(t *testing.T) {
+	port := serveUDP(t, ntpServer{stratum: 2, skew: time.Second}.reply)
+
+	executor := NewNTPExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "ntp",
+		Name:    "NTP Monitor",
+		Timeout: 1,
+		Config:  fmt.Sprintf(`{"host":"127.0.0.1","port":%d}`, port),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, 2, result.Metadata["stratum"])
+	assert.Equal(t, "192.0.2.1", result.Metadata["reference_id"])
+	assert.Equal(t, 31.25, result.Metadata["root_delay_ms"])
+	assert.InDelta(t, 1000, result.Metadata["offset_ms"], 50)
+	assert.GreaterOrEqual(t, result.Metadata["delay_ms"], 0.0)
+}
+
+func TestNTPTime(t *testing.T) {
+	now := time.Date(2025, 8, 10, 12, 30, 45, 500_000_000, time.UTC)
+	assert.WithinDuration(t, now, ntpTime(toNTPTime(now)), time.Microsecond)
+	assert.Equal(t, time.Unix(0, 0), ntpTime(uint64(ntpEpochOffset)<<32))
+}
@@ -2,23 +2,83 @@ package executor
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"net"
-	"os/exec"
 	"peekaping/src/modules/shared"
-	"runtime"
-	"strconv"
-	"strings"
 	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/net/icmp"
 	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+// pingProbeInterval is the pause between two probes of the same check
+const pingProbeInterval = 200 * time.Millisecond
+
+const (
+	protocolICMP   = 1
+	protocolICMPv6 = 58
 )
 
 type PingConfig struct {
 	Host       string `json:"host" validate:"required" example:"example.com"`
 	PacketSize int    `json:"packet_size" validate:"min=0,max=65507" example:"32"`
+	// Count is the number of probes sent per check, defaults to 1
+	Count     int    `json:"count" validate:"min=0,max=100" example:"5"`
+	IPVersion string `json:"ip_version" validate:"omitempty,oneof=4 6" example:"4"`
+	// MaxPacketLoss is the highest loss in percent still considered UP, by default the monitor
+	// is only DOWN when no probe is answered
+	MaxPacketLoss *float64 `json:"max_packet_loss" validate:"omitempty,min=0,max=100" example:"20"`
+	// MaxJitter is the highest jitter in milliseconds still considered UP
+	MaxJitter *float64 `json:"max_jitter_ms" validate:"omitempty,min=0" example:"30"`
+}
+
+// pingStats summarizes the probes of a check
+type pingStats struct {
+	Sent     int
+	Received int
+	Min      time.Duration
+	Avg      time.Duration
+	Max      time.Duration
+	Jitter   time.Duration
+}
+
+// Loss returns the share of unanswered probes in percent
+func (s pingStats) Loss() float64 {
+	if s.Sent == 0 {
+		return 0
+	}
+	return float64(s.Sent-s.Received) * 100 / float64(s.Sent)
+}
+
+// newPingStats computes the statistics of the answered probes in the order they were sent.
+// Jitter is the mean difference between consecutive round trip times.
+func newPingStats(sent int, rtts []time.Duration) pingStats {
+	stats := pingStats{Sent: sent, Received: len(rtts)}
+	if len(rtts) == 0 {
+		return stats
+	}
+
+	var total, variation time.Duration
+	stats.Min, stats.Max = rtts[0], rtts[0]
+	for i, rtt := range rtts {
+		total += rtt
+		stats.Min = min(stats.Min, rtt)
+		stats.Max = max(stats.Max, rtt)
+		if i > 0 {
+			variation += (rtt - rtts[i-1]).Abs()
+		}
+	}
+	stats.Avg = total / time.Duration(len(rtts))
+	if len(rtts) > 1 {
+		stats.Jitter = variation / time.Duration(len(rtts)-1)
+	}
+
+	return stats
 }
 
 type PingExecutor struct {
@@ -54,20 +114,18 @@ func (p *PingExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 	if cfg.PacketSize == 0 {
 		cfg.PacketSize = 32
 	}
+	if cfg.Count == 0 {
+		cfg.Count = 1
+	}
 
 	p.logger.Debugf("execute ping cfg: %+v", cfg)
 
-	startTime := time.Now().UTC()
-
-	// Try native ICMP first, fallback to system ping command
-	success, rtt, err := p.tryNativePing(ctx, cfg.Host, cfg.PacketSize, time.Duration(m.Timeout)*time.Second)
-	if err != nil {
-		// Fallback to system ping command
-		p.logger.Debugf("Ping failed: %s, %s, %s", m.Name, err.Error(), "trying system ping")
-		startTime = time.Now().UTC() // reset start time
-		success, rtt, err = p.trySystemPing(ctx, cfg.Host, cfg.PacketSize, time.Duration(m.Timeout)*time.Second)
-	}
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
+	startTime := time.Now().UTC()
+	stats, err := p.ping(ctx, cfg, timeout)
 	endTime := time.Now().UTC()
 
 	if err != nil {
@@ -80,138 +138,224 @@ func (p *PingExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 		}
 	}
 
-	if !success {
+	metadata := Metadata{
+		"packets_sent":     stats.Sent,
+		"packets_received": stats.Received,
+		"packet_loss":      stats.Loss(),
+	}
+
+	if stats.Received == 0 {
+		p.logger.Infof("Ping failed: %s, no response received", m.Name)
 		return &Result{
 			Status:    shared.MonitorStatusDown,
-			Message:   "Ping failed: no response received",
+			Message:   fmt.Sprintf("Ping failed: no response received, %d packets sent", stats.Sent),
 			StartTime: startTime,
 			EndTime:   endTime,
+			Metadata:  metadata,
 		}
 	}
 
-	p.logger.Infof("Ping successful: %s, RTT: %v", m.Name, rtt)
+	metadata["rtt_ms"] = durationMs(stats.Avg)
+	metadata["rtt_min_ms"] = durationMs(stats.Min)
+	metadata["rtt_max_ms"] = durationMs(stats.Max)
+	metadata["jitter_ms"] = durationMs(stats.Jitter)
+
+	// The heartbeat ping is the average round trip time rather than the duration of all probes
+	endTime = startTime.Add(stats.Avg)
+
+	summary := fmt.Sprintf("%d/%d received, %.0f%% loss, rtt min/avg/max %v/%v/%v, jitter %v",
+		stats.Received, stats.Sent, stats.Loss(), stats.Min, stats.Avg, stats.Max, stats.Jitter)
+
+	if cfg.MaxPacketLoss != nil && stats.Loss() > *cfg.MaxPacketLoss {
+		p.logger.Infof("Ping failed: %s, packet loss %.0f%%", m.Name, stats.Loss())
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("Packet loss %.0f%% exceeds %.0f%%: %s", stats.Loss(), *cfg.MaxPacketLoss, summary),
+			StartTime: startTime,
+			EndTime:   endTime,
+			Metadata:  metadata,
+		}
+	}
+
+	if cfg.MaxJitter != nil && durationMs(stats.Jitter) > *cfg.MaxJitter {
+		p.logger.Infof("Ping failed: %s, jitter %v", m.Name, stats.Jitter)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("Jitter %v exceeds %vms: %s", stats.Jitter, *cfg.MaxJitter, summary),
+			StartTime: startTime,
+			EndTime:   endTime,
+			Metadata:  metadata,
+		}
+	}
+
+	p.logger.Infof("Ping successful: %s, %s", m.Name, summary)
 
 	return &Result{
 		Status:    shared.MonitorStatusUp,
-		Message:   fmt.Sprintf("Ping successful, RTT: %v", rtt),
+		Message:   fmt.Sprintf("Ping successful, %s", summary),
 		StartTime: startTime,
 		EndTime:   endTime,
+		Metadata:  metadata,
 	}
 }
 
-// tryNativePing attempts to use native ICMP implementation
-func (p *PingExecutor) tryNativePing(ctx context.Context, host string, packetSize int, timeout time.Duration) (bool, time.Duration, error) {
-	// Resolve the host
-	dst, err := net.ResolveIPAddr("ip4", host)
+// ping sends the configured number of echo requests one after another and collects the replies
+func (p *PingExecutor) ping(ctx context.Context, cfg *PingConfig, timeout time.Duration) (pingStats, error) {
+	dst, err := resolvePingTarget(cfg.Host, cfg.IPVersion)
 	if err != nil {
-		return false, 0, fmt.Errorf("failed to resolve host: %v", err)
+		return pingStats{}, err
 	}
 
-	// Try to open raw socket for ICMP
-	conn, err := icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+	pc, err := listenICMP(dst.IP.To4() == nil)
 	if err != nil {
-		return false, 0, fmt.Errorf("failed to create ICMP socket (try running as root): %v", err)
+		return pingStats{}, err
 	}
-	defer conn.Close()
-
-	// Set timeout
-	deadline := time.Now().Add(timeout)
-	conn.SetDeadline(deadline)
+	defer pc.conn.Close()
 
-	// Create ICMP message with custom data size
-	// packetSize represents the data payload size (like ping -s flag)
-	dataSize := packetSize
-	if dataSize < 0 {
-		dataSize = 0
-	}
-	data := make([]byte, dataSize)
+	data := make([]byte, cfg.PacketSize)
 	copy(data, []byte("Peekaping"))
 
-	p.logger.Debugf("Native ping: host=%s, dataSize=%d, totalPacketSize=%d", host, dataSize, dataSize+8)
+	p.logger.Debugf("Native ping: host=%s, ip=%s, privileged=%t, count=%d, dataSize=%d", cfg.Host, dst.IP, pc.privileged, cfg.Count, len(data))
+
+	// Every probe gets an equal share of the timeout, a late reply counts as lost
+	probeTimeout := timeout / time.Duration(cfg.Count)
+	id := rand.Intn(math.MaxUint16)
+
+	var rtts []time.Duration
+	sent := 0
+	for seq := 1; seq <= cfg.Count; seq++ {
+		if seq > 1 {
+			select {
+			case <-ctx.Done():
+				return newPingStats(sent, rtts), nil
+			case <-time.After(pingProbeInterval):
+			}
+		}
 
-	msg := &icmp.Message{
-		Type: ipv4.ICMPTypeEcho,
-		Code: 0,
-		Body: &icmp.Echo{
-			ID:   1,
-			Seq:  1,
-			Data: data,
-		},
-	}
+		deadline := time.Now().Add(probeTimeout)
+		if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+			deadline = ctxDeadline
+		}
 
-	msgBytes, err := msg.Marshal(nil)
-	if err != nil {
-		return false, 0, fmt.Errorf("failed to marshal ICMP message: %v", err)
+		sent++
+		rtt, err := pc.probe(dst, id, seq, data, deadline)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return pingStats{}, err
+		}
+		rtts = append(rtts, rtt)
 	}
 
-	start := time.Now()
-	_, err = conn.WriteTo(msgBytes, dst)
-	if err != nil {
-		return false, 0, fmt.Errorf("failed to send ICMP packet: %v", err)
-	}
+	return newPingStats(sent, rtts), nil
+}
 
-	// Read response
-	reply := make([]byte, 1500)
-	n, peer, err := conn.ReadFrom(reply)
-	if err != nil {
-		return false, 0, fmt.Errorf("failed to read ICMP reply: %v", err)
+func resolvePingTarget(host, ipVersion string) (*net.IPAddr, error) {
+	network := "ip"
+	switch ipVersion {
+	case "4":
+		network = "ip4"
+	case "6":
+		network = "ip6"
 	}
-	rtt := time.Since(start)
 
-	// Parse the reply - protocol 1 for IPv4 ICMP
-	replyMsg, err := icmp.ParseMessage(1, reply[:n])
+	dst, err := net.ResolveIPAddr(network, host)
 	if err != nil {
-		return false, 0, fmt.Errorf("failed to parse ICMP reply: %v", err)
-	}
-
-	if replyMsg.Type == ipv4.ICMPTypeEchoReply {
-		p.logger.Debugf("Received ICMP reply from %v", peer)
-		return true, rtt, nil
+		return nil, fmt.Errorf("failed to resolve host: %v", err)
 	}
+	return dst, nil
+}
 
-	return false, 0, fmt.Errorf("unexpected ICMP message type: %v", replyMsg.Type)
+// icmpConn is an ICMP socket, either an unprivileged datagram socket or a raw socket
+type icmpConn struct {
+	conn       *icmp.PacketConn
+	ipv6       bool
+	privileged bool
 }
 
-// trySystemPing falls back to using the system ping command
-func (p *PingExecutor) trySystemPing(ctx context.Context, host string, packetSize int, timeout time.Duration) (bool, time.Duration, error) {
-	var cmd *exec.Cmd
+// listenICMP opens an unprivileged datagram socket and falls back to a raw socket when the
+// system does not allow them (net.ipv4.ping_group_range on Linux)
+func listenICMP(ipv6 bool) (*icmpConn, error) {
+	udpNetwork, rawNetwork, address := "udp4", "ip4:icmp", "0.0.0.0"
+	if ipv6 {
+		udpNetwork, rawNetwork, address = "udp6", "ip6:ipv6-icmp", "::"
+	}
 
-	p.logger.Debugf("System ping: host=%s, dataSize=%d, totalPacketSize=%d", host, packetSize, packetSize+8)
+	conn, err := icmp.ListenPacket(udpNetwork, address)
+	if err == nil {
+		return &icmpConn{conn: conn, ipv6: ipv6}, nil
+	}
 
-	switch runtime.GOOS {
-	case "windows":
-		cmd = exec.CommandContext(ctx, "ping", "-n", "1", "-l", strconv.Itoa(packetSize), "-w", strconv.Itoa(int(timeout.Milliseconds())), host)
-	case "darwin":
-		cmd = exec.CommandContext(ctx, "ping", "-c", "1", "-s", strconv.Itoa(packetSize), "-W", strconv.Itoa(int(timeout.Milliseconds())), host)
-	default: // linux and others
-		cmd = exec.CommandContext(ctx, "ping", "-c", "1", "-s", strconv.Itoa(packetSize), "-W", strconv.Itoa(int(timeout.Seconds())), host)
+	conn, rawErr := icmp.ListenPacket(rawNetwork, address)
+	if rawErr != nil {
+		return nil, fmt.Errorf("failed to create ICMP socket, allow unprivileged ping or grant CAP_NET_RAW: %v", rawErr)
 	}
+	return &icmpConn{conn: conn, ipv6: ipv6, privileged: true}, nil
+}
 
-	start := time.Now()
-	output, err := cmd.Output()
-	rtt := time.Since(start)
+// probe sends one echo request and waits until deadline for the matching reply
+func (c *icmpConn) probe(dst *net.IPAddr, id, seq int, data []byte, deadline time.Time) (time.Duration, error) {
+	var requestType, replyType icmp.Type = ipv4.ICMPTypeEcho, ipv4.ICMPTypeEchoReply
+	protocol := protocolICMP
+	if c.ipv6 {
+		requestType, replyType = ipv6.ICMPTypeEchoRequest, ipv6.ICMPTypeEchoReply
+		protocol = protocolICMPv6
+	}
 
+	msg := &icmp.Message{
+		Type: requestType,
+		Code: 0,
+		Body: &icmp.Echo{
+			ID:   id,
+			Seq:  seq,
+			Data: data,
+		},
+	}
+	msgBytes, err := msg.Marshal(nil)
 	if err != nil {
-		return false, 0, fmt.Errorf("ping command failed: %v", err)
+		return 0, fmt.Errorf("failed to marshal ICMP message: %v", err)
 	}
 
-	outputStr := string(output)
+	var addr net.Addr = dst
+	if !c.privileged {
+		addr = &net.UDPAddr{IP: dst.IP, Zone: dst.Zone}
+	}
 
-	// Check if ping was successful based on output
-	if strings.Contains(outputStr, "100% packet loss") ||
-		strings.Contains(outputStr, "100% loss") ||
-		strings.Contains(outputStr, "Request timed out") ||
-		strings.Contains(outputStr, "Destination host unreachable") {
-		return false, rtt, nil
+	if err := c.conn.SetReadDeadline(deadline); err != nil {
+		return 0, err
 	}
 
-	// Look for success indicators
-	if strings.Contains(outputStr, "bytes from") ||
-		strings.Contains(outputStr, "Reply from") ||
-		(strings.Contains(outputStr, "packets transmitted") && !strings.Contains(outputStr, "100% packet loss")) {
-		return true, rtt, nil
+	start := time.Now()
+	if _, err := c.conn.WriteTo(msgBytes, addr); err != nil {
+		return 0, fmt.Errorf("failed to send ICMP packet: %v", err)
+	}
+
+	reply := make([]byte, 1500+len(data))
+	for {
+		n, _, err := c.conn.ReadFrom(reply)
+		if err != nil {
+			return 0, err
+		}
+		rtt := time.Since(start)
+
+		replyMsg, err := icmp.ParseMessage(protocol, reply[:n])
+		if err != nil || replyMsg.Type != replyType {
+			continue
+		}
+		echo, ok := replyMsg.Body.(*icmp.Echo)
+		if !ok || echo.Seq != seq {
+			continue
+		}
+		// Datagram sockets get their ID assigned by the kernel, raw sockets see every reply
+		if c.privileged && echo.ID != id {
+			continue
+		}
+		return rtt, nil
 	}
+}
 
-	// If we can't determine from output, assume failure
-	return false, rtt, fmt.Errorf("unable to determine ping result from output: %s", outputStr)
+func durationMs(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
 }
@@ -0,0 +1,115 @@
+package executor
+
+import (
+	"context"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPingExecutor_Validate(t *testing.T) {
+	executor := NewPingExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "host only",
+			config:    `{"host":"example.com"}`,
+			wantError: false,
+		},
+		{
+			name:      "probes and thresholds",
+			config:    `{"host":"example.com","count":5,"ip_version":"6","max_packet_loss":20,"max_jitter_ms":30}`,
+			wantError: false,
+		},
+		{
+			name:      "zero loss tolerated",
+			config:    `{"host":"example.com","count":5,"max_packet_loss":0}`,
+			wantError: false,
+		},
+		{
+			name:      "invalid ip version",
+			config:    `{"host":"example.com","ip_version":"5"}`,
+			wantError: true,
+		},
+		{
+			name:      "loss above 100",
+			config:    `{"host":"example.com","max_packet_loss":101}`,
+			wantError: true,
+		},
+		{
+			name:      "too many probes",
+			config:    `{"host":"example.com","count":101}`,
+			wantError: true,
+		},
+		{
+			name:      "missing host",
+			config:    `{"count":3}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewPingStats(t *testing.T) {
+	stats := newPingStats(5, []time.Duration{
+		10 * time.Millisecond,
+		14 * time.Millisecond,
+		12 * time.Millisecond,
+		20 * time.Millisecond,
+	})
+
+	assert.Equal(t, 5, stats.Sent)
+	assert.Equal(t, 4, stats.Received)
+	assert.Equal(t, 20.0, stats.Loss())
+	assert.Equal(t, 10*time.Millisecond, stats.Min)
+	assert.Equal(t, 14*time.Millisecond, stats.Avg)
+	assert.Equal(t, 20*time.Millisecond, stats.Max)
+	// |14-10| + |12-14| + |20-12| = 14ms over 3 differences
+	assert.Equal(t, 14*time.Millisecond/3, stats.Jitter)
+
+	single := newPingStats(1, []time.Duration{5 * time.Millisecond})
+	assert.Equal(t, time.Duration(0), single.Jitter)
+	assert.Equal(t, 0.0, single.Loss())
+
+	none := newPingStats(3, nil)
+	assert.Equal(t, 100.0, none.Loss())
+}
+
+func TestPingExecutor_ExecuteLoopback(t *testing.T) {
+	if _, err := listenICMP(false); err != nil {
+		t.Skipf("ICMP sockets not available: %v", err)
+	}
+
+	executor := NewPingExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "ping",
+		Name:    "Ping Monitor",
+		Timeout: 5,
+		Config:  `{"host":"127.0.0.1","count":3,"ip_version":"4","max_packet_loss":0}`,
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, 3, result.Metadata["packets_sent"])
+	assert.Equal(t, 3, result.Metadata["packets_received"])
+	assert.Equal(t, 0.0, result.Metadata["packet_loss"])
+	assert.Contains(t, result.Metadata, "jitter_ms")
+}
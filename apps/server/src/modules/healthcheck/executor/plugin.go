@@ -0,0 +1,326 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"peekaping/src/modules/shared"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Plugins are executables in the plugins directory that add monitor types. Each is invoked
+// with a single argument naming the operation:
+//
+//	describe  prints a PluginDescriptor as JSON on stdout
+//	validate  reads the monitor config on stdin and exits non-zero with the reason on stderr
+//	          when the config is invalid
+//	check     reads the monitor config on stdin and prints a pluginResult as JSON on stdout
+//
+// The check runs with PEEKAPING_MONITOR_ID, PEEKAPING_MONITOR_NAME, PEEKAPING_MONITOR_TIMEOUT
+// and, when the monitor uses a proxy, PEEKAPING_PROXY_URL in its environment. Besides PATH,
+// LANG and HOME, the plugin directory, nothing of the server environment is passed on.
+const (
+	// pluginCallTimeout bounds describe and validate, checks use the monitor timeout
+	pluginCallTimeout = 10 * time.Second
+	// pluginMaxOutput bounds what a plugin may print on stdout, it is killed beyond that
+	pluginMaxOutput = 1 << 20
+	// pluginMaxStderr is the part of stderr kept to explain a failure
+	pluginMaxStderr = 4096
+)
+
+var pluginTypeName = regexp.MustCompile(`^[a-z][a-z0-9-]{0,63}$`)
+
+// PluginDescriptor is what a plugin declares about the monitor type it provides
+type PluginDescriptor struct {
+	Type        string `json:"type" example:"license-server"`
+	Description string `json:"description,omitempty" example:"FlexLM license server availability"`
+	// ConfigSchema is the JSON schema of the monitor config, used by the UI to render the form
+	ConfigSchema json.RawMessage `json:"config_schema,omitempty" swaggertype:"object"`
+	Path         string          `json:"-"`
+}
+
+// pluginResult is what a plugin prints after a check
+type pluginResult struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// DurationMs replaces the time the process ran as response time, which includes its start
+	DurationMs *float64 `json:"duration_ms"`
+	Metadata   Metadata `json:"metadata"`
+}
+
+type PluginExecutor struct {
+	logger     *zap.SugaredLogger
+	descriptor *PluginDescriptor
+}
+
+// NewPluginExecutor asks the executable at path to describe itself
+func NewPluginExecutor(logger *zap.SugaredLogger, path string) (*PluginExecutor, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), pluginCallTimeout)
+	defer cancel()
+
+	stdout, err := runPlugin(ctx, path, "describe", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var descriptor PluginDescriptor
+	if err := json.Unmarshal(stdout, &descriptor); err != nil {
+		return nil, fmt.Errorf("invalid describe output: %w", err)
+	}
+	if !pluginTypeName.MatchString(descriptor.Type) {
+		return nil, fmt.Errorf("invalid monitor type %q, lower case letters, digits and dashes expected", descriptor.Type)
+	}
+	if len(descriptor.ConfigSchema) > 0 && !json.Valid(descriptor.ConfigSchema) {
+		return nil, errors.New("config_schema is not valid JSON")
+	}
+	descriptor.Path = path
+
+	return &PluginExecutor{
+		logger:     logger,
+		descriptor: &descriptor,
+	}, nil
+}
+
+func (s *PluginExecutor) Descriptor() *PluginDescriptor {
+	return s.descriptor
+}
+
+func (s *PluginExecutor) Unmarshal(configJSON string) (any, error) {
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(configJSON), &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+	if cfg == nil {
+		return nil, errors.New("failed to parse config: a JSON object is required")
+	}
+	return cfg, nil
+}
+
+func (s *PluginExecutor) Validate(configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(map[string]any)
+
+	// The plugin validates the details, the required fields of the schema are checked here so
+	// that plugins without validation still reject incomplete configs
+	var schema struct {
+		Required []string `json:"required"`
+	}
+	if len(s.descriptor.ConfigSchema) > 0 && json.Unmarshal(s.descriptor.ConfigSchema, &schema) == nil {
+		for _, field := range schema.Required {
+			if _, ok := cfg[field]; !ok {
+				return fmt.Errorf("%s is required", field)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), pluginCallTimeout)
+	defer cancel()
+
+	if _, err := runPlugin(ctx, s.descriptor.Path, "validate", []byte(configJSON), nil); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PluginExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	env := []string{
+		"PEEKAPING_MONITOR_ID=" + m.ID,
+		"PEEKAPING_MONITOR_NAME=" + m.Name,
+		"PEEKAPING_MONITOR_TIMEOUT=" + strconv.Itoa(m.Timeout),
+	}
+	if proxyModel != nil {
+		env = append(env, "PEEKAPING_PROXY_URL="+pluginProxyURL(proxyModel))
+	}
+
+	s.logger.Debugf("execute plugin %s: %s", s.descriptor.Type, m.Name)
+
+	startTime := time.Now().UTC()
+	stdout, err := runPlugin(ctx, s.descriptor.Path, "check", []byte(m.Config), env)
+	endTime := time.Now().UTC()
+
+	if err != nil && len(stdout) == 0 {
+		s.logger.Infof("Plugin check failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("%s plugin failed: %w", s.descriptor.Type, err), startTime, endTime)
+	}
+
+	// A plugin may report its result and signal it with a non-zero exit code as well
+	var output pluginResult
+	if jsonErr := json.Unmarshal(stdout, &output); jsonErr != nil {
+		if err == nil {
+			err = fmt.Errorf("invalid output: %w", jsonErr)
+		}
+		s.logger.Infof("Plugin check failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("%s plugin failed: %w", s.descriptor.Type, err), startTime, endTime)
+	}
+
+	if output.DurationMs != nil && *output.DurationMs >= 0 {
+		endTime = startTime.Add(time.Duration(*output.DurationMs * float64(time.Millisecond)))
+	}
+
+	result := &Result{
+		Message:   output.Message,
+		StartTime: startTime,
+		EndTime:   endTime,
+		Metadata:  output.Metadata,
+	}
+
+	switch strings.ToLower(output.Status) {
+	case "up":
+		result.Status = shared.MonitorStatusUp
+	case "down":
+		result.Status = shared.MonitorStatusDown
+	default:
+		result.Status = shared.MonitorStatusDown
+		result.Message = fmt.Sprintf("%s plugin reported unknown status %q: %s", s.descriptor.Type, output.Status, output.Message)
+	}
+
+	if result.Message == "" {
+		result.Message = fmt.Sprintf("%s plugin reported %s", s.descriptor.Type, strings.ToLower(output.Status))
+	}
+
+	s.logger.Infof("Plugin check finished: %s, %s", m.Name, result.Message)
+
+	return result
+}
+
+// limitedOutput keeps up to limit bytes and reports when a write goes beyond. The buffer is
+// not embedded, io.Copy would otherwise bypass the limit through bytes.Buffer.ReadFrom
+type limitedOutput struct {
+	buf      bytes.Buffer
+	limit    int
+	exceeded func()
+}
+
+func (o *limitedOutput) Write(p []byte) (int, error) {
+	if room := o.limit - o.buf.Len(); len(p) > room {
+		o.buf.Write(p[:max(room, 0)])
+		if o.exceeded != nil {
+			o.exceeded()
+		}
+		return len(p), nil
+	}
+	return o.buf.Write(p)
+}
+
+// runPlugin invokes the plugin for an operation and returns its stdout, the error of a
+// non-zero exit carries what the plugin printed on stderr
+func runPlugin(ctx context.Context, path, operation string, stdin []byte, env []string) ([]byte, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var overflow bool
+	stdout := &limitedOutput{limit: pluginMaxOutput, exceeded: func() {
+		overflow = true
+		cancel()
+	}}
+	stderr := &limitedOutput{limit: pluginMaxStderr}
+
+	cmd := exec.CommandContext(ctx, path, operation)
+	cmd.Stdin = bytes.NewReader(stdin)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Dir = filepath.Dir(path)
+	// The environment of the server, database credentials included, is not passed on
+	cmd.Env = append([]string{"PATH=" + os.Getenv("PATH"), "HOME=" + cmd.Dir, "LANG=C"}, env...)
+	// Children that inherited the pipes must not keep the check running after the plugin exits
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	switch {
+	case overflow:
+		return nil, fmt.Errorf("output exceeds %d bytes", pluginMaxOutput)
+	case ctx.Err() != nil && errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("%s timed out", operation)
+	case err != nil:
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if reason := strings.TrimSpace(stderr.buf.String()); reason != "" {
+				return stdout.buf.Bytes(), errors.New(reason)
+			}
+			return stdout.buf.Bytes(), fmt.Errorf("%s exited with code %d", operation, exitErr.ExitCode())
+		}
+		return nil, err
+	}
+	return stdout.buf.Bytes(), nil
+}
+
+func pluginProxyURL(proxyModel *Proxy) string {
+	protocol := proxyModel.Protocol
+	if protocol == "" {
+		protocol = "http"
+	}
+	proxyURL := &url.URL{
+		Scheme: protocol,
+		Host:   fmt.Sprintf("%s:%d", proxyModel.Host, proxyModel.Port),
+	}
+	if proxyModel.Auth && proxyModel.Username != "" {
+		proxyURL.User = url.UserPassword(proxyModel.Username, proxyModel.Password)
+	}
+	return proxyURL.String()
+}
+
+// LoadPlugins registers the monitor types of the executables in dir, plugins that fail to
+// describe themselves or clash with a built-in type are skipped
+func (f *ExecutorRegistry) LoadPlugins(dir string) error {
+	if dir == "" {
+		return nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read plugins directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || info.Mode().Perm()&0o111 == 0 {
+			continue
+		}
+
+		path, err := filepath.Abs(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		plugin, err := NewPluginExecutor(f.logger, path)
+		if err != nil {
+			f.logger.Warnf("Skipping plugin %s: %v", entry.Name(), err)
+			continue
+		}
+
+		name := plugin.Descriptor().Type
+		if _, exists := f.registry[name]; exists {
+			f.logger.Warnf("Skipping plugin %s: monitor type %s is already registered", entry.Name(), name)
+			continue
+		}
+
+		f.RegisterExecutor(name, plugin)
+		f.plugins = append(f.plugins, plugin.Descriptor())
+		f.logger.Infof("Registered plugin monitor type %s from %s", name, entry.Name())
+	}
+
+	return nil
+}
+
+// Plugins lists the monitor types provided by plugins
+func (f *ExecutorRegistry) Plugins() []*PluginDescriptor {
+	return f.plugins
+}
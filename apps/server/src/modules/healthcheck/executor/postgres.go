@@ -157,15 +157,19 @@ func (p *PostgresExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *
 	}
 	defer rows.Close()
 
-	if !rows.Next() {
-		if err := rows.Err(); err != nil {
-			endTime := time.Now().UTC()
-			return &Result{
-				Status:    shared.MonitorStatusDown,
-				Message:   fmt.Sprintf("Query error: %v", err),
-				StartTime: startTime,
-				EndTime:   endTime,
-			}
+	// Count rows
+	rowCount := 0
+	for rows.Next() {
+		rowCount++
+	}
+
+	if err := rows.Err(); err != nil {
+		endTime := time.Now().UTC()
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("Query error: %v", err),
+			StartTime: startTime,
+			EndTime:   endTime,
 		}
 	}
 
@@ -179,6 +183,7 @@ func (p *PostgresExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *
 		Message:   fmt.Sprintf("Query successful, ping: %dms", ping),
 		StartTime: startTime,
 		EndTime:   endTime,
+		Metadata:  Metadata{MetadataRows: rowCount},
 	}
 }
 
@@ -136,6 +136,7 @@ func (s *SnmpExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 
 	// Convert the value to string for comparison
 	valueStr := s.convertSnmpValueToString(variable.Value, variable.Type)
+	metadata := Metadata{"oid": cfg.Oid, "value": valueStr, "value_type": variable.Type.String()}
 
 	// If no json path or expected value is provided, consider it successful
 	if cfg.JsonPath == "" || cfg.ExpectedValue == "" {
@@ -145,6 +146,7 @@ func (s *SnmpExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 			Message:   fmt.Sprintf("SNMP query successful, received value: %s", valueStr),
 			StartTime: startTime,
 			EndTime:   endTime,
+			Metadata:  metadata,
 		}
 	}
 
@@ -158,6 +160,7 @@ func (s *SnmpExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 			Message:   fmt.Sprintf("SNMP condition passes (comparing %s %s %s)", valueStr, cfg.JsonPathOperator, cfg.ExpectedValue),
 			StartTime: startTime,
 			EndTime:   endTime,
+			Metadata:  metadata,
 		}
 	} else {
 		s.logger.Infof("SNMP condition failed: %s, comparing %s %s %s", m.Name, valueStr, cfg.JsonPathOperator, cfg.ExpectedValue)
@@ -166,6 +169,7 @@ func (s *SnmpExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Prox
 			Message:   fmt.Sprintf("SNMP condition does not pass (comparing %s %s %s)", valueStr, cfg.JsonPathOperator, cfg.ExpectedValue),
 			StartTime: startTime,
 			EndTime:   endTime,
+			Metadata:  metadata,
 		}
 	}
 }
@@ -338,6 +338,7 @@ func (s *SQLServerExecutor) Execute(ctx context.Context, m *Monitor, proxyModel
 		Message:   fmt.Sprintf("Query successful, ping: %dms, columns: %d, rows: %d", ping, len(columns), rowCount),
 		StartTime: startTime,
 		EndTime:   endTime,
+		Metadata:  Metadata{MetadataRows: rowCount, "columns": len(columns)},
 	}
 }
 
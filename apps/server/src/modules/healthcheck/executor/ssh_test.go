@@ -0,0 +1,273 @@
+package executor
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/ssh"
+)
+
+// fakeSSHServer serves SSH on a local port. Logins succeed with user/pass or clientKey, the
+// "uptime" command prints a fixed line and "false" exits with code 1.
+type fakeSSHServer struct {
+	port      int
+	hostKey   ssh.Signer
+	clientKey string
+}
+
+func newFakeSSHServer(t *testing.T) *fakeSSHServer {
+	t.Helper()
+
+	_, hostPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	hostKey, err := ssh.NewSignerFromKey(hostPriv)
+	require.NoError(t, err)
+
+	_, clientPriv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	clientSigner, err := ssh.NewSignerFromKey(clientPriv)
+	require.NoError(t, err)
+	clientPEM, err := ssh.MarshalPrivateKey(clientPriv, "")
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{
+		ServerVersion: "SSH-2.0-OpenSSH_9.6",
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == "user" && string(password) == "pass" {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("invalid credentials")
+		},
+		PublicKeyCallback: func(conn ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if string(key.Marshal()) == string(clientSigner.PublicKey().Marshal()) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unknown key")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSSH(conn, config)
+		}
+	}()
+
+	return &fakeSSHServer{
+		port:      listener.Addr().(*net.TCPAddr).Port,
+		hostKey:   hostKey,
+		clientKey: string(pem.EncodeToMemory(clientPEM)),
+	}
+}
+
+func serveSSH(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		channel, requests, err := newChannel.Accept()
+		if err != nil {
+			return
+		}
+		for req := range requests {
+			if req.Type != "exec" {
+				req.Reply(false, nil)
+				continue
+			}
+			var payload struct{ Command string }
+			ssh.Unmarshal(req.Payload, &payload)
+			req.Reply(true, nil)
+
+			status := uint32(0)
+			switch payload.Command {
+			case "uptime":
+				channel.Write([]byte(" 10:00:00 up 42 days, load average: 0.00\n"))
+			default:
+				status = 1
+			}
+			channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{status}))
+			channel.Close()
+		}
+	}
+}
+
+func TestSSHExecutor_Validate(t *testing.T) {
+	executor := NewSSHExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "banner only",
+			config:    `{"host":"bastion.example.com","port":22}`,
+			wantError: false,
+		},
+		{
+			name:      "password and command",
+			config:    `{"host":"bastion.example.com","port":22,"username":"user","password":"pass","command":"uptime","keyword":"up"}`,
+			wantError: false,
+		},
+		{
+			name:      "password without username",
+			config:    `{"host":"bastion.example.com","port":22,"password":"pass"}`,
+			wantError: true,
+		},
+		{
+			name:      "username without credentials",
+			config:    `{"host":"bastion.example.com","port":22,"username":"user"}`,
+			wantError: true,
+		},
+		{
+			name:      "command without credentials",
+			config:    `{"host":"bastion.example.com","port":22,"command":"uptime"}`,
+			wantError: true,
+		},
+		{
+			name:      "keyword without command",
+			config:    `{"host":"bastion.example.com","port":22,"username":"user","password":"pass","keyword":"up"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid private key",
+			config:    `{"host":"bastion.example.com","port":22,"username":"user","private_key":"not a key"}`,
+			wantError: true,
+		},
+		{
+			name:      "exit code out of range",
+			config:    `{"host":"bastion.example.com","port":22,"username":"user","password":"pass","command":"uptime","expected_exit_code":256}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSSHExecutor_Execute(t *testing.T) {
+	server := newFakeSSHServer(t)
+	fingerprint := ssh.FingerprintSHA256(server.hostKey.PublicKey())
+	executor := NewSSHExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "banner only",
+			config:      `{"host":"127.0.0.1","port":%d,"expected_banner":"OpenSSH"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "SSH server ready: SSH-2.0-OpenSSH_9.6",
+		},
+		{
+			name:        "unexpected banner",
+			config:      `{"host":"127.0.0.1","port":%d,"expected_banner":"dropbear"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "does not contain",
+		},
+		{
+			name:        "pinned host key",
+			config:      `{"host":"127.0.0.1","port":%d,"host_key_fingerprint":"` + fingerprint + `"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "SSH server ready",
+		},
+		{
+			name:        "host key changed",
+			config:      `{"host":"127.0.0.1","port":%d,"host_key_fingerprint":"SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "host key changed: expected SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8, got " + fingerprint,
+		},
+		{
+			name:        "password login",
+			config:      `{"host":"127.0.0.1","port":%d,"username":"user","password":"pass"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "login successful",
+		},
+		{
+			name:        "wrong password",
+			config:      `{"host":"127.0.0.1","port":%d,"username":"user","password":"wrong"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "authentication failed",
+		},
+		{
+			name:        "private key and command with keyword",
+			config:      fmt.Sprintf(`{"host":"127.0.0.1","port":%%d,"username":"user","private_key":%q,"command":"uptime","keyword":"load average"}`, server.clientKey),
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "command exited with code 0",
+		},
+		{
+			name:        "missing keyword",
+			config:      `{"host":"127.0.0.1","port":%d,"username":"user","password":"pass","command":"uptime","keyword":"down"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "keyword 'down' not found in output",
+		},
+		{
+			name:        "unexpected exit code",
+			config:      `{"host":"127.0.0.1","port":%d,"username":"user","password":"pass","command":"false"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "command exited with code 1, expected 0",
+		},
+		{
+			name:        "expected non zero exit code",
+			config:      `{"host":"127.0.0.1","port":%d,"username":"user","password":"pass","command":"false","expected_exit_code":1}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "command exited with code 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "ssh",
+				Name:    "SSH Monitor",
+				Timeout: 5,
+				Config:  fmt.Sprintf(tt.config, server.port),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+			assert.False(t, result.EndTime.Before(result.StartTime))
+		})
+	}
+}
+
+func TestSameSSHFingerprint(t *testing.T) {
+	actual := "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"
+
+	assert.True(t, sameSSHFingerprint(actual, actual))
+	assert.True(t, sameSSHFingerprint("nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8=", actual))
+	assert.False(t, sameSSHFingerprint("SHA256:AAAAg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8", actual))
+}
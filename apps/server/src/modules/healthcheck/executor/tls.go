@@ -0,0 +1,378 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type TLSConfig struct {
+	Host            string `json:"host" validate:"required" example:"ldap.example.com"`
+	Port            int    `json:"port" validate:"required,min=1,max=65535" example:"636"`
+	ServerName      string `json:"server_name" validate:"omitempty" example:"ldap.internal.example.com"`
+	StartTLS        string `json:"starttls" validate:"omitempty,oneof=smtp imap postgres ldap" example:"smtp"`
+	Fingerprint     string `json:"fingerprint" validate:"omitempty" example:"3F:2A:9C:..."`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors" example:"false"`
+	CheckCertExpiry bool   `json:"check_cert_expiry" example:"true"`
+}
+
+type TLSExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewTLSExecutor(logger *zap.SugaredLogger) *TLSExecutor {
+	return &TLSExecutor{
+		logger: logger,
+	}
+}
+
+func (s *TLSExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[TLSConfig](configJSON)
+}
+
+func (s *TLSExecutor) Validate(configJSON string) error {
+	cfg, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	tlsCfg := cfg.(*TLSConfig)
+	if err := GenericValidator(tlsCfg); err != nil {
+		return err
+	}
+
+	if tlsCfg.Fingerprint != "" {
+		if _, err := normalizeFingerprint(tlsCfg.Fingerprint); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *TLSExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := s.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*TLSConfig)
+
+	s.logger.Debugf("execute tls cfg: %s:%d", cfg.Host, cfg.Port)
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	serverName := cfg.ServerName
+	if serverName == "" {
+		serverName = cfg.Host
+	}
+
+	startTime := time.Now().UTC()
+	tlsConn, tlsInfo, err := s.handshake(ctx, cfg, serverName, timeout)
+	endTime := time.Now().UTC()
+
+	if err == nil {
+		defer tlsConn.Close()
+		err = checkFingerprint(tlsInfo, cfg.Fingerprint)
+	}
+	if err != nil {
+		s.logger.Infof("TLS check failed: %s, %s", m.Name, err.Error())
+		result := DownResult(err, startTime, endTime)
+		result.TLSInfo = tlsInfo
+		return result
+	}
+
+	s.logger.Infof("TLS check successful: %s", m.Name)
+
+	state := tlsConn.ConnectionState()
+	certInfo := tlsInfo.CertInfo
+
+	message := fmt.Sprintf("TLS handshake successful (%s), certificate %s expires in %d days",
+		tls.VersionName(state.Version), certInfo.Subject, certInfo.DaysRemaining)
+	if !tlsInfo.Valid {
+		message += ", certificate not trusted"
+	}
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   endTime,
+		TLSInfo:   tlsInfo,
+		Metadata: Metadata{
+			"tls_version":    tls.VersionName(state.Version),
+			"cipher_suite":   tls.CipherSuiteName(state.CipherSuite),
+			"fingerprint256": certInfo.Fingerprint256,
+			"days_remaining": certInfo.DaysRemaining,
+		},
+	}
+}
+
+// handshake connects to the endpoint, negotiates STARTTLS when configured and runs the TLS handshake
+func (s *TLSExecutor) handshake(ctx context.Context, cfg *TLSConfig, serverName string, timeout time.Duration) (*tls.Conn, *certificate.TLSInfo, error) {
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, nil, fmt.Errorf("connection failed: %w", err)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if cfg.StartTLS != "" {
+		if err := negotiateStartTLS(conn, cfg.StartTLS); err != nil {
+			conn.Close()
+			return nil, nil, fmt.Errorf("%s STARTTLS failed: %w", cfg.StartTLS, err)
+		}
+	}
+
+	tlsConn, tlsInfo, err := handshakeTLS(ctx, conn, serverName, cfg.IgnoreTlsErrors)
+	if err != nil {
+		conn.Close()
+		return nil, tlsInfo, err
+	}
+
+	return tlsConn, tlsInfo, nil
+}
+
+// negotiateStartTLS asks the server to switch the plain text connection to TLS
+func negotiateStartTLS(conn net.Conn, protocol string) error {
+	switch protocol {
+	case "smtp":
+		text := textproto.NewConn(conn)
+		if _, _, err := text.ReadResponse(220); err != nil {
+			return err
+		}
+		if err := smtpCommand(text, "EHLO localhost", 250); err != nil {
+			return err
+		}
+		return smtpCommand(text, "STARTTLS", 220)
+	case "imap":
+		text := textproto.NewConn(conn)
+		greeting, err := text.ReadLine()
+		if err != nil {
+			return err
+		}
+		if !strings.HasPrefix(greeting, "* OK") {
+			return fmt.Errorf("unexpected greeting: %s", greeting)
+		}
+		if err := text.PrintfLine("a1 STARTTLS"); err != nil {
+			return err
+		}
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(line, "a1 ") {
+				if !strings.HasPrefix(line, "a1 OK") {
+					return errors.New(strings.TrimPrefix(line, "a1 "))
+				}
+				return nil
+			}
+		}
+	case "postgres":
+		return postgresSSLRequest(conn)
+	case "ldap":
+		return ldapStartTLS(conn)
+	default:
+		return fmt.Errorf("unsupported protocol: %s", protocol)
+	}
+}
+
+// postgresSSLRequestCode is the protocol version number PostgreSQL reserves for SSLRequest
+const postgresSSLRequestCode = 80877103
+
+func postgresSSLRequest(conn net.Conn) error {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	response := make([]byte, 1)
+	if _, err := io.ReadFull(conn, response); err != nil {
+		return err
+	}
+	if response[0] != 'S' {
+		return errors.New("server does not accept SSL connections")
+	}
+	return nil
+}
+
+// ldapStartTLSOID is the name of the LDAP StartTLS extended operation (RFC 4511)
+const ldapStartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+func ldapStartTLS(conn net.Conn) error {
+	// LDAPMessage { messageID 1, ExtendedRequest { requestName ldapStartTLSOID } }
+	requestName := append([]byte{0x80, byte(len(ldapStartTLSOID))}, ldapStartTLSOID...)
+	extendedRequest := append([]byte{0x77, byte(len(requestName))}, requestName...)
+	body := append([]byte{0x02, 0x01, 0x01}, extendedRequest...)
+	message := append([]byte{0x30, byte(len(body))}, body...)
+	if _, err := conn.Write(message); err != nil {
+		return err
+	}
+
+	tag, body, err := readBER(conn)
+	if err != nil {
+		return err
+	}
+	if tag != 0x30 {
+		return fmt.Errorf("unexpected LDAP message tag 0x%02x", tag)
+	}
+
+	// Skip the message ID and read the result code of the ExtendedResponse
+	_, _, rest, err := splitBER(body)
+	if err != nil {
+		return err
+	}
+	tag, response, _, err := splitBER(rest)
+	if err != nil {
+		return err
+	}
+	if tag != 0x78 {
+		return fmt.Errorf("unexpected LDAP response tag 0x%02x", tag)
+	}
+	tag, resultCode, _, err := splitBER(response)
+	if err != nil {
+		return err
+	}
+	if tag != 0x0a || len(resultCode) != 1 {
+		return errors.New("malformed LDAP result code")
+	}
+	if resultCode[0] != 0 {
+		return fmt.Errorf("server rejected StartTLS with result code %d", resultCode[0])
+	}
+	return nil
+}
+
+// readBER reads a single BER element from r and returns its tag and contents
+func readBER(r io.Reader) (byte, []byte, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	length := int(header[1])
+	if header[1]&0x80 != 0 {
+		size := int(header[1] & 0x7f)
+		if size == 0 || size > 4 {
+			return 0, nil, errors.New("unsupported BER length")
+		}
+		lengthBytes := make([]byte, size)
+		if _, err := io.ReadFull(r, lengthBytes); err != nil {
+			return 0, nil, err
+		}
+		length = 0
+		for _, b := range lengthBytes {
+			length = length<<8 | int(b)
+		}
+	}
+
+	contents := make([]byte, length)
+	if _, err := io.ReadFull(r, contents); err != nil {
+		return 0, nil, err
+	}
+	return header[0], contents, nil
+}
+
+// splitBER returns the tag and contents of the first BER element in data and the bytes after it
+func splitBER(data []byte) (byte, []byte, []byte, error) {
+	reader := bytes.NewReader(data)
+	tag, contents, err := readBER(reader)
+	if err != nil {
+		return 0, nil, nil, fmt.Errorf("malformed LDAP response: %w", err)
+	}
+	return tag, contents, data[len(data)-reader.Len():], nil
+}
+
+// normalizeFingerprint turns a SHA-256 fingerprint in hex, with or without separators, into
+// the colon separated upper case form stored in CertificateInfo.Fingerprint256
+func normalizeFingerprint(fingerprint string) (string, error) {
+	hexDigits := strings.NewReplacer(":", "", " ", "", "-", "").Replace(strings.ToUpper(fingerprint))
+	raw, err := hex.DecodeString(hexDigits)
+	if err != nil || len(raw) != sha256.Size {
+		return "", errors.New("fingerprint must be a hex encoded SHA-256 hash")
+	}
+
+	parts := make([]string, len(raw))
+	for i, b := range raw {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// checkFingerprint compares the certificate against the pinned fingerprint, if any
+func checkFingerprint(tlsInfo *certificate.TLSInfo, pinned string) error {
+	if pinned == "" {
+		return nil
+	}
+	expected, err := normalizeFingerprint(pinned)
+	if err != nil {
+		return err
+	}
+	if tlsInfo == nil || tlsInfo.CertInfo == nil || tlsInfo.CertInfo.Fingerprint256 != expected {
+		return errors.New("certificate fingerprint does not match the pinned fingerprint")
+	}
+	return nil
+}
+
+// handshakeTLS runs a TLS handshake over conn and verifies the server certificate for
+// serverName. The certificate info is returned even when verification fails, so expiry
+// tracking keeps working for servers with broken chains.
+func handshakeTLS(ctx context.Context, conn net.Conn, serverName string, ignoreTLSErrors bool) (*tls.Conn, *certificate.TLSInfo, error) {
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName: serverName,
+		// Verified below so the certificate can be reported when it is invalid
+		InsecureSkipVerify: true,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return nil, nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+
+	state := tlsConn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		return nil, &certificate.TLSInfo{Valid: false}, errors.New("server presented no certificate")
+	}
+
+	verifyErr := verifyServerCertificate(state.PeerCertificates, serverName)
+	tlsInfo := certificate.ParseCertificateChain(state.PeerCertificates[0], verifyErr == nil)
+	if verifyErr != nil && !ignoreTLSErrors {
+		return nil, tlsInfo, fmt.Errorf("certificate verification failed: %w", verifyErr)
+	}
+
+	return tlsConn, tlsInfo, nil
+}
+
+// verifyServerCertificate verifies the presented chain against the system roots and serverName
+func verifyServerCertificate(certs []*x509.Certificate, serverName string) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Intermediates: intermediates,
+	})
+	return err
+}
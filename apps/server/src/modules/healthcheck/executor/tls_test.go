@@ -0,0 +1,225 @@
+package executor
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// finishTLS completes the server side handshake and waits for the client to hang up
+func finishTLS(c *mailConn) {
+	c.conn.(*tls.Conn).Handshake()
+	io.Copy(io.Discard, c.conn)
+}
+
+func fakePostgresSSL(c *mailConn) {
+	request := make([]byte, 8)
+	if _, err := io.ReadFull(c.conn, request); err != nil {
+		return
+	}
+	c.conn.Write([]byte("S"))
+	c.startTLS()
+	finishTLS(c)
+}
+
+func fakeLDAPStartTLS(c *mailConn) {
+	if _, _, err := readBER(c.conn); err != nil {
+		return
+	}
+	// ExtendedResponse { resultCode success, matchedDN "", diagnosticMessage "" }
+	c.conn.Write([]byte{0x30, 0x0c, 0x02, 0x01, 0x01, 0x78, 0x07, 0x0a, 0x01, 0x00, 0x04, 0x00, 0x04, 0x00})
+	c.startTLS()
+	finishTLS(c)
+}
+
+func fakeIMAPStartTLS(c *mailConn) {
+	c.send("* OK IMAP4rev1 ready")
+	line, err := c.text.ReadLine()
+	if err != nil {
+		return
+	}
+	tag, _, _ := strings.Cut(line, " ")
+	c.send(tag + " OK begin TLS")
+	c.startTLS()
+	finishTLS(c)
+}
+
+func TestTLSExecutor_Validate(t *testing.T) {
+	executor := NewTLSExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid config",
+			config:    `{"host":"ldap.example.com","port":636}`,
+			wantError: false,
+		},
+		{
+			name:      "valid config with starttls and pin",
+			config:    `{"host":"db.example.com","port":5432,"starttls":"postgres","server_name":"db.internal","fingerprint":"` + strings.Repeat("ab:", 31) + `ab"}`,
+			wantError: false,
+		},
+		{
+			name:      "unknown starttls protocol",
+			config:    `{"host":"ftp.example.com","port":21,"starttls":"ftp"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid fingerprint",
+			config:    `{"host":"ldap.example.com","port":636,"fingerprint":"not-a-hash"}`,
+			wantError: true,
+		},
+		{
+			name:      "sha1 fingerprint",
+			config:    `{"host":"ldap.example.com","port":636,"fingerprint":"` + strings.Repeat("AB", 20) + `"}`,
+			wantError: true,
+		},
+		{
+			name:      "missing port",
+			config:    `{"host":"ldap.example.com"}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTLSExecutor_Execute(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	port := server.Listener.Addr().(*net.TCPAddr).Port
+
+	sum := sha256.Sum256(server.Certificate().Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	executor := NewTLSExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "untrusted certificate",
+			config:      `{"host":"127.0.0.1","port":%d}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "certificate verification failed",
+		},
+		{
+			name:        "ignored certificate errors",
+			config:      `{"host":"127.0.0.1","port":%d,"ignore_tls_errors":true}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "certificate not trusted",
+		},
+		{
+			name:        "matching fingerprint",
+			config:      `{"host":"127.0.0.1","port":%d,"server_name":"example.com","ignore_tls_errors":true,"fingerprint":"` + fingerprint + `"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "TLS handshake successful",
+		},
+		{
+			name:        "mismatching fingerprint",
+			config:      `{"host":"127.0.0.1","port":%d,"ignore_tls_errors":true,"fingerprint":"` + strings.Repeat("00", 32) + `"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "does not match the pinned fingerprint",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "tls",
+				Name:    "TLS Monitor",
+				Timeout: 5,
+				Config:  fmt.Sprintf(tt.config, port),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+			require.NotNil(t, result.TLSInfo)
+			require.NotNil(t, result.TLSInfo.CertInfo)
+			assert.False(t, result.TLSInfo.Valid)
+			assert.Contains(t, result.TLSInfo.CertInfo.ValidFor, "127.0.0.1")
+		})
+	}
+}
+
+func TestTLSExecutor_ExecuteStartTLS(t *testing.T) {
+	executor := NewTLSExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		protocol string
+		server   func(c *mailConn)
+	}{
+		{protocol: "smtp", server: fakeSMTP},
+		{protocol: "imap", server: fakeIMAPStartTLS},
+		{protocol: "postgres", server: fakePostgresSSL},
+		{protocol: "ldap", server: fakeLDAPStartTLS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.protocol, func(t *testing.T) {
+			port := serveMail(t, false, tt.server)
+
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "tls",
+				Name:    "TLS Monitor",
+				Timeout: 5,
+				Config:  fmt.Sprintf(`{"host":"127.0.0.1","port":%d,"starttls":%q,"ignore_tls_errors":true}`, port, tt.protocol),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+			require.NotNil(t, result.TLSInfo)
+			assert.NotEmpty(t, result.Metadata["tls_version"])
+		})
+	}
+}
+
+func TestTLSExecutor_ExecuteStartTLSRejected(t *testing.T) {
+	port := serveMail(t, false, func(c *mailConn) {
+		request := make([]byte, 8)
+		io.ReadFull(c.conn, request)
+		c.conn.Write([]byte("N"))
+	})
+
+	executor := NewTLSExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "tls",
+		Name:    "TLS Monitor",
+		Timeout: 5,
+		Config:  fmt.Sprintf(`{"host":"127.0.0.1","port":%d,"starttls":"postgres"}`, port),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "postgres STARTTLS failed")
+	assert.Nil(t, result.TLSInfo)
+}
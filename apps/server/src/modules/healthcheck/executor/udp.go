@@ -0,0 +1,325 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/miekg/dns"
+	"go.uber.org/zap"
+)
+
+const (
+	// udpSilentWait is how long a service that does not answer is given to report the port
+	// unreachable
+	udpSilentWait = time.Second
+	udpMaxPacket  = 65535
+	// udpResponseHexLength is the number of response bytes kept in the heartbeat
+	udpResponseHexLength = 64
+)
+
+type UDPConfig struct {
+	Host string `json:"host" validate:"required" example:"example.com"`
+	Port int    `json:"port" validate:"required,min=1,max=65535" example:"123"`
+	// Preset sends the probe of a common protocol and checks its answer: ntp, dns or syslog
+	Preset string `json:"preset" validate:"omitempty,oneof=ntp dns syslog" example:"ntp"`
+	// Payload is sent instead of the probe of the preset
+	Payload       string `json:"payload" validate:"omitempty" example:"ping"`
+	PayloadFormat string `json:"payload_format" validate:"omitempty,oneof=text hex" example:"text"`
+	// NoResponse is set for services that never answer, the monitor is UP unless the port is
+	// reported unreachable
+	NoResponse bool `json:"no_response" example:"false"`
+
+	// Response validation fields
+	ResponsePrefix string `json:"response_prefix,omitempty" example:"ff ff ff ff"`
+	Keyword        string `json:"keyword,omitempty"`
+	InvertKeyword  bool   `json:"invert_keyword,omitempty"`
+	MinLength      int    `json:"min_length,omitempty" validate:"omitempty,min=0,max=65535"`
+	MaxLength      int    `json:"max_length,omitempty" validate:"omitempty,min=0,max=65535"`
+}
+
+// udpPreset is the probe of a well-known protocol
+type udpPreset struct {
+	payload func() ([]byte, error)
+	// check validates the answer to payload and adds what it found to the metadata,
+	// nil for protocols without answer
+	check func(request, response []byte, metadata Metadata) error
+}
+
+var udpPresets = map[string]udpPreset{
+	"ntp":    {payload: ntpRequest, check: checkNTPResponse},
+	"dns":    {payload: dnsRequest, check: checkDNSResponse},
+	"syslog": {payload: syslogMessage},
+}
+
+type UDPExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewUDPExecutor(logger *zap.SugaredLogger) *UDPExecutor {
+	return &UDPExecutor{
+		logger: logger,
+	}
+}
+
+func (s *UDPExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[UDPConfig](configJSON)
+}
+
+func (s *UDPExecutor) Validate(configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*UDPConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	if cfg.Preset == "" && cfg.Payload == "" {
+		return errors.New("either a preset or a payload is required")
+	}
+	if cfg.PayloadFormat == "hex" {
+		if _, err := decodeHex(cfg.Payload); err != nil {
+			return fmt.Errorf("invalid hex payload: %w", err)
+		}
+	}
+	if _, err := decodeHex(cfg.ResponsePrefix); err != nil {
+		return fmt.Errorf("invalid hex response prefix: %w", err)
+	}
+	if cfg.MaxLength > 0 && cfg.MinLength > cfg.MaxLength {
+		return errors.New("min_length must not be greater than max_length")
+	}
+	return nil
+}
+
+func (u *UDPExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := u.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*UDPConfig)
+
+	u.logger.Debugf("execute udp cfg: %+v", cfg)
+
+	preset, hasPreset := udpPresets[cfg.Preset]
+	expectResponse := !cfg.NoResponse && (!hasPreset || preset.check != nil)
+
+	payload, err := udpPayload(cfg, preset)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	// A custom payload gets a custom answer, the preset check only applies to its own probe
+	if cfg.Payload != "" {
+		preset.check = nil
+	}
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	startTime := time.Now().UTC()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		u.logger.Infof("UDP check failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("UDP connection failed: %w", err), startTime, time.Now().UTC())
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if !expectResponse {
+		deadline = time.Now().Add(min(udpSilentWait, timeout))
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := conn.Write(payload); err != nil {
+		u.logger.Infof("UDP check failed: %s, %s", m.Name, err.Error())
+		return DownResult(fmt.Errorf("failed to send UDP packet: %w", err), startTime, time.Now().UTC())
+	}
+	sentAt := time.Now().UTC()
+
+	buf := make([]byte, udpMaxPacket)
+	n, err := conn.Read(buf)
+	endTime := time.Now().UTC()
+	response := buf[:n]
+
+	if !expectResponse {
+		return u.silentResult(m, cfg, err, sentAt, endTime)
+	}
+
+	if err != nil {
+		var netErr net.Error
+		message := fmt.Sprintf("UDP request failed: %v", err)
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			message = fmt.Sprintf("No UDP response from %s within %v", address, timeout)
+		} else if isPortUnreachable(err) {
+			message = fmt.Sprintf("UDP port %d is unreachable", cfg.Port)
+		}
+		u.logger.Infof("UDP check failed: %s, %s", m.Name, message)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   message,
+			StartTime: startTime,
+			EndTime:   endTime,
+		}
+	}
+
+	metadata := Metadata{
+		MetadataResponseSize: len(response),
+		"response_hex":       hex.EncodeToString(response[:min(len(response), udpResponseHexLength)]),
+	}
+
+	if err := u.checkResponse(cfg, preset, payload, response, metadata); err != nil {
+		u.logger.Infof("UDP check failed: %s, %s", m.Name, err.Error())
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   err.Error(),
+			StartTime: startTime,
+			EndTime:   endTime,
+			Metadata:  metadata,
+		}
+	}
+
+	u.logger.Infof("UDP check successful: %s", m.Name)
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("UDP response received: %d bytes", len(response)),
+		StartTime: startTime,
+		EndTime:   endTime,
+		Metadata:  metadata,
+	}
+}
+
+// silentResult judges a service that does not answer, only an unreachable port is a failure
+func (u *UDPExecutor) silentResult(m *Monitor, cfg *UDPConfig, err error, sentAt, endTime time.Time) *Result {
+	if err != nil && isPortUnreachable(err) {
+		u.logger.Infof("UDP check failed: %s, port %d unreachable", m.Name, cfg.Port)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   fmt.Sprintf("UDP port %d is unreachable", cfg.Port),
+			StartTime: sentAt,
+			EndTime:   endTime,
+		}
+	}
+
+	u.logger.Infof("UDP check successful: %s, no port unreachable reported", m.Name)
+
+	// Waiting for an error that did not come is not a response time
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   fmt.Sprintf("UDP packet sent to port %d, no port unreachable reported", cfg.Port),
+		StartTime: sentAt,
+		EndTime:   sentAt,
+	}
+}
+
+func (u *UDPExecutor) checkResponse(cfg *UDPConfig, preset udpPreset, request, response []byte, metadata Metadata) error {
+	if preset.check != nil {
+		if err := preset.check(request, response, metadata); err != nil {
+			return err
+		}
+	}
+
+	if prefix, _ := decodeHex(cfg.ResponsePrefix); len(prefix) > 0 && !bytes.HasPrefix(response, prefix) {
+		return fmt.Errorf("response does not start with %s", hex.EncodeToString(prefix))
+	}
+	if cfg.MinLength > 0 && len(response) < cfg.MinLength {
+		return fmt.Errorf("response of %d bytes is shorter than %d bytes", len(response), cfg.MinLength)
+	}
+	if cfg.MaxLength > 0 && len(response) > cfg.MaxLength {
+		return fmt.Errorf("response of %d bytes is longer than %d bytes", len(response), cfg.MaxLength)
+	}
+	if !checkKeyword(string(response), cfg.Keyword, cfg.InvertKeyword) {
+		if cfg.InvertKeyword {
+			return fmt.Errorf("Keyword check failed: keyword '%s' found in response (expected absent)", cfg.Keyword)
+		}
+		return fmt.Errorf("Keyword check failed: keyword '%s' not found in response", cfg.Keyword)
+	}
+	return nil
+}
+
+func udpPayload(cfg *UDPConfig, preset udpPreset) ([]byte, error) {
+	switch {
+	case cfg.Payload != "" && cfg.PayloadFormat == "hex":
+		return decodeHex(cfg.Payload)
+	case cfg.Payload != "":
+		return []byte(cfg.Payload), nil
+	case preset.payload != nil:
+		return preset.payload()
+	default:
+		return nil, errors.New("either a preset or a payload is required")
+	}
+}
+
+// decodeHex decodes hex strings that may separate the bytes with spaces or colons
+func decodeHex(value string) ([]byte, error) {
+	return hex.DecodeString(strings.NewReplacer(" ", "", ":", "", "0x", "").Replace(value))
+}
+
+// isPortUnreachable reports whether an ICMP port unreachable was received for the socket
+func isPortUnreachable(err error) bool {
+	var sysErr *os.SyscallError
+	return errors.As(err, &sysErr) && errors.Is(sysErr.Err, syscall.ECONNREFUSED)
+}
+
+// ntpRequest is an SNTP client request (RFC 4330), version 4 in client mode
+func ntpRequest() ([]byte, error) {
+	request := make([]byte, ntpPacketSize)
+	request[0] = 4<<3 | ntpModeClient
+	return request, nil
+}
+
+func checkNTPResponse(request, response []byte, metadata Metadata) error {
+	packet, err := parseNTPPacket(response)
+	if err != nil {
+		return err
+	}
+	metadata["stratum"] = int(packet.Stratum)
+	return packet.syncError()
+}
+
+// dnsRequest asks for the root name servers, any name server answers it with records or an error
+func dnsRequest() ([]byte, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(".", dns.TypeNS)
+	return msg.Pack()
+}
+
+func checkDNSResponse(request, response []byte, metadata Metadata) error {
+	var query, answer dns.Msg
+	if err := query.Unpack(request); err != nil {
+		return err
+	}
+	if err := answer.Unpack(response); err != nil {
+		return fmt.Errorf("invalid DNS response: %w", err)
+	}
+	if !answer.Response || answer.Id != query.Id {
+		return errors.New("response does not answer the DNS query")
+	}
+	metadata["rcode"] = dns.RcodeToString[answer.Rcode]
+	return nil
+}
+
+// syslogMessage is an RFC 5424 notice from the user facility
+func syslogMessage() ([]byte, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "-"
+	}
+	return fmt.Appendf(nil, "<13>1 %s %s peekaping - - - Peekaping syslog probe",
+		time.Now().UTC().Format(time.RFC3339), hostname), nil
+}
@@ -0,0 +1,266 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// serveUDP answers every datagram with the result of reply, nil replies are dropped
+func serveUDP(t *testing.T, reply func(request []byte) []byte) int {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 65535)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if response := reply(buf[:n]); response != nil {
+				conn.WriteTo(response, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// closedUDPPort returns a port nothing listens on
+func closedUDPPort(t *testing.T) int {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	port := conn.LocalAddr().(*net.UDPAddr).Port
+	conn.Close()
+	return port
+}
+
+func echoUDP(request []byte) []byte {
+	return append([]byte("echo: "), request...)
+}
+
+func fakeNTP(stratum byte) func(request []byte) []byte {
+	return func(request []byte) []byte {
+		response := make([]byte, 48)
+		response[0] = 4<<3 | 4 // version 4, server mode
+		response[1] = stratum
+		if stratum == 0 {
+			copy(response[12:], "RATE")
+		}
+		return response
+	}
+}
+
+func fakeDNS(request []byte) []byte {
+	var query dns.Msg
+	if err := query.Unpack(request); err != nil {
+		return nil
+	}
+	answer := new(dns.Msg)
+	answer.SetRcode(&query, dns.RcodeRefused)
+	packed, _ := answer.Pack()
+	return packed
+}
+
+func TestUDPExecutor_Validate(t *testing.T) {
+	executor := NewUDPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "preset",
+			config:    `{"host":"pool.ntp.org","port":123,"preset":"ntp"}`,
+			wantError: false,
+		},
+		{
+			name:      "text payload with checks",
+			config:    `{"host":"127.0.0.1","port":27015,"payload":"status","keyword":"players","min_length":4,"max_length":512}`,
+			wantError: false,
+		},
+		{
+			name:      "hex payload",
+			config:    `{"host":"127.0.0.1","port":27015,"payload":"ff ff ff ff 54","payload_format":"hex","response_prefix":"ff:ff:ff:ff"}`,
+			wantError: false,
+		},
+		{
+			name:      "neither preset nor payload",
+			config:    `{"host":"127.0.0.1","port":27015}`,
+			wantError: true,
+		},
+		{
+			name:      "unknown preset",
+			config:    `{"host":"127.0.0.1","port":161,"preset":"snmp"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid hex payload",
+			config:    `{"host":"127.0.0.1","port":27015,"payload":"zz","payload_format":"hex"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid response prefix",
+			config:    `{"host":"127.0.0.1","port":27015,"payload":"x","response_prefix":"f"}`,
+			wantError: true,
+		},
+		{
+			name:      "min length above max length",
+			config:    `{"host":"127.0.0.1","port":27015,"payload":"x","min_length":10,"max_length":5}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestUDPExecutor_Execute(t *testing.T) {
+	executor := NewUDPExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		port        func(t *testing.T) int
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "text payload with keyword",
+			port:        func(t *testing.T) int { return serveUDP(t, echoUDP) },
+			config:      `{"host":"127.0.0.1","port":%d,"payload":"status","keyword":"echo: status"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "UDP response received: 12 bytes",
+		},
+		{
+			name:        "hex payload with prefix",
+			port:        func(t *testing.T) int { return serveUDP(t, echoUDP) },
+			config:      `{"host":"127.0.0.1","port":%d,"payload":"ff ff","payload_format":"hex","response_prefix":"6563686f"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "UDP response received",
+		},
+		{
+			name:        "wrong prefix",
+			port:        func(t *testing.T) int { return serveUDP(t, echoUDP) },
+			config:      `{"host":"127.0.0.1","port":%d,"payload":"x","response_prefix":"ffff"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "response does not start with ffff",
+		},
+		{
+			name:        "response too short",
+			port:        func(t *testing.T) int { return serveUDP(t, echoUDP) },
+			config:      `{"host":"127.0.0.1","port":%d,"payload":"x","min_length":100}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "response of 7 bytes is shorter than 100 bytes",
+		},
+		{
+			name:        "no response",
+			port:        func(t *testing.T) int { return serveUDP(t, func([]byte) []byte { return nil }) },
+			config:      `{"host":"127.0.0.1","port":%d,"payload":"x"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "No UDP response",
+		},
+		{
+			name:        "port unreachable",
+			port:        closedUDPPort,
+			config:      `{"host":"127.0.0.1","port":%d,"payload":"x"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "is unreachable",
+		},
+		{
+			name:        "ntp preset",
+			port:        func(t *testing.T) int { return serveUDP(t, fakeNTP(2)) },
+			config:      `{"host":"127.0.0.1","port":%d,"preset":"ntp"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "UDP response received: 48 bytes",
+		},
+		{
+			name:        "ntp kiss-o'-death",
+			port:        func(t *testing.T) int { return serveUDP(t, fakeNTP(0)) },
+			config:      `{"host":"127.0.0.1","port":%d,"preset":"ntp"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: `NTP server sent kiss-o'-death "RATE"`,
+		},
+		{
+			name:        "ntp preset against other service",
+			port:        func(t *testing.T) int { return serveUDP(t, echoUDP) },
+			config:      `{"host":"127.0.0.1","port":%d,"preset":"ntp"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "unexpected NTP mode",
+		},
+		{
+			name:        "dns preset",
+			port:        func(t *testing.T) int { return serveUDP(t, fakeDNS) },
+			config:      `{"host":"127.0.0.1","port":%d,"preset":"dns"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "UDP response received",
+		},
+		{
+			name:        "syslog preset",
+			port:        func(t *testing.T) int { return serveUDP(t, func([]byte) []byte { return nil }) },
+			config:      `{"host":"127.0.0.1","port":%d,"preset":"syslog"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "no port unreachable reported",
+		},
+		{
+			name:        "syslog preset unreachable",
+			port:        closedUDPPort,
+			config:      `{"host":"127.0.0.1","port":%d,"preset":"syslog"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "is unreachable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "udp",
+				Name:    "UDP Monitor",
+				Timeout: 1,
+				Config:  fmt.Sprintf(tt.config, tt.port(t)),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+		})
+	}
+}
+
+func TestUDPExecutor_ExecuteMetadata(t *testing.T) {
+	port := serveUDP(t, fakeDNS)
+
+	executor := NewUDPExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "udp",
+		Name:    "UDP Monitor",
+		Timeout: 1,
+		Config:  fmt.Sprintf(`{"host":"127.0.0.1","port":%d,"preset":"dns"}`, port),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, "REFUSED", result.Metadata["rcode"])
+	assert.Contains(t, result.Metadata, MetadataResponseSize)
+	assert.Contains(t, result.Metadata, "response_hex")
+}
@@ -0,0 +1,249 @@
+package executor
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/shared"
+	"peekaping/src/version"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+// websocketMaxReply bounds the size of a reply and the part of it kept in the heartbeat
+const (
+	websocketMaxReply    = 1 << 20
+	websocketReplyLength = 1024
+)
+
+type WebSocketConfig struct {
+	Url          string   `json:"url" validate:"required,url" example:"wss://example.com/socket"`
+	Headers      string   `json:"headers" validate:"omitempty,json" example:"{\"Authorization\":\"Bearer token\"}"`
+	Subprotocols []string `json:"subprotocols" validate:"omitempty,dive,required" example:"graphql-ws"`
+	// Message is sent once the connection is open, the monitor then waits for a reply
+	Message         string `json:"message" validate:"omitempty" example:"{\"type\":\"ping\"}"`
+	IgnoreTlsErrors bool   `json:"ignore_tls_errors"`
+	CheckCertExpiry bool   `json:"check_cert_expiry"`
+
+	// Reply validation fields, replies are read until one passes the checks
+	Keyword       string `json:"keyword,omitempty"`
+	InvertKeyword bool   `json:"invert_keyword,omitempty"`
+	JsonQuery     string `json:"json_query,omitempty"`
+	JsonCondition string `json:"json_condition,omitempty" validate:"omitempty,oneof='==' '!=' '>' '<' '>=' '<='"`
+	ExpectedValue string `json:"expected_value,omitempty"`
+}
+
+// expectsReply reports whether the monitor waits for a message after the handshake
+func (c *WebSocketConfig) expectsReply() bool {
+	return c.Message != "" || c.Keyword != "" || c.JsonQuery != "" || c.ExpectedValue != ""
+}
+
+type WebSocketExecutor struct {
+	logger *zap.SugaredLogger
+}
+
+func NewWebSocketExecutor(logger *zap.SugaredLogger) *WebSocketExecutor {
+	return &WebSocketExecutor{
+		logger: logger,
+	}
+}
+
+func (s *WebSocketExecutor) Unmarshal(configJSON string) (any, error) {
+	return GenericUnmarshal[WebSocketConfig](configJSON)
+}
+
+func (s *WebSocketExecutor) Validate(configJSON string) error {
+	cfgAny, err := s.Unmarshal(configJSON)
+	if err != nil {
+		return err
+	}
+	cfg := cfgAny.(*WebSocketConfig)
+	if err := GenericValidator(cfg); err != nil {
+		return err
+	}
+
+	u, err := url.Parse(cfg.Url)
+	if err != nil || (u.Scheme != "ws" && u.Scheme != "wss") {
+		return fmt.Errorf("url must use the ws or wss scheme")
+	}
+	return nil
+}
+
+func (w *WebSocketExecutor) Execute(ctx context.Context, m *Monitor, proxyModel *Proxy) *Result {
+	cfgAny, err := w.Unmarshal(m.Config)
+	if err != nil {
+		return DownResult(err, time.Now().UTC(), time.Now().UTC())
+	}
+	cfg := cfgAny.(*WebSocketConfig)
+
+	w.logger.Debugf("execute websocket cfg: %+v", cfg)
+
+	header := http.Header{}
+	header.Set("User-Agent", "peekaping/"+version.Version)
+	if cfg.Headers != "" {
+		headersMap := make(map[string]string)
+		if err := json.Unmarshal([]byte(cfg.Headers), &headersMap); err != nil {
+			return DownResult(fmt.Errorf("invalid headers: %w", err), time.Now().UTC(), time.Now().UTC())
+		}
+		for key, value := range headersMap {
+			header.Set(key, value)
+		}
+	}
+
+	timeout := time.Duration(m.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The proxy settings of the HTTP executor apply to the upgrade request as well
+	transport := buildProxyTransport(&http.Transport{}, proxyModel).(*http.Transport)
+	dialer := &websocket.Dialer{
+		Proxy:            transport.Proxy,
+		NetDialContext:   transport.DialContext,
+		TLSClientConfig:  &tls.Config{InsecureSkipVerify: cfg.IgnoreTlsErrors},
+		HandshakeTimeout: timeout,
+		Subprotocols:     cfg.Subprotocols,
+	}
+
+	startTime := time.Now().UTC()
+	conn, resp, err := dialer.DialContext(ctx, cfg.Url, header)
+	handshakeTime := time.Since(startTime)
+	if err != nil {
+		endTime := time.Now().UTC()
+		w.logger.Infof("WebSocket handshake failed: %s, %s", m.Name, err.Error())
+		if resp != nil && errors.Is(err, websocket.ErrBadHandshake) {
+			return &Result{
+				Status:    shared.MonitorStatusDown,
+				Message:   fmt.Sprintf("WebSocket handshake failed with status: %d", resp.StatusCode),
+				StartTime: startTime,
+				EndTime:   endTime,
+				Metadata:  Metadata{MetadataStatusCode: resp.StatusCode},
+			}
+		}
+		return DownResult(fmt.Errorf("WebSocket handshake failed: %w", err), startTime, endTime)
+	}
+	defer conn.Close()
+
+	var tlsInfo *certificate.TLSInfo
+	if tlsConn, ok := conn.NetConn().(*tls.Conn); ok {
+		tlsInfo = certificate.ExtractCertificateFromTLSConn(tlsConn)
+	}
+
+	metadata := Metadata{
+		MetadataStatusCode: resp.StatusCode,
+		"handshake_ms":     durationMs(handshakeTime),
+	}
+	if conn.Subprotocol() != "" {
+		metadata["subprotocol"] = conn.Subprotocol()
+	}
+
+	down := func(message string) *Result {
+		w.logger.Infof("WebSocket check failed: %s, %s", m.Name, message)
+		return &Result{
+			Status:    shared.MonitorStatusDown,
+			Message:   message,
+			StartTime: startTime,
+			EndTime:   time.Now().UTC(),
+			TLSInfo:   tlsInfo,
+			Metadata:  metadata,
+		}
+	}
+
+	if len(cfg.Subprotocols) > 0 && conn.Subprotocol() == "" {
+		return down("Server accepted none of the subprotocols")
+	}
+
+	message := fmt.Sprintf("WebSocket connected in %v", handshakeTime.Round(time.Millisecond))
+
+	if cfg.expectsReply() {
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetReadDeadline(deadline)
+			conn.SetWriteDeadline(deadline)
+		}
+		conn.SetReadLimit(websocketMaxReply)
+
+		sentAt := time.Now()
+		if cfg.Message != "" {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(cfg.Message)); err != nil {
+				return down(fmt.Sprintf("Failed to send message: %v", err))
+			}
+		}
+
+		reply, err := w.awaitReply(conn, cfg)
+		if reply != "" {
+			metadata["reply"] = truncate(reply, websocketReplyLength)
+		}
+		if err != nil {
+			return down(err.Error())
+		}
+
+		roundTrip := time.Since(sentAt)
+		metadata["round_trip_ms"] = durationMs(roundTrip)
+		message += fmt.Sprintf(", reply received in %v", roundTrip.Round(time.Millisecond))
+	}
+
+	// Close the connection politely, the server's answer is not awaited
+	conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+
+	w.logger.Infof("WebSocket check successful: %s, %s", m.Name, message)
+
+	return &Result{
+		Status:    shared.MonitorStatusUp,
+		Message:   message,
+		StartTime: startTime,
+		EndTime:   time.Now().UTC(),
+		TLSInfo:   tlsInfo,
+		Metadata:  metadata,
+	}
+}
+
+// awaitReply reads messages until one passes the keyword and JSON checks and returns the last
+// message read
+func (w *WebSocketExecutor) awaitReply(conn *websocket.Conn, cfg *WebSocketConfig) (string, error) {
+	condition := cfg.JsonCondition
+	if condition == "" {
+		condition = "=="
+	}
+
+	var reply, reason string
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			// The last reply that failed the checks explains the failure better than the timeout
+			if reason != "" {
+				return reply, errors.New(reason)
+			}
+			return reply, fmt.Errorf("No reply received: %v", err)
+		}
+		reply = string(data)
+
+		if !checkKeyword(reply, cfg.Keyword, cfg.InvertKeyword) {
+			if cfg.InvertKeyword {
+				reason = fmt.Sprintf("Keyword check failed: keyword '%s' found in reply (expected absent)", cfg.Keyword)
+			} else {
+				reason = fmt.Sprintf("Keyword check failed: keyword '%s' not found in reply", cfg.Keyword)
+			}
+			w.logger.Debugf("WebSocket reply skipped: %s", reason)
+			continue
+		}
+
+		valid, err := checkJsonQuery(reply, cfg.JsonQuery, cfg.JsonCondition, cfg.ExpectedValue)
+		if err != nil || !valid {
+			reason = fmt.Sprintf("JSON query validation failed: query '%s' with condition '%s' and expected value '%s'",
+				cfg.JsonQuery, condition, cfg.ExpectedValue)
+			if err != nil {
+				reason = fmt.Sprintf("JSON query validation error: %v", err)
+			}
+			w.logger.Debugf("WebSocket reply skipped: %s", reason)
+			continue
+		}
+
+		return reply, nil
+	}
+}
@@ -0,0 +1,278 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"peekaping/src/modules/shared"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// websocketHandler accepts the "chat" subprotocol when requested, rejects requests without the
+// token header and answers "ping" with a status update after a heartbeat message
+func websocketHandler(t *testing.T) http.Handler {
+	upgrader := websocket.Upgrader{Subprotocols: []string{"chat"}}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Token") != "secret" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Logf("upgrade failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == "ping" {
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"heartbeat"}`))
+				conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"status","status":"ok","clients":3}`))
+			}
+		}
+	})
+}
+
+func websocketURL(server *httptest.Server) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestWebSocketExecutor_Validate(t *testing.T) {
+	executor := NewWebSocketExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name      string
+		config    string
+		wantError bool
+	}{
+		{
+			name:      "valid ws url",
+			config:    `{"url":"ws://example.com/socket"}`,
+			wantError: false,
+		},
+		{
+			name:      "valid wss url with message and checks",
+			config:    `{"url":"wss://example.com/socket","headers":"{\"X-Token\":\"secret\"}","subprotocols":["chat"],"message":"ping","keyword":"ok","json_query":"status","json_condition":"==","expected_value":"ok"}`,
+			wantError: false,
+		},
+		{
+			name:      "http scheme",
+			config:    `{"url":"https://example.com/socket"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid headers",
+			config:    `{"url":"ws://example.com/socket","headers":"not json"}`,
+			wantError: true,
+		},
+		{
+			name:      "invalid json condition",
+			config:    `{"url":"ws://example.com/socket","json_query":"status","json_condition":"~="}`,
+			wantError: true,
+		},
+		{
+			name:      "missing url",
+			config:    `{}`,
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := executor.Validate(tt.config)
+			if tt.wantError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebSocketExecutor_Execute(t *testing.T) {
+	server := httptest.NewServer(websocketHandler(t))
+	defer server.Close()
+
+	executor := NewWebSocketExecutor(zap.NewNop().Sugar())
+
+	tests := []struct {
+		name        string
+		config      string
+		wantStatus  shared.MonitorStatus
+		wantMessage string
+	}{
+		{
+			name:        "handshake only",
+			config:      `{"url":%q,"headers":"{\"X-Token\":\"secret\"}"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "WebSocket connected in",
+		},
+		{
+			name:        "rejected handshake",
+			config:      `{"url":%q}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "WebSocket handshake failed with status: 403",
+		},
+		{
+			name:        "reply matches keyword after other messages",
+			config:      `{"url":%q,"headers":"{\"X-Token\":\"secret\"}","message":"ping","keyword":"status"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "reply received in",
+		},
+		{
+			name:        "reply matches json query",
+			config:      `{"url":%q,"headers":"{\"X-Token\":\"secret\"}","message":"ping","json_query":"clients","json_condition":">=","expected_value":"2"}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "reply received in",
+		},
+		{
+			name:        "no reply matches",
+			config:      `{"url":%q,"headers":"{\"X-Token\":\"secret\"}","message":"ping","keyword":"degraded"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "Keyword check failed: keyword 'degraded' not found in reply",
+		},
+		{
+			name:        "no reply at all",
+			config:      `{"url":%q,"headers":"{\"X-Token\":\"secret\"}","message":"hello"}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "No reply received",
+		},
+		{
+			name:        "subprotocol negotiated",
+			config:      `{"url":%q,"headers":"{\"X-Token\":\"secret\"}","subprotocols":["v2.chat","chat"]}`,
+			wantStatus:  shared.MonitorStatusUp,
+			wantMessage: "WebSocket connected in",
+		},
+		{
+			name:        "subprotocol not supported",
+			config:      `{"url":%q,"headers":"{\"X-Token\":\"secret\"}","subprotocols":["mqtt"]}`,
+			wantStatus:  shared.MonitorStatusDown,
+			wantMessage: "Server accepted none of the subprotocols",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := executor.Execute(context.Background(), &Monitor{
+				Type:    "websocket",
+				Name:    "WebSocket Monitor",
+				Timeout: 1,
+				Config:  fmt.Sprintf(tt.config, websocketURL(server)),
+			}, nil)
+
+			require.NotNil(t, result)
+			assert.Equal(t, tt.wantStatus, result.Status, result.Message)
+			assert.Contains(t, result.Message, tt.wantMessage)
+			assert.Nil(t, result.TLSInfo)
+		})
+	}
+}
+
+func TestWebSocketExecutor_ExecuteMetadata(t *testing.T) {
+	server := httptest.NewServer(websocketHandler(t))
+	defer server.Close()
+
+	executor := NewWebSocketExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "websocket",
+		Name:    "WebSocket Monitor",
+		Timeout: 2,
+		Config:  fmt.Sprintf(`{"url":%q,"headers":"{\"X-Token\":\"secret\"}","subprotocols":["chat"],"message":"ping","json_query":"status","expected_value":"ok"}`, websocketURL(server)),
+	}, nil)
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, http.StatusSwitchingProtocols, result.Metadata[MetadataStatusCode])
+	assert.Equal(t, "chat", result.Metadata["subprotocol"])
+	assert.Equal(t, `{"type":"status","status":"ok","clients":3}`, result.Metadata["reply"])
+	assert.Contains(t, result.Metadata, "handshake_ms")
+	assert.Contains(t, result.Metadata, "round_trip_ms")
+}
+
+func TestWebSocketExecutor_ExecuteTLS(t *testing.T) {
+	server := httptest.NewTLSServer(websocketHandler(t))
+	defer server.Close()
+
+	executor := NewWebSocketExecutor(zap.NewNop().Sugar())
+	run := func(config string) *Result {
+		return executor.Execute(context.Background(), &Monitor{
+			Type:    "websocket",
+			Name:    "WebSocket Monitor",
+			Timeout: 2,
+			Config:  fmt.Sprintf(config, websocketURL(server)),
+		}, nil)
+	}
+
+	result := run(`{"url":%q,"headers":"{\"X-Token\":\"secret\"}"}`)
+	assert.Equal(t, shared.MonitorStatusDown, result.Status)
+	assert.Contains(t, result.Message, "certificate")
+
+	result = run(`{"url":%q,"headers":"{\"X-Token\":\"secret\"}","ignore_tls_errors":true}`)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	require.NotNil(t, result.TLSInfo)
+	assert.NotNil(t, result.TLSInfo.CertInfo)
+}
+
+func TestWebSocketExecutor_ExecuteProxy(t *testing.T) {
+	server := httptest.NewServer(websocketHandler(t))
+	defer server.Close()
+
+	// An HTTP proxy tunneling CONNECT requests
+	var tunnels atomic.Int32
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT", http.StatusMethodNotAllowed)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer upstream.Close()
+
+		tunnels.Add(1)
+		w.WriteHeader(http.StatusOK)
+		client, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer client.Close()
+
+		go io.Copy(upstream, client)
+		io.Copy(client, upstream)
+	}))
+	defer proxyServer.Close()
+
+	host, port, err := net.SplitHostPort(strings.TrimPrefix(proxyServer.URL, "http://"))
+	require.NoError(t, err)
+	proxyPort, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	executor := NewWebSocketExecutor(zap.NewNop().Sugar())
+	result := executor.Execute(context.Background(), &Monitor{
+		Type:    "websocket",
+		Name:    "WebSocket Monitor",
+		Timeout: 2,
+		Config:  fmt.Sprintf(`{"url":%q,"headers":"{\"X-Token\":\"secret\"}","message":"ping","keyword":"ok"}`, websocketURL(server)),
+	}, &Proxy{Protocol: "http", Host: host, Port: proxyPort})
+
+	require.NotNil(t, result)
+	assert.Equal(t, shared.MonitorStatusUp, result.Status, result.Message)
+	assert.Equal(t, int32(1), tunnels.Load())
+}
@@ -0,0 +1,231 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor_flap"
+	"peekaping/src/modules/shared"
+	"sync"
+)
+
+// defaultFlapWindow is the number of heartbeats the status change rate is computed over
+// when the monitor does not configure its own window
+const defaultFlapWindow = 20
+
+// minFlapWindow keeps the window large enough for a meaningful change rate
+const minFlapWindow = 3
+
+type flapChange int
+
+const (
+	flapUnchanged flapChange = iota
+	flapStarted
+	flapStopped
+)
+
+// flapWindow holds the recent outcomes of a monitor and whether it is currently flapping.
+// Scheduled ticks, pushes and agent results of a monitor may be processed concurrently, mu is
+// held for the whole push and evaluation.
+type flapWindow struct {
+	mu       sync.Mutex
+	outcomes []shared.MonitorStatus // oldest first, only UP and DOWN
+	flapping bool
+}
+
+// push appends the outcome, keeping the last size ones, and returns the number of status
+// changes in the window
+func (w *flapWindow) push(outcome shared.MonitorStatus, size int) int {
+	w.outcomes = append(w.outcomes, outcome)
+	if len(w.outcomes) > size {
+		w.outcomes = w.outcomes[len(w.outcomes)-size:]
+	}
+	return w.changes()
+}
+
+func (w *flapWindow) changes() int {
+	changes := 0
+	for i := 1; i < len(w.outcomes); i++ {
+		if w.outcomes[i] != w.outcomes[i-1] {
+			changes++
+		}
+	}
+	return changes
+}
+
+// flapTracker keeps the flap window of every monitor in memory
+type flapTracker struct {
+	mu      sync.Mutex
+	windows map[string]*flapWindow
+}
+
+func newFlapTracker() *flapTracker {
+	return &flapTracker{
+		windows: make(map[string]*flapWindow),
+	}
+}
+
+func (t *flapTracker) get(monitorID string) (*flapWindow, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	w, ok := t.windows[monitorID]
+	return w, ok
+}
+
+func (t *flapTracker) set(monitorID string, w *flapWindow) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.windows[monitorID] = w
+}
+
+// setIfAbsent stores the window unless another one was stored meanwhile, and returns the stored one
+func (t *flapTracker) setIfAbsent(monitorID string, w *flapWindow) *flapWindow {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if existing, ok := t.windows[monitorID]; ok {
+		return existing
+	}
+	t.windows[monitorID] = w
+	return w
+}
+
+func (t *flapTracker) forget(monitorID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.windows, monitorID)
+}
+
+// flapOutcome reduces a status to the outcome counted for flap detection, pending and
+// maintenance beats are not counted
+func flapOutcome(status shared.MonitorStatus) (shared.MonitorStatus, bool) {
+	switch status {
+	case shared.MonitorStatusUp, shared.MonitorStatusDegraded:
+		return shared.MonitorStatusUp, true
+	case shared.MonitorStatusDown:
+		return shared.MonitorStatusDown, true
+	default:
+		return status, false
+	}
+}
+
+func flapWindowSize(m *Monitor) int {
+	if m.FlapWindow <= 0 {
+		return defaultFlapWindow
+	}
+	return max(m.FlapWindow, minFlapWindow)
+}
+
+// evaluateFlapping applies the status change rate to the flapping state of the window. A
+// monitor starts flapping once the rate reaches the threshold and stops when it falls below
+// half of it, so a rate hovering around the threshold does not toggle the state.
+func evaluateFlapping(w *flapWindow, changes, size, threshold int) flapChange {
+	rate := changes * 100 / (size - 1)
+
+	if !w.flapping && rate >= threshold {
+		w.flapping = true
+		return flapStarted
+	}
+	if w.flapping && rate < threshold/2 {
+		w.flapping = false
+		return flapStopped
+	}
+	return flapUnchanged
+}
+
+// loadFlapWindow rebuilds the window from the latest heartbeats and the open flapping period
+func (s *HealthCheckSupervisor) loadFlapWindow(ctx context.Context, m *Monitor, size int) (*flapWindow, error) {
+	w := &flapWindow{}
+
+	if s.flapSvc != nil {
+		open, err := s.flapSvc.FindOpenByMonitorID(ctx, m.ID)
+		if err != nil {
+			return nil, err
+		}
+		w.flapping = open != nil
+	}
+
+	if m.FlapThreshold > 0 {
+		beats, err := s.heartbeatService.FindByMonitorIDPaginated(ctx, m.ID, size, 0, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, beat := range beats {
+			if outcome, ok := flapOutcome(beat.Status); ok {
+				w.outcomes = append(w.outcomes, outcome)
+			}
+		}
+	}
+
+	return w, nil
+}
+
+// detectFlapping records the status in the flap window of the monitor and returns whether it
+// is flapping, whether that changed with this status and the status changes in the window
+func (s *HealthCheckSupervisor) detectFlapping(ctx context.Context, m *Monitor, status shared.MonitorStatus) (bool, flapChange, int) {
+	size := flapWindowSize(m)
+
+	w, ok := s.flaps.get(m.ID)
+	if !ok {
+		loaded, err := s.loadFlapWindow(ctx, m, size)
+		if err != nil {
+			s.logger.Errorf("Failed to load flap window for monitor %s: %v", m.ID, err)
+			return false, flapUnchanged, 0
+		}
+		w = s.flaps.setIfAbsent(m.ID, loaded)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Disabling detection ends a period that is still open
+	if m.FlapThreshold <= 0 {
+		if w.flapping {
+			w.flapping = false
+			return false, flapStopped, 0
+		}
+		return false, flapUnchanged, 0
+	}
+
+	outcome, counted := flapOutcome(status)
+	if !counted {
+		return w.flapping, flapUnchanged, w.changes()
+	}
+
+	changes := w.push(outcome, size)
+	return w.flapping, evaluateFlapping(w, changes, size, m.FlapThreshold), changes
+}
+
+// recordFlapping stores the flapping period and publishes the started or stopped event
+func (s *HealthCheckSupervisor) recordFlapping(ctx context.Context, m *Monitor, hb *heartbeat.Model, change flapChange, changes int) {
+	started := change == flapStarted
+
+	if s.flapSvc != nil {
+		var err error
+		if started {
+			_, err = s.flapSvc.Start(ctx, m.ID, hb.Time, changes)
+		} else {
+			err = s.flapSvc.End(ctx, m.ID, hb.Time)
+		}
+		if err != nil {
+			s.logger.Errorf("Failed to record flapping period for monitor %s: %v", m.ID, err)
+		}
+	}
+
+	if started {
+		s.logger.Infof("Monitor %s started flapping, %d status changes in the last %d checks", m.Name, changes, flapWindowSize(m))
+	} else {
+		s.logger.Infof("Monitor %s stopped flapping", m.Name)
+	}
+
+	s.eventBus.Publish(events.Event{
+		Type: events.MonitorFlapping,
+		Payload: &monitor_flap.FlappingEvent{
+			MonitorID:   m.ID,
+			MonitorName: m.Name,
+			Started:     started,
+			Transitions: changes,
+			Window:      flapWindowSize(m),
+			Heartbeat:   hb,
+		},
+	})
+}
@@ -0,0 +1,110 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestSupervisor_DetectFlapping(t *testing.T) {
+	s := &HealthCheckSupervisor{
+		heartbeatService: &countingHeartbeatService{latest: map[string]*heartbeat.Model{}},
+		flaps:            newFlapTracker(),
+		logger:           zap.NewNop().Sugar(),
+	}
+	m := &Monitor{ID: "m1", FlapThreshold: 50, FlapWindow: 5}
+	up, down := shared.MonitorStatusUp, shared.MonitorStatusDown
+
+	steps := []struct {
+		status   shared.MonitorStatus
+		flapping bool
+		change   flapChange
+	}{
+		{up, false, flapUnchanged},
+		{down, false, flapUnchanged},
+		// two changes out of four possible reach the 50% threshold
+		{up, true, flapStarted},
+		{shared.MonitorStatusPending, true, flapUnchanged},
+		{down, true, flapUnchanged},
+		{down, true, flapUnchanged},
+		{down, true, flapUnchanged},
+		// one change is still at half of the threshold
+		{down, true, flapUnchanged},
+		{down, false, flapStopped},
+		{down, false, flapUnchanged},
+	}
+
+	for i, step := range steps {
+		flapping, change, _ := s.detectFlapping(context.Background(), m, step.status)
+		assert.Equal(t, step.flapping, flapping, "step %d", i)
+		assert.Equal(t, step.change, change, "step %d", i)
+	}
+}
+
+func TestSupervisor_DetectFlappingDisabled(t *testing.T) {
+	s := &HealthCheckSupervisor{
+		flaps:  newFlapTracker(),
+		logger: zap.NewNop().Sugar(),
+	}
+	m := &Monitor{ID: "m1"}
+
+	flapping, change, _ := s.detectFlapping(context.Background(), m, shared.MonitorStatusDown)
+	assert.False(t, flapping)
+	assert.Equal(t, flapUnchanged, change)
+
+	// Turning detection off while flapping closes the period
+	s.flaps.set("m1", &flapWindow{flapping: true})
+	flapping, change, _ = s.detectFlapping(context.Background(), m, shared.MonitorStatusUp)
+	assert.False(t, flapping)
+	assert.Equal(t, flapStopped, change)
+}
+
+func TestSupervisor_DetectFlappingConcurrent(t *testing.T) {
+	s := &HealthCheckSupervisor{
+		heartbeatService: &countingHeartbeatService{latest: map[string]*heartbeat.Model{}},
+		flaps:            newFlapTracker(),
+		logger:           zap.NewNop().Sugar(),
+	}
+	m := &Monitor{ID: "m1", FlapThreshold: 50, FlapWindow: 10}
+
+	// Pushes and agent results of the same monitor arrive at the same time
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	started := 0
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := shared.MonitorStatusUp
+			if i%2 == 0 {
+				status = shared.MonitorStatusDown
+			}
+			_, change, _ := s.detectFlapping(context.Background(), m, status)
+			if change == flapStarted {
+				mu.Lock()
+				started++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	w, ok := s.flaps.get("m1")
+	assert.True(t, ok)
+	assert.Len(t, w.outcomes, 10)
+	assert.LessOrEqual(t, started, 1)
+}
+
+func TestFlapOutcome(t *testing.T) {
+	outcome, ok := flapOutcome(shared.MonitorStatusDegraded)
+	assert.True(t, ok)
+	assert.Equal(t, shared.MonitorStatusUp, outcome)
+
+	_, ok = flapOutcome(shared.MonitorStatusMaintenance)
+	assert.False(t, ok)
+}
@@ -3,6 +3,8 @@ package healthcheck
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"maps"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/healthcheck/executor"
 	"peekaping/src/modules/heartbeat"
@@ -18,6 +20,7 @@ func (s *HealthCheckSupervisor) isImportantForNotification(prevBeatStatus, currB
 	down := shared.MonitorStatusDown
 	pending := shared.MonitorStatusPending
 	maintenance := shared.MonitorStatusMaintenance
+	degraded := shared.MonitorStatusDegraded
 
 	// * ? -> ANY STATUS = important [isFirstBeat]
 	// UP -> PENDING = not important
@@ -34,8 +37,19 @@ func (s *HealthCheckSupervisor) isImportantForNotification(prevBeatStatus, currB
 	// * MAINTENANCE -> DOWN = important
 	// DOWN -> MAINTENANCE = not important
 	// UP -> MAINTENANCE = not important
-
-	return (prevBeatStatus == maintenance && currBeatStatus == down) ||
+	// * UP -> DEGRADED = important
+	// * PENDING -> DEGRADED = important
+	// * DOWN -> DEGRADED = important
+	// * MAINTENANCE -> DEGRADED = important
+	// * DEGRADED -> UP = important
+	// * DEGRADED -> DOWN = important
+	// DEGRADED -> PENDING = not important
+	// DEGRADED -> MAINTENANCE = not important
+
+	return (prevBeatStatus != degraded && currBeatStatus == degraded) ||
+		(prevBeatStatus == degraded && currBeatStatus == up) ||
+		(prevBeatStatus == degraded && currBeatStatus == down) ||
+		(prevBeatStatus == maintenance && currBeatStatus == down) ||
 		(prevBeatStatus == up && currBeatStatus == down) ||
 		(prevBeatStatus == down && currBeatStatus == up) ||
 		(prevBeatStatus == pending && currBeatStatus == down)
@@ -47,6 +61,7 @@ func (s *HealthCheckSupervisor) isImportantBeat(prevBeatStatus, currBeatStatus h
 	down := shared.MonitorStatusDown
 	pending := shared.MonitorStatusPending
 	maintenance := shared.MonitorStatusMaintenance
+	degraded := shared.MonitorStatusDegraded
 
 	// UP -> PENDING = not important
 	// * UP -> DOWN = important
@@ -62,8 +77,17 @@ func (s *HealthCheckSupervisor) isImportantBeat(prevBeatStatus, currBeatStatus h
 	// * MAINTENANCE -> DOWN = important
 	// * DOWN -> MAINTENANCE = important
 	// * UP -> MAINTENANCE = important
-
-	return (prevBeatStatus == down && currBeatStatus == maintenance) ||
+	// * ANY STATUS -> DEGRADED = important
+	// * DEGRADED -> UP = important
+	// * DEGRADED -> DOWN = important
+	// * DEGRADED -> MAINTENANCE = important
+	// DEGRADED -> PENDING = not important
+
+	return (prevBeatStatus != degraded && currBeatStatus == degraded) ||
+		(prevBeatStatus == degraded && currBeatStatus == up) ||
+		(prevBeatStatus == degraded && currBeatStatus == down) ||
+		(prevBeatStatus == degraded && currBeatStatus == maintenance) ||
+		(prevBeatStatus == down && currBeatStatus == maintenance) ||
 		(prevBeatStatus == up && currBeatStatus == maintenance) ||
 		(prevBeatStatus == maintenance && currBeatStatus == down) ||
 		(prevBeatStatus == maintenance && currBeatStatus == up) ||
@@ -72,19 +96,61 @@ func (s *HealthCheckSupervisor) isImportantBeat(prevBeatStatus, currBeatStatus h
 		(prevBeatStatus == pending && currBeatStatus == down)
 }
 
-func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m *Monitor, intervalUpdateCb func(newInterval time.Duration)) {
-	ping := int(result.EndTime.Sub(result.StartTime).Milliseconds())
+// responseTime returns the response time in milliseconds the check measured itself, such as
+// the perfdata of a command, and the time the check took otherwise
+func responseTime(result *executor.Result) int {
+	// Metadata reported by agents comes back from JSON as float64
+	switch value := result.Metadata[executor.MetadataResponseTimeMs].(type) {
+	case float64:
+		return int(value)
+	case int:
+		return value
+	}
+	return int(result.EndTime.Sub(result.StartTime).Milliseconds())
+}
 
+// processReceivedResult persists a result pushed to this node or reported by an agent.
+// With clustering the previous result may have landed on another node, so the cached
+// state is dropped and read again from the database
+func (s *HealthCheckSupervisor) processReceivedResult(result *executor.Result, m *Monitor, location string) {
+	if s.clusterService.Enabled() {
+		s.states.forget(m.ID)
+	}
+	s.postProcessHeartbeat(result, m, location, nil)
+}
+
+// postProcessHeartbeat persists a check result reported from location, an empty location
+// marks results that are not tied to a place such as push heartbeats
+func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m *Monitor, location string, intervalUpdateCb func(newInterval time.Duration)) {
 	ctx := context.Background()
 
-	// get the previous heartbeat
-	previousBeats, err := s.heartbeatService.FindByMonitorIDPaginated(ctx, m.ID, 1, 0, nil, false)
-	var previousBeat *heartbeat.Model = nil
+	// Combine with the latest results of the other locations checking this monitor
+	result, err := s.locations.apply(ctx, m, location, result, time.Now())
 	if err != nil {
-		s.logger.Errorf("Failed to get previous heartbeat for monitor %s: %v", m.ID, err)
+		s.logger.Errorf("Failed to combine location results for monitor %s: %v", m.ID, err)
 	}
-	if len(previousBeats) > 0 {
-		previousBeat = previousBeats[0]
+
+	// A DOWN parent explains the failure, record it without paging for the child
+	dependencyDown := false
+	if result.Status == shared.MonitorStatusDown {
+		parents, err := s.downParents(ctx, m.ID)
+		if err != nil {
+			s.logger.Errorf("Failed to check dependencies of monitor %s: %v", m.ID, err)
+		}
+		if len(parents) > 0 {
+			dependencyDown = true
+			suppressed := *result
+			suppressed.Message = fmt.Sprintf("Dependency down: %s (%s)", strings.Join(parents, ", "), result.Message)
+			result = &suppressed
+		}
+	}
+
+	ping := responseTime(result)
+
+	// the state left by the previous heartbeat, cached so steady-state ticks skip the read
+	previousBeat, err := s.previousState(ctx, m.ID)
+	if err != nil {
+		s.logger.Errorf("Failed to get previous heartbeat for monitor %s: %v", m.ID, err)
 	}
 
 	s.logger.Debugf("previousBeat %t", previousBeat != nil)
@@ -103,6 +169,8 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		Time:      result.StartTime,
 		EndTime:   result.EndTime,
 		Notified:  false,
+		Location:  location,
+		Metadata:  result.Metadata,
 	}
 
 	if !isFirstBeat {
@@ -126,6 +194,14 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		hb.Retries = 0
 	}
 
+	// A successful but slow check becomes DEGRADED, executors reporting DEGRADED explain it themselves
+	var slowChecks int
+	statusBeforeLatency := hb.Status
+	hb.Status, slowChecks = degradedStatus(m, previousBeat, hb.Status, ping)
+	if statusBeforeLatency != shared.MonitorStatusDegraded && hb.Status == shared.MonitorStatusDegraded {
+		hb.Msg = fmt.Sprintf("%s (response time %d ms exceeds %d ms)", hb.Msg, ping, m.DegradedThreshold)
+	}
+
 	s.logger.Debugf("isFirstBeat for: %s %t", m.Name, isFirstBeat)
 	s.logger.Debugf("checking if important for: %s", m.Name)
 	isImportant := isFirstBeat || s.isImportantBeat(previousBeat.Status, hb.Status)
@@ -165,7 +241,49 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		}
 	}
 
-	if result.Status == shared.MonitorStatusUp {
+	// An outage hidden by a DOWN parent is announced once the parents recovered, and its
+	// recovery is not announced if the outage never was
+	suppressed := !isFirstBeat && previousBeat.SuppressedByDependency
+	switch {
+	case dependencyDown:
+		if shouldNotify {
+			s.logger.Debugf("not sending notification %s, a parent monitor is down", m.Name)
+			shouldNotify = false
+			hb.Notified = false
+			suppressed = true
+		}
+	case suppressed && hb.Status == shared.MonitorStatusDown:
+		s.logger.Debugf("sending notification %s, the parent monitors recovered", m.Name)
+		shouldNotify = true
+		hb.Notified = true
+		hb.DownCount = 0
+		suppressed = false
+	case suppressed && hb.Status != shared.MonitorStatusPending:
+		if shouldNotify && hb.Status == shared.MonitorStatusUp {
+			s.logger.Debugf("not sending notification %s, the outage was not announced", m.Name)
+			shouldNotify = false
+			hb.Notified = false
+		}
+		suppressed = false
+	}
+
+	// A flapping monitor sends one started/stopped notification instead of one per transition
+	flapping, flapChange, flapChanges := s.detectFlapping(ctx, m, hb.Status)
+	if flapping {
+		metadata := make(shared.HeartBeatMetadata, len(hb.Metadata)+1)
+		maps.Copy(metadata, hb.Metadata)
+		metadata["flapping"] = true
+		hb.Metadata = metadata
+	}
+	if flapping && shouldNotify {
+		s.logger.Debugf("not sending notification %s, the monitor is flapping", m.Name)
+		shouldNotify = false
+		hb.Notified = false
+	}
+
+	if hb.Status == shared.MonitorStatusDegraded {
+		s.logger.Debugf("%s degraded response %d ms | interval %d seconds | type %s", m.Name, ping, m.Interval, m.Type)
+	} else if result.Status == shared.MonitorStatusUp {
 		s.logger.Debugf("%s successful response %d ms | interval %d seconds | type %s", m.Name, ping, m.Interval, m.Type)
 	} else if result.Status == shared.MonitorStatusPending {
 		s.logger.Debugf("%s pending response %d ms | interval %d seconds | type %s", m.Name, ping, m.Interval, m.Type)
@@ -175,8 +293,8 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		s.logger.Debugf("%s maintenance response %d ms | interval %d seconds | type %s", m.Name, ping, m.Interval, m.Type)
 	}
 
-	// Update TLS info and check certificate expiry for HTTPS monitors
-	if result.TLSInfo != nil && strings.HasPrefix(strings.ToLower(m.Type), "http") {
+	// Update TLS info and check certificate expiry for monitors speaking TLS
+	if result.TLSInfo != nil && tracksCertificate(m.Type) {
 		// Update TLS info (this handles certificate change detection and notification history cleanup)
 		if err := s.certificateService.UpdateTLSInfo(ctx, m.ID, result.TLSInfo); err != nil {
 			s.logger.Errorf("Failed to update TLS info for monitor %s: %v", m.Name, err)
@@ -185,12 +303,12 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		// Check if certificate expiry checking is enabled in monitor configuration
 		shouldCheckCertExpiry := false
 		if m.Config != "" {
-			// Parse HTTP configuration to check if certificate expiry checking is enabled
+			// Parse the configuration to check if certificate expiry checking is enabled
 			var httpConfig struct {
 				CheckCertExpiry bool `json:"check_cert_expiry"`
 			}
 			if err := json.Unmarshal([]byte(m.Config), &httpConfig); err != nil {
-				s.logger.Errorf("Failed to parse HTTP config for monitor %s: %v", m.Name, err)
+				s.logger.Errorf("Failed to parse config for monitor %s: %v", m.Name, err)
 			} else {
 				shouldCheckCertExpiry = httpConfig.CheckCertExpiry
 			}
@@ -206,12 +324,37 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 		}
 	}
 
+	// Notify about the domain registration running out at the configured thresholds
+	if result.DomainInfo != nil {
+		var domainConfig struct {
+			NotifyDays []int `json:"notify_days"`
+		}
+		if err := json.Unmarshal([]byte(m.Config), &domainConfig); err != nil {
+			s.logger.Errorf("Failed to parse config for monitor %s: %v", m.Name, err)
+		}
+		if err := s.domainService.CheckDomainExpiry(ctx, result.DomainInfo, m.ID, m.Name, domainConfig.NotifyDays); err != nil {
+			s.logger.Errorf("Failed to check domain expiry for monitor %s: %v", m.Name, err)
+		}
+	}
+
 	dbHb, err := s.heartbeatService.Create(ctx, hb)
 	if err != nil {
 		s.logger.Errorf("Failed to create heartbeat", err.Error())
+		// The cached state may no longer match the database, reload it on the next beat
+		s.states.forget(m.ID)
 		return
 	}
 
+	if s.clusterService.Owns(m.ID) {
+		s.states.set(m.ID, &monitorState{
+			Status:                 dbHb.Status,
+			Retries:                dbHb.Retries,
+			DownCount:              dbHb.DownCount,
+			SlowChecks:             slowChecks,
+			SuppressedByDependency: suppressed,
+		})
+	}
+
 	if isFirstBeat || previousBeat.Status != hb.Status {
 		s.eventBus.Publish(events.Event{
 			Type:    events.MonitorStatusChanged,
@@ -225,16 +368,21 @@ func (s *HealthCheckSupervisor) postProcessHeartbeat(result *executor.Result, m
 			Payload: dbHb,
 		})
 	}
+
+	if flapChange != flapUnchanged {
+		s.recordFlapping(ctx, m, dbHb, flapChange, flapChanges)
+	}
 }
 
-// handleMonitorTick processes a single monitor tick in its own goroutine.
+// handleMonitorTick processes a single monitor tick and returns the recorded result,
+// nil when the executor produced none.
 func (s *HealthCheckSupervisor) handleMonitorTick(
 	ctx context.Context,
 	m *Monitor,
 	exec executor.Executor,
 	proxyModel *proxy.Model,
 	intervalUpdateCb func(newInterval time.Duration),
-) {
+) *executor.Result {
 	// Check if monitor is under maintenance
 	isUnderMaintenance, err := s.isUnderMaintenance(ctx, m.ID)
 	s.logger.Debugf("isUnderMaintenance for %s: %t", m.Name, isUnderMaintenance)
@@ -250,8 +398,8 @@ func (s *HealthCheckSupervisor) handleMonitorTick(
 			StartTime: time.Now(),
 			EndTime:   time.Now(),
 		}
-		s.postProcessHeartbeat(result, m, intervalUpdateCb)
-		return
+		s.postProcessHeartbeat(result, m, s.location, intervalUpdateCb)
+		return result
 	}
 
 	callCtx, cCancel := context.WithTimeout(
@@ -263,8 +411,39 @@ func (s *HealthCheckSupervisor) handleMonitorTick(
 	// Execute the health check
 	result := exec.Execute(callCtx, m, proxyModel)
 	if result == nil {
-		return
+		return nil
+	}
+
+	s.postProcessHeartbeat(result, m, s.location, intervalUpdateCb)
+	return result
+}
+
+// degradedStatus turns an UP status into DEGRADED once the response time exceeded the monitor's
+// threshold for the configured number of consecutive checks, and returns the updated slow check count
+func degradedStatus(m *Monitor, previous *monitorState, status shared.MonitorStatus, ping int) (shared.MonitorStatus, int) {
+	if m.DegradedThreshold <= 0 || status != shared.MonitorStatusUp || ping <= m.DegradedThreshold {
+		return status, 0
 	}
 
-	s.postProcessHeartbeat(result, m, intervalUpdateCb)
+	slowChecks := 1
+	if previous != nil {
+		slowChecks = previous.SlowChecks + 1
+		// The count is not persisted, a monitor reloaded while DEGRADED stays DEGRADED
+		if previous.Status == shared.MonitorStatusDegraded {
+			return shared.MonitorStatusDegraded, slowChecks
+		}
+	}
+
+	if slowChecks >= max(m.DegradedBreaches, 1) {
+		return shared.MonitorStatusDegraded, slowChecks
+	}
+	return status, slowChecks
+}
+
+// tracksCertificate reports whether the certificates seen by monitors of this type are
+// stored and checked for expiry
+func tracksCertificate(monitorType string) bool {
+	monitorType = strings.ToLower(monitorType)
+	return strings.HasPrefix(monitorType, "http") || monitorType == "mail" || monitorType == "tls" ||
+		monitorType == "websocket" || monitorType == "ldap"
 }
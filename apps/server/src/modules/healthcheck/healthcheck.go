@@ -5,14 +5,21 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"peekaping/src/config"
 	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/cluster"
+	"peekaping/src/modules/domain_expiry"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/healthcheck/executor"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/maintenance"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_dependency"
+	"peekaping/src/modules/monitor_flap"
+	"peekaping/src/modules/monitor_location"
 	"peekaping/src/modules/proxy"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
@@ -22,6 +29,8 @@ type HealthCheckSupervisor struct {
 	mu                 sync.RWMutex
 	active             map[string]*task
 	monitorSvc         monitor.Service
+	dependencySvc      monitor_dependency.Service
+	flapSvc            monitor_flap.Service
 	maintenanceSvc     maintenance.Service
 	execRegistry       *executor.ExecutorRegistry
 	heartbeatService   heartbeat.Service
@@ -29,13 +38,23 @@ type HealthCheckSupervisor struct {
 	logger             *zap.SugaredLogger
 	proxyService       proxy.Service
 	certificateService certificate.Service
-	maxJitterSeconds   int64 // configurable jitter for testing
+	domainService      domain_expiry.Service
+	clusterService     cluster.Service
+	location           string            // location reported for checks run by this server
+	locations          *locationTracker  // latest result per location for the down quorum
+	pool               *executionPool    // bounded workers running monitor ticks
+	states             *stateManager     // last state per monitor, replaces the previous heartbeat read
+	maintenances       *maintenanceCache // maintenances per monitor, invalidated on maintenance changes
+	flaps              *flapTracker      // recent outcomes per monitor for flap detection
+	maxJitterSeconds   int64             // configurable jitter for testing
 }
 
 type task struct {
 	cancel         context.CancelFunc
 	done           chan struct{}
 	intervalUpdate chan time.Duration
+	busy           atomic.Bool // a tick is queued or running
+	updatedAt      time.Time   // UpdatedAt of the monitor settings the task runs with
 }
 
 func NewHealthCheck(
@@ -47,10 +66,21 @@ func NewHealthCheck(
 	logger *zap.SugaredLogger,
 	proxyService proxy.Service,
 	certificateService certificate.Service,
+	domainService domain_expiry.Service,
+	clusterService cluster.Service,
+	dependencyService monitor_dependency.Service,
+	flapService monitor_flap.Service,
+	locationService monitor_location.Service,
+	cfg *config.Config,
 ) *HealthCheckSupervisor {
+	pool := newExecutionPool(cfg.HealthCheckWorkers, cfg.HealthCheckQueueSize, cfg.HealthCheckPerHostLimit)
+	pool.start()
+
 	return &HealthCheckSupervisor{
 		active:             make(map[string]*task),
 		monitorSvc:         monitorService,
+		dependencySvc:      dependencyService,
+		flapSvc:            flapService,
 		maintenanceSvc:     maintenanceService,
 		execRegistry:       execRegistry,
 		heartbeatService:   heartbeatService,
@@ -58,6 +88,14 @@ func NewHealthCheck(
 		logger:             logger.With("service", "[healthcheck]"),
 		proxyService:       proxyService,
 		certificateService: certificateService,
+		domainService:      domainService,
+		clusterService:     clusterService,
+		location:           cfg.ServerLocation,
+		locations:          newLocationTracker(cfg.AgentDownQuorum, locationStore(clusterService, locationService)),
+		pool:               pool,
+		states:             newStateManager(),
+		maintenances:       newMaintenanceCache(),
+		flaps:              newFlapTracker(),
 		maxJitterSeconds:   20, // default production jitter
 	}
 }
@@ -72,11 +110,22 @@ func NewHealthCheckWithJitter(
 	logger *zap.SugaredLogger,
 	proxyService proxy.Service,
 	certificateService certificate.Service,
+	domainService domain_expiry.Service,
+	clusterService cluster.Service,
+	dependencyService monitor_dependency.Service,
+	flapService monitor_flap.Service,
+	locationService monitor_location.Service,
+	cfg *config.Config,
 	maxJitterSeconds int64,
 ) *HealthCheckSupervisor {
+	pool := newExecutionPool(cfg.HealthCheckWorkers, cfg.HealthCheckQueueSize, cfg.HealthCheckPerHostLimit)
+	pool.start()
+
 	return &HealthCheckSupervisor{
 		active:             make(map[string]*task),
 		monitorSvc:         monitorService,
+		dependencySvc:      dependencyService,
+		flapSvc:            flapService,
 		maintenanceSvc:     maintenanceService,
 		execRegistry:       execRegistry,
 		heartbeatService:   heartbeatService,
@@ -84,12 +133,35 @@ func NewHealthCheckWithJitter(
 		logger:             logger.With("service", "[healthcheck]"),
 		proxyService:       proxyService,
 		certificateService: certificateService,
+		domainService:      domainService,
+		clusterService:     clusterService,
+		location:           cfg.ServerLocation,
+		locations:          newLocationTracker(cfg.AgentDownQuorum, locationStore(clusterService, locationService)),
+		pool:               pool,
+		states:             newStateManager(),
+		maintenances:       newMaintenanceCache(),
+		flaps:              newFlapTracker(),
 		maxJitterSeconds:   maxJitterSeconds,
 	}
 }
 
+// locationStore returns the store shared by the replicas for the down quorum, a single node
+// receives every location result and keeps them in memory
+func locationStore(clusterService cluster.Service, locationService monitor_location.Service) monitor_location.Service {
+	if !clusterService.Enabled() {
+		return nil
+	}
+	return locationService
+}
+
 func (s *HealthCheckSupervisor) StartAll(ctx context.Context) error {
 	s.logger.Info("Start health check module")
+
+	// Join the cluster before scheduling so only monitors in owned shards are started
+	s.clusterService.Start(ctx, func() {
+		s.reconcile(ctx)
+	})
+
 	// Get all active monitors
 	monitors, err := s.monitorSvc.FindActive(ctx)
 	if err != nil {
@@ -97,6 +169,15 @@ func (s *HealthCheckSupervisor) StartAll(ctx context.Context) error {
 	}
 	s.logger.Infof("Found active monitors: %d", len(monitors))
 
+	// Load the last state of owned monitors up front so their ticks never read it back
+	owned := make([]*Monitor, 0, len(monitors))
+	for _, m := range monitors {
+		if s.clusterService.Owns(m.ID) {
+			owned = append(owned, m)
+		}
+	}
+	s.seedStates(ctx, owned)
+
 	// Start monitoring for each active monitor
 	for _, m := range monitors {
 		if err := s.StartMonitor(ctx, m, true); err != nil {
@@ -112,6 +193,13 @@ func (s *HealthCheckSupervisor) StartMonitor(
 	m *Monitor,
 	withJitter bool,
 ) error {
+	// Another node holds the lease for this monitor, make sure it is not running here
+	if !s.clusterService.Owns(m.ID) {
+		s.logger.Debugf("Monitor %s is owned by another node, skipping", m.ID)
+		s.DeleteMonitor(m.ID)
+		return nil
+	}
+
 	s.logger.Infof("StartMonitor health check module for monitor: %s", m.ID)
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -123,8 +211,13 @@ func (s *HealthCheckSupervisor) StartMonitor(
 	}
 
 	ctx, cancel := context.WithCancel(ctx)
-	done := make(chan struct{})
-	intervalUpdate := make(chan time.Duration, 1)
+	t := &task{
+		cancel:         cancel,
+		done:           make(chan struct{}),
+		intervalUpdate: make(chan time.Duration, 1),
+		updatedAt:      m.UpdatedAt,
+	}
+	host := targetHost(m)
 
 	// Fetch proxy once here
 	var proxyModel *proxy.Model = nil
@@ -138,7 +231,7 @@ func (s *HealthCheckSupervisor) StartMonitor(
 	}
 
 	go func() {
-		defer close(done)
+		defer close(t.done)
 		interval := time.Duration(m.Interval) * time.Second
 
 		// Add random jitter before starting the loop
@@ -155,18 +248,23 @@ func (s *HealthCheckSupervisor) StartMonitor(
 			return
 		}
 
+		intervalUpdateCb := func(newInterval time.Duration) {
+			// Keep only the latest interval, the loop may not have consumed the previous one yet
+			select {
+			case <-t.intervalUpdate:
+			default:
+			}
+			t.intervalUpdate <- newInterval
+		}
+
 		// Run once immediately
-		go s.handleMonitorTick(ctx, m, executor, proxyModel, func(newInterval time.Duration) {
-			intervalUpdate <- newInterval
-		})
+		s.scheduleTick(ctx, t, host, m, executor, proxyModel, intervalUpdateCb)
 
 		for {
 			select {
 			case <-time.After(interval):
-				go s.handleMonitorTick(ctx, m, executor, proxyModel, func(newInterval time.Duration) {
-					intervalUpdate <- newInterval
-				})
-			case newInterval := <-intervalUpdate:
+				s.scheduleTick(ctx, t, host, m, executor, proxyModel, intervalUpdateCb)
+			case newInterval := <-t.intervalUpdate:
 				interval = newInterval
 			case <-ctx.Done():
 				return
@@ -174,10 +272,49 @@ func (s *HealthCheckSupervisor) StartMonitor(
 		}
 	}()
 
-	s.active[m.ID] = &task{cancel: cancel, done: done, intervalUpdate: intervalUpdate}
+	s.active[m.ID] = t
 	return nil
 }
 
+// scheduleTick queues a tick on the execution pool. The tick is skipped while the previous
+// one of the monitor is still queued or running, so slow targets never stack checks.
+func (s *HealthCheckSupervisor) scheduleTick(
+	ctx context.Context,
+	t *task,
+	host string,
+	m *Monitor,
+	exec executor.Executor,
+	proxyModel *proxy.Model,
+	intervalUpdateCb func(newInterval time.Duration),
+) {
+	if !t.busy.CompareAndSwap(false, true) {
+		s.pool.recordSkipped()
+		s.logger.Debugf("Previous check of %s is still running, skipping tick", m.Name)
+		return
+	}
+
+	submitted := s.pool.submit(&job{
+		host: host,
+		run: func() {
+			defer t.busy.Store(false)
+			// The monitor was stopped or restarted while the tick waited in the queue
+			if ctx.Err() != nil {
+				return
+			}
+			s.handleMonitorTick(ctx, m, exec, proxyModel, intervalUpdateCb)
+		},
+	})
+	if !submitted {
+		t.busy.Store(false)
+		s.logger.Warnf("Execution queue is full, dropping tick of %s", m.Name)
+	}
+}
+
+// Metrics reports the load of the execution pool
+func (s *HealthCheckSupervisor) Metrics() PoolMetrics {
+	return s.pool.metrics()
+}
+
 func (s *HealthCheckSupervisor) DeleteMonitor(monitorId string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -188,19 +325,101 @@ func (s *HealthCheckSupervisor) DeleteMonitor(monitorId string) {
 	}
 }
 
+// ForgetMonitor drops the per-location results and cached state of a deleted monitor
+func (s *HealthCheckSupervisor) ForgetMonitor(monitorId string) {
+	s.locations.forget(monitorId)
+	s.states.forget(monitorId)
+	s.maintenances.forget(monitorId)
+	s.flaps.forget(monitorId)
+}
+
+// InvalidateState drops the cached state of a monitor so it is reloaded from the database,
+// used when heartbeats may have changed outside of this supervisor
+func (s *HealthCheckSupervisor) InvalidateState(monitorId string) {
+	s.states.forget(monitorId)
+	s.flaps.forget(monitorId)
+}
+
+// InvalidateMaintenances drops every cached maintenance after a maintenance changed
+func (s *HealthCheckSupervisor) InvalidateMaintenances() {
+	s.maintenances.invalidate()
+}
+
 func (s *HealthCheckSupervisor) Shutdown() {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 	for id, t := range s.active {
 		t.cancel()
 		<-t.done
 		delete(s.active, id)
 	}
+	s.mu.Unlock()
+
+	s.pool.shutdown()
+	s.clusterService.Stop()
+}
+
+// reconcile brings the monitors running on this node in line with the database after every
+// lease renewal. Monitor events only reach the node that handled the request, so monitors
+// created, edited, paused or deleted through another node are picked up here: owned monitors
+// that are missing are started, edited ones restarted and the ones that are paused, deleted or
+// moved to another node stopped.
+func (s *HealthCheckSupervisor) reconcile(ctx context.Context) {
+	monitors, err := s.monitorSvc.FindActive(ctx)
+	if err != nil {
+		s.logger.Errorf("Failed to get active monitors for reconcile: %v", err)
+		return
+	}
+
+	owned := make(map[string]bool, len(monitors))
+	started, restarted := 0, 0
+	for _, m := range monitors {
+		if !s.clusterService.Owns(m.ID) {
+			continue
+		}
+		owned[m.ID] = true
+
+		s.mu.RLock()
+		t, running := s.active[m.ID]
+		s.mu.RUnlock()
+
+		switch {
+		case !running:
+			// Another node may have recorded heartbeats while it owned the monitor
+			s.states.forget(m.ID)
+			started++
+		case !t.updatedAt.Equal(m.UpdatedAt):
+			restarted++
+		default:
+			continue
+		}
+		if err := s.StartMonitor(ctx, m, true); err != nil {
+			s.logger.Errorf("Failed to start monitor %s after reconcile: %v", m.ID, err)
+		}
+	}
+
+	s.mu.RLock()
+	var released []string
+	for id := range s.active {
+		if !owned[id] {
+			released = append(released, id)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, id := range released {
+		s.DeleteMonitor(id)
+		s.ForgetMonitor(id)
+	}
+
+	if started > 0 || restarted > 0 || len(released) > 0 {
+		s.logger.Infof("Reconciled monitors: %d running on this node, %d started, %d restarted, %d stopped",
+			len(owned), started, restarted, len(released))
+	}
 }
 
 // isUnderMaintenance checks if a monitor is under maintenance
 func (s *HealthCheckSupervisor) isUnderMaintenance(ctx context.Context, monitorID string) (bool, error) {
-	maintenances, err := s.maintenanceSvc.GetMaintenancesByMonitorID(ctx, monitorID)
+	maintenances, err := s.maintenances.get(ctx, monitorID, time.Now(), s.maintenanceSvc.GetMaintenancesByMonitorID)
 	if err != nil {
 		return false, err
 	}
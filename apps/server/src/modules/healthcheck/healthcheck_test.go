@@ -0,0 +1,93 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/healthcheck/executor"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// newNodeTestSupervisor returns the supervisor of one cluster node owning the given monitors,
+// the monitors use a type without executor so their tasks never run a check
+func newNodeTestSupervisor(monitors *fakeMonitorService, owned map[string]bool) *HealthCheckSupervisor {
+	logger := zap.NewNop().Sugar()
+	return &HealthCheckSupervisor{
+		active:         make(map[string]*task),
+		monitorSvc:     monitors,
+		execRegistry:   executor.NewExecutorRegistry(logger, nil, nil),
+		clusterService: &fakeClusterService{owned: owned},
+		states:         newStateManager(),
+		maintenances:   newMaintenanceCache(),
+		locations:      newLocationTracker(1, nil),
+		flaps:          newFlapTracker(),
+		logger:         logger,
+	}
+}
+
+func (s *HealthCheckSupervisor) runningTask(monitorID string) *task {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active[monitorID]
+}
+
+func TestSupervisor_ReconcileChangesFromOtherNode(t *testing.T) {
+	ctx := context.Background()
+	monitors := &fakeMonitorService{monitors: map[string]*Monitor{}}
+	nodeA := newNodeTestSupervisor(monitors, map[string]bool{"a": true})
+	nodeB := newNodeTestSupervisor(monitors, map[string]bool{"b": true})
+	t.Cleanup(func() {
+		nodeA.DeleteMonitor("b")
+		nodeB.DeleteMonitor("b")
+	})
+
+	// Created through node A in a shard owned by node B
+	created := time.Now().UTC()
+	m := &Monitor{ID: "b", Type: "unknown", Interval: 60, Active: true, UpdatedAt: created}
+	monitors.monitors["b"] = m
+	assert.NoError(t, nodeA.StartMonitor(ctx, m, false))
+	assert.Nil(t, nodeA.runningTask("b"))
+
+	nodeB.reconcile(ctx)
+	first := nodeB.runningTask("b")
+	if assert.NotNil(t, first) {
+		assert.Equal(t, created, first.updatedAt)
+	}
+
+	// Nothing changed, the task keeps running
+	nodeB.reconcile(ctx)
+	assert.Same(t, first, nodeB.runningTask("b"))
+
+	// Edited through node A
+	edited := *m
+	edited.Interval = 30
+	edited.UpdatedAt = created.Add(time.Minute)
+	monitors.monitors["b"] = &edited
+	nodeB.reconcile(ctx)
+	restarted := nodeB.runningTask("b")
+	if assert.NotNil(t, restarted) {
+		assert.NotSame(t, first, restarted)
+		assert.Equal(t, edited.UpdatedAt, restarted.updatedAt)
+	}
+
+	// Paused through node A
+	paused := edited
+	paused.Active = false
+	monitors.monitors["b"] = &paused
+	nodeB.reconcile(ctx)
+	assert.Nil(t, nodeB.runningTask("b"))
+
+	// Resumed, then deleted through node A
+	monitors.monitors["b"] = &edited
+	nodeB.reconcile(ctx)
+	assert.NotNil(t, nodeB.runningTask("b"))
+	delete(monitors.monitors, "b")
+	nodeB.reconcile(ctx)
+	assert.Nil(t, nodeB.runningTask("b"))
+
+	// Node A never ran it
+	nodeA.reconcile(ctx)
+	assert.Nil(t, nodeA.runningTask("b"))
+}
@@ -27,6 +27,9 @@ func (l *EventListener) Start(eventBus *events.EventBus) {
 	eventBus.Subscribe(events.MonitorDeleted, l.handleMonitorDeleted)
 	eventBus.Subscribe(events.ProxyUpdated, l.handleProxyUpdated)
 	eventBus.Subscribe(events.ProxyDeleted, l.handleProxyDeleted)
+	eventBus.Subscribe(events.MaintenanceCreated, l.handleMaintenanceChanged)
+	eventBus.Subscribe(events.MaintenanceUpdated, l.handleMaintenanceChanged)
+	eventBus.Subscribe(events.MaintenanceDeleted, l.handleMaintenanceChanged)
 }
 
 // handleMonitorCreated starts health check polling for newly created monitors
@@ -53,6 +56,9 @@ func (l *EventListener) handleMonitorUpdated(event events.Event) {
 		return
 	}
 
+	// Updates may come with deleted heartbeats (e.g. a reset), reload the last state
+	l.supervisor.InvalidateState(monitor.ID)
+
 	if monitor.Active {
 		ctx := context.Background()
 		if err := l.supervisor.StartMonitor(ctx, monitor, false); err != nil {
@@ -72,6 +78,7 @@ func (l *EventListener) handleMonitorDeleted(event events.Event) {
 	}
 
 	l.supervisor.DeleteMonitor(monitorID)
+	l.supervisor.ForgetMonitor(monitorID)
 }
 
 func (l *EventListener) handleProxyUpdated(event events.Event) {
@@ -111,3 +118,8 @@ func (l *EventListener) handleProxyDeleted(event events.Event) {
 		}
 	}
 }
+
+// handleMaintenanceChanged drops cached maintenances so the next tick sees the new schedule
+func (l *EventListener) handleMaintenanceChanged(event events.Event) {
+	l.supervisor.InvalidateMaintenances()
+}
@@ -0,0 +1,171 @@
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/monitor_location"
+	"peekaping/src/modules/shared"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// locationState is the last result a single location reported for a monitor
+type locationState struct {
+	status     shared.MonitorStatus
+	message    string
+	reportedAt time.Time
+}
+
+// locationTracker keeps the latest result of every location checking a monitor,
+// so a monitor is only marked DOWN once a quorum of locations agrees. With a store the
+// results are kept in the database, agents report to whichever replica the load balancer
+// picks and each replica alone would only see some of the locations.
+type locationTracker struct {
+	mu     sync.Mutex
+	quorum int
+	store  monitor_location.Service
+	states map[string]map[string]*locationState
+}
+
+func newLocationTracker(quorum int, store monitor_location.Service) *locationTracker {
+	if quorum < 1 {
+		quorum = 1
+	}
+	return &locationTracker{
+		quorum: quorum,
+		store:  store,
+		states: make(map[string]map[string]*locationState),
+	}
+}
+
+// apply records the result reported from location and returns the result that should be persisted.
+// Locations that did not report for three intervals are no longer counted, and a monitor checked
+// from a single location behaves exactly as without agents. When the store fails the result is
+// returned unchanged together with the error.
+func (t *locationTracker) apply(ctx context.Context, m *Monitor, location string, result *executor.Result, now time.Time) (*executor.Result, error) {
+	if location == "" || (result.Status != shared.MonitorStatusUp && result.Status != shared.MonitorStatusDown) {
+		return result, nil
+	}
+
+	staleAfter := 3 * time.Duration(max(m.Interval, m.RetryInterval, 1)) * time.Second
+	current := &locationState{
+		status:     result.Status,
+		message:    result.Message,
+		reportedAt: now,
+	}
+
+	var states map[string]*locationState
+	if t.store != nil {
+		var err error
+		states, err = t.record(ctx, m.ID, location, current, now.Add(-staleAfter))
+		if err != nil {
+			return result, err
+		}
+	} else {
+		states = t.recordInMemory(m.ID, location, current, now.Add(-staleAfter))
+	}
+
+	return t.aggregate(location, result, states), nil
+}
+
+// record stores the result in the database and returns the fresh results of all locations
+func (t *locationTracker) record(ctx context.Context, monitorID, location string, current *locationState, since time.Time) (map[string]*locationState, error) {
+	err := t.store.Report(ctx, &monitor_location.Model{
+		MonitorID:  monitorID,
+		Location:   location,
+		Status:     current.status,
+		Message:    current.message,
+		ReportedAt: current.reportedAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store location result: %w", err)
+	}
+
+	stored, err := t.store.FindByMonitorID(ctx, monitorID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load location results: %w", err)
+	}
+
+	states := make(map[string]*locationState, len(stored))
+	for _, s := range stored {
+		states[s.Location] = &locationState{
+			status:     s.Status,
+			message:    s.Message,
+			reportedAt: s.ReportedAt,
+		}
+	}
+	// The result just reported counts even if the clocks of the replicas disagree
+	states[location] = current
+	return states, nil
+}
+
+// recordInMemory keeps the result on this replica and returns the fresh results of all locations
+func (t *locationTracker) recordInMemory(monitorID, location string, current *locationState, since time.Time) map[string]*locationState {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	states, ok := t.states[monitorID]
+	if !ok {
+		states = make(map[string]*locationState)
+		t.states[monitorID] = states
+	}
+	states[location] = current
+
+	fresh := make(map[string]*locationState, len(states))
+	for loc, state := range states {
+		if state.reportedAt.Before(since) {
+			delete(states, loc)
+			continue
+		}
+		fresh[loc] = state
+	}
+	return fresh
+}
+
+// aggregate combines the result reported from location with the results of the other locations
+func (t *locationTracker) aggregate(location string, result *executor.Result, states map[string]*locationState) *executor.Result {
+	reporting := len(states)
+	var down []string
+	for loc, state := range states {
+		if state.status == shared.MonitorStatusDown {
+			down = append(down, loc)
+		}
+	}
+
+	if reporting <= 1 {
+		return result
+	}
+	sort.Strings(down)
+
+	required := min(t.quorum, reporting)
+	aggregated := *result
+
+	if len(down) >= required {
+		aggregated.Status = shared.MonitorStatusDown
+		if result.Status == shared.MonitorStatusDown {
+			aggregated.Message = fmt.Sprintf("[%s] %s (down in %d of %d locations: %s)",
+				location, result.Message, len(down), reporting, strings.Join(down, ", "))
+		} else {
+			// This location is fine, but enough other locations still see the monitor down
+			aggregated.Message = fmt.Sprintf("[%s] %s (down in %d of %d locations: %s)",
+				down[0], states[down[0]].message, len(down), reporting, strings.Join(down, ", "))
+		}
+	} else if result.Status == shared.MonitorStatusDown {
+		aggregated.Status = shared.MonitorStatusPending
+		aggregated.Message = fmt.Sprintf("[%s] %s (down in %d of %d locations, quorum is %d)",
+			location, result.Message, len(down), reporting, required)
+	}
+
+	return &aggregated
+}
+
+// forget drops everything recorded in memory for the monitor, the stored results are removed
+// when the monitor is deleted
+func (t *locationTracker) forget(monitorID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.states, monitorID)
+}
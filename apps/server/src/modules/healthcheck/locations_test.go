@@ -0,0 +1,166 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/modules/monitor_location"
+	"peekaping/src/modules/shared"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLocationStore keeps the reported results in memory like the shared table
+type fakeLocationStore struct {
+	mu      sync.Mutex
+	results map[string]map[string]monitor_location.Model
+}
+
+func newFakeLocationStore() *fakeLocationStore {
+	return &fakeLocationStore{results: make(map[string]map[string]monitor_location.Model)}
+}
+
+func (f *fakeLocationStore) Report(ctx context.Context, model *monitor_location.Model) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.results[model.MonitorID] == nil {
+		f.results[model.MonitorID] = make(map[string]monitor_location.Model)
+	}
+	f.results[model.MonitorID][model.Location] = *model
+	return nil
+}
+
+func (f *fakeLocationStore) FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*monitor_location.Model, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var models []*monitor_location.Model
+	for _, model := range f.results[monitorID] {
+		if !model.ReportedAt.Before(since) {
+			models = append(models, &model)
+		}
+	}
+	return models, nil
+}
+
+func (f *fakeLocationStore) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.results, monitorID)
+	return nil
+}
+
+func applyAt(t *testing.T, tracker *locationTracker, m *Monitor, location string, result *executor.Result, now time.Time) *executor.Result {
+	t.Helper()
+	got, err := tracker.apply(context.Background(), m, location, result, now)
+	require.NoError(t, err)
+	return got
+}
+
+func locationResult(status shared.MonitorStatus, message string) *executor.Result {
+	return &executor.Result{Status: status, Message: message}
+}
+
+func TestLocationTracker_SingleLocationUnchanged(t *testing.T) {
+	tracker := newLocationTracker(2, nil)
+	m := &Monitor{ID: "m1", Interval: 60}
+	now := time.Now()
+
+	result := locationResult(shared.MonitorStatusDown, "timeout")
+	got := applyAt(t, tracker, m, "local", result, now)
+
+	assert.Same(t, result, got)
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+}
+
+func TestLocationTracker_EmptyLocationIgnored(t *testing.T) {
+	tracker := newLocationTracker(2, nil)
+	m := &Monitor{ID: "m1", Interval: 60}
+	now := time.Now()
+
+	applyAt(t, tracker, m, "local", locationResult(shared.MonitorStatusUp, "OK"), now)
+	got := applyAt(t, tracker, m, "", locationResult(shared.MonitorStatusDown, "push failed"), now)
+
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+	assert.Equal(t, "push failed", got.Message)
+}
+
+func TestLocationTracker_DownBelowQuorumIsPending(t *testing.T) {
+	tracker := newLocationTracker(2, nil)
+	m := &Monitor{ID: "m1", Interval: 60}
+	now := time.Now()
+
+	applyAt(t, tracker, m, "local", locationResult(shared.MonitorStatusUp, "OK"), now)
+	got := applyAt(t, tracker, m, "eu", locationResult(shared.MonitorStatusDown, "timeout"), now)
+
+	assert.Equal(t, shared.MonitorStatusPending, got.Status)
+	assert.Contains(t, got.Message, "[eu] timeout")
+	assert.Contains(t, got.Message, "down in 1 of 2 locations")
+}
+
+func TestLocationTracker_QuorumReached(t *testing.T) {
+	tracker := newLocationTracker(2, nil)
+	m := &Monitor{ID: "m1", Interval: 60}
+	now := time.Now()
+
+	applyAt(t, tracker, m, "local", locationResult(shared.MonitorStatusUp, "OK"), now)
+	applyAt(t, tracker, m, "eu", locationResult(shared.MonitorStatusDown, "timeout"), now)
+	got := applyAt(t, tracker, m, "us", locationResult(shared.MonitorStatusDown, "refused"), now)
+
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+	assert.Contains(t, got.Message, "down in 2 of 3 locations: eu, us")
+
+	// A healthy location does not flip the monitor back while the quorum still sees it down
+	got = applyAt(t, tracker, m, "local", locationResult(shared.MonitorStatusUp, "OK"), now)
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+	assert.Contains(t, got.Message, "[eu] timeout")
+}
+
+func TestLocationTracker_StaleLocationsDropped(t *testing.T) {
+	tracker := newLocationTracker(2, nil)
+	m := &Monitor{ID: "m1", Interval: 10}
+	now := time.Now()
+
+	applyAt(t, tracker, m, "eu", locationResult(shared.MonitorStatusUp, "OK"), now.Add(-time.Minute))
+	got := applyAt(t, tracker, m, "local", locationResult(shared.MonitorStatusDown, "timeout"), now)
+
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+	assert.Equal(t, "timeout", got.Message)
+}
+
+func TestLocationTracker_Forget(t *testing.T) {
+	tracker := newLocationTracker(2, nil)
+	m := &Monitor{ID: "m1", Interval: 60}
+	now := time.Now()
+
+	applyAt(t, tracker, m, "eu", locationResult(shared.MonitorStatusUp, "OK"), now)
+	tracker.forget(m.ID)
+	got := applyAt(t, tracker, m, "local", locationResult(shared.MonitorStatusDown, "timeout"), now)
+
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+}
+
+func TestLocationTracker_QuorumAcrossReplicas(t *testing.T) {
+	// Agents report to whichever replica the load balancer picks, both share the stored results
+	store := newFakeLocationStore()
+	first := newLocationTracker(2, store)
+	second := newLocationTracker(2, store)
+	m := &Monitor{ID: "m1", Interval: 60}
+	now := time.Now()
+
+	applyAt(t, first, m, "local", locationResult(shared.MonitorStatusUp, "OK"), now)
+	got := applyAt(t, second, m, "eu", locationResult(shared.MonitorStatusDown, "timeout"), now)
+	assert.Equal(t, shared.MonitorStatusPending, got.Status)
+	assert.Contains(t, got.Message, "down in 1 of 2 locations")
+
+	got = applyAt(t, first, m, "us", locationResult(shared.MonitorStatusDown, "refused"), now)
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+	assert.Contains(t, got.Message, "down in 2 of 3 locations: eu, us")
+
+	// Results older than three intervals are not counted
+	got = applyAt(t, second, m, "local", locationResult(shared.MonitorStatusDown, "timeout"), now.Add(4*time.Minute))
+	assert.Equal(t, shared.MonitorStatusDown, got.Status)
+	assert.Equal(t, "timeout", got.Message)
+}
@@ -0,0 +1,20 @@
+package healthcheck
+
+import (
+	"net/http"
+	"peekaping/src/modules/auth"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterMetricsEndpoint exposes the execution pool metrics used to size the pool
+func RegisterMetricsEndpoint(
+	router *gin.RouterGroup,
+	middleware *auth.MiddlewareProvider,
+	healthcheckSupervisor *HealthCheckSupervisor,
+) {
+	router.GET("/healthcheck/metrics", middleware.Auth(), func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", healthcheckSupervisor.Metrics()))
+	})
+}
@@ -0,0 +1,25 @@
+package healthcheck
+
+import (
+	"net/http"
+	"peekaping/src/modules/auth"
+	"peekaping/src/modules/healthcheck/executor"
+	"peekaping/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterPluginsEndpoint exposes the monitor types provided by plugins and their config schemas
+func RegisterPluginsEndpoint(
+	router *gin.RouterGroup,
+	middleware *auth.MiddlewareProvider,
+	registry *executor.ExecutorRegistry,
+) {
+	router.GET("/healthcheck/plugins", middleware.Auth(), func(ctx *gin.Context) {
+		plugins := registry.Plugins()
+		if plugins == nil {
+			plugins = []*executor.PluginDescriptor{}
+		}
+		ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", plugins))
+	})
+}
@@ -0,0 +1,228 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// job is a single monitor tick waiting for a worker
+type job struct {
+	host string
+	run  func()
+}
+
+// PoolMetrics describes the load of the execution pool
+type PoolMetrics struct {
+	Workers        int    `json:"workers"`
+	PerHostLimit   int    `json:"per_host_limit"`
+	QueueCapacity  int    `json:"queue_capacity"`
+	QueueDepth     int    `json:"queue_depth"`
+	WaitingForHost int    `json:"waiting_for_host"`
+	Running        int64  `json:"running"`
+	Executed       uint64 `json:"executed"`
+	DroppedTicks   uint64 `json:"dropped_ticks"`
+	SkippedTicks   uint64 `json:"skipped_ticks"`
+}
+
+// executionPool runs monitor ticks on a fixed number of workers fed by a bounded queue.
+// Ticks arriving while the queue is full are dropped, and with a per-host limit ticks for a
+// saturated host are parked until a check against that host finishes instead of holding a worker.
+type executionPool struct {
+	queue        chan *job
+	workers      int
+	perHostLimit int
+
+	mu          sync.Mutex
+	hostRunning map[string]int
+	hostWaiting map[string][]*job
+	waiting     int
+
+	running  atomic.Int64
+	executed atomic.Uint64
+	dropped  atomic.Uint64
+	skipped  atomic.Uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	wg       sync.WaitGroup
+}
+
+func newExecutionPool(workers int, queueSize int, perHostLimit int) *executionPool {
+	if workers < 1 {
+		workers = 1
+	}
+	if queueSize < 0 {
+		queueSize = 0
+	}
+	return &executionPool{
+		queue:        make(chan *job, queueSize),
+		workers:      workers,
+		perHostLimit: perHostLimit,
+		hostRunning:  make(map[string]int),
+		hostWaiting:  make(map[string][]*job),
+		stop:         make(chan struct{}),
+	}
+}
+
+func (p *executionPool) start() {
+	for i := 0; i < p.workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+}
+
+// shutdown stops the workers after their current check, queued and parked ticks are discarded
+func (p *executionPool) shutdown() {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+	p.wg.Wait()
+}
+
+// submit queues j, returning false when the queue is full and the tick was dropped
+func (p *executionPool) submit(j *job) bool {
+	select {
+	case p.queue <- j:
+		return true
+	default:
+		p.dropped.Add(1)
+		return false
+	}
+}
+
+// recordSkipped counts a tick skipped because the previous check of the monitor is still running
+func (p *executionPool) recordSkipped() {
+	p.skipped.Add(1)
+}
+
+func (p *executionPool) metrics() PoolMetrics {
+	p.mu.Lock()
+	waiting := p.waiting
+	p.mu.Unlock()
+
+	return PoolMetrics{
+		Workers:        p.workers,
+		PerHostLimit:   p.perHostLimit,
+		QueueCapacity:  cap(p.queue),
+		QueueDepth:     len(p.queue),
+		WaitingForHost: waiting,
+		Running:        p.running.Load(),
+		Executed:       p.executed.Load(),
+		DroppedTicks:   p.dropped.Load(),
+		SkippedTicks:   p.skipped.Load(),
+	}
+}
+
+func (p *executionPool) worker() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case j := <-p.queue:
+			// Keep serving ticks parked for the same host while they are handed over
+			for j != nil {
+				j = p.execute(j)
+			}
+		}
+	}
+}
+
+// execute runs j unless its host is saturated and returns the next tick parked for that host
+func (p *executionPool) execute(j *job) *job {
+	if !p.acquireHost(j) {
+		return nil
+	}
+
+	p.running.Add(1)
+	j.run()
+	p.running.Add(-1)
+	p.executed.Add(1)
+
+	return p.releaseHost(j.host)
+}
+
+func (p *executionPool) acquireHost(j *job) bool {
+	if p.perHostLimit <= 0 || j.host == "" {
+		return true
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.hostRunning[j.host] >= p.perHostLimit {
+		p.hostWaiting[j.host] = append(p.hostWaiting[j.host], j)
+		p.waiting++
+		return false
+	}
+	p.hostRunning[j.host]++
+	return true
+}
+
+func (p *executionPool) releaseHost(host string) *job {
+	if p.perHostLimit <= 0 || host == "" {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.hostRunning[host]--
+	if p.hostRunning[host] <= 0 {
+		delete(p.hostRunning, host)
+	}
+
+	waiting := p.hostWaiting[host]
+	if len(waiting) == 0 {
+		return nil
+	}
+
+	next := waiting[0]
+	if len(waiting) == 1 {
+		delete(p.hostWaiting, host)
+	} else {
+		p.hostWaiting[host] = waiting[1:]
+	}
+	p.waiting--
+
+	select {
+	case <-p.stop:
+		return nil
+	default:
+		return next
+	}
+}
+
+// targetHost extracts the host a monitor checks from its config, used for the per-host limit.
+// Monitors without a recognizable host are not limited.
+func targetHost(m *Monitor) string {
+	if m.Config == "" {
+		return ""
+	}
+
+	var cfg map[string]any
+	if err := json.Unmarshal([]byte(m.Config), &cfg); err != nil {
+		return ""
+	}
+
+	if raw, ok := cfg["url"].(string); ok && raw != "" {
+		if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+			return strings.ToLower(u.Hostname())
+		}
+	}
+
+	for _, key := range []string{"hostname", "host"} {
+		if raw, ok := cfg[key].(string); ok && raw != "" {
+			if host, _, err := net.SplitHostPort(raw); err == nil {
+				return strings.ToLower(host)
+			}
+			return strings.ToLower(raw)
+		}
+	}
+
+	return ""
+}
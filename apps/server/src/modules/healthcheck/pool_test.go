@@ -0,0 +1,126 @@
+package healthcheck
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestExecutionPool_LimitsConcurrency(t *testing.T) {
+	pool := newExecutionPool(2, 10, 0)
+	pool.start()
+	defer pool.shutdown()
+
+	var current, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		assert.True(t, pool.submit(&job{run: func() {
+			defer wg.Done()
+			n := current.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			current.Add(-1)
+		}}))
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int64(2))
+	assert.Equal(t, uint64(6), pool.metrics().Executed)
+}
+
+func TestExecutionPool_DropsWhenQueueFull(t *testing.T) {
+	pool := newExecutionPool(1, 1, 0)
+
+	// Workers are not started, so the queue fills up
+	assert.True(t, pool.submit(&job{run: func() {}}))
+	assert.False(t, pool.submit(&job{run: func() {}}))
+
+	metrics := pool.metrics()
+	assert.Equal(t, 1, metrics.QueueDepth)
+	assert.Equal(t, uint64(1), metrics.DroppedTicks)
+}
+
+func TestExecutionPool_PerHostLimit(t *testing.T) {
+	pool := newExecutionPool(4, 10, 1)
+	pool.start()
+	defer pool.shutdown()
+
+	var current, peak atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		assert.True(t, pool.submit(&job{host: "example.com", run: func() {
+			defer wg.Done()
+			n := current.Add(1)
+			if n > peak.Load() {
+				peak.Store(n)
+			}
+			time.Sleep(10 * time.Millisecond)
+			current.Add(-1)
+		}}))
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1), peak.Load())
+	assert.Equal(t, 0, pool.metrics().WaitingForHost)
+	assert.Equal(t, uint64(4), pool.metrics().Executed)
+}
+
+func TestSupervisor_ScheduleTickSkipsWhileRunning(t *testing.T) {
+	pool := newExecutionPool(1, 10, 0)
+	s := &HealthCheckSupervisor{pool: pool, logger: zap.NewNop().Sugar()}
+	m := &Monitor{ID: "m1", Name: "slow"}
+	tk := &task{}
+
+	// The previous tick is still in flight
+	tk.busy.Store(true)
+	s.scheduleTick(context.Background(), tk, "", m, nil, nil, nil)
+
+	metrics := pool.metrics()
+	assert.Equal(t, uint64(1), metrics.SkippedTicks)
+	assert.Equal(t, 0, metrics.QueueDepth)
+}
+
+func TestSupervisor_ScheduleTickReleasesDroppedTick(t *testing.T) {
+	pool := newExecutionPool(1, 0, 0)
+	s := &HealthCheckSupervisor{pool: pool, logger: zap.NewNop().Sugar()}
+	m := &Monitor{ID: "m1", Name: "dropped"}
+	tk := &task{}
+
+	s.scheduleTick(context.Background(), tk, "", m, nil, nil, nil)
+
+	assert.Equal(t, uint64(1), pool.metrics().DroppedTicks)
+	assert.False(t, tk.busy.Load())
+}
+
+func TestTargetHost(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"url", `{"url":"https://Example.com:8443/health"}`, "example.com"},
+		{"hostname", `{"hostname":"db.internal","port":5432}`, "db.internal"},
+		{"host with port", `{"host":"10.0.0.1:6379"}`, "10.0.0.1"},
+		{"no host", `{"container_id":"abc"}`, ""},
+		{"invalid json", `not json`, ""},
+		{"empty", ``, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, targetHost(&Monitor{Config: tt.config}))
+		})
+	}
+}
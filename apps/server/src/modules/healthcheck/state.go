@@ -0,0 +1,155 @@
+package healthcheck
+
+import (
+	"context"
+	"peekaping/src/modules/maintenance"
+	"peekaping/src/modules/shared"
+	"sync"
+	"time"
+)
+
+// maintenanceCacheTTL bounds how long cached maintenances are trusted, so changes made
+// through another replica are picked up even though its events never reach this one
+const maintenanceCacheTTL = time.Minute
+
+// monitorState is the part of the previous heartbeat needed to process the next one
+type monitorState struct {
+	Status    shared.MonitorStatus
+	Retries   int
+	DownCount int
+	// SlowChecks counts consecutive successful checks above the latency threshold
+	SlowChecks int
+	// SuppressedByDependency is set while the monitor is DOWN without its outage having been
+	// announced because a parent monitor was DOWN. It is not persisted, like SlowChecks.
+	SuppressedByDependency bool
+}
+
+// stateManager keeps the last state of every monitor in memory so ticks do not have to
+// read the previous heartbeat. A nil state means the monitor has no heartbeat yet.
+type stateManager struct {
+	mu     sync.RWMutex
+	states map[string]*monitorState
+}
+
+func newStateManager() *stateManager {
+	return &stateManager{
+		states: make(map[string]*monitorState),
+	}
+}
+
+// get returns the cached state and whether the monitor is known at all
+func (m *stateManager) get(monitorID string) (*monitorState, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	state, ok := m.states[monitorID]
+	return state, ok
+}
+
+func (m *stateManager) set(monitorID string, state *monitorState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.states[monitorID] = state
+}
+
+// forget drops the cached state so it is loaded from the database again on the next beat
+func (m *stateManager) forget(monitorID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.states, monitorID)
+}
+
+type maintenanceEntry struct {
+	maintenances []*maintenance.Model
+	loadedAt     time.Time
+}
+
+// maintenanceCache keeps the maintenances of every monitor, invalidated on maintenance changes
+type maintenanceCache struct {
+	mu      sync.RWMutex
+	entries map[string]*maintenanceEntry
+}
+
+func newMaintenanceCache() *maintenanceCache {
+	return &maintenanceCache{
+		entries: make(map[string]*maintenanceEntry),
+	}
+}
+
+// get returns the maintenances of the monitor, calling load when nothing fresh is cached
+func (c *maintenanceCache) get(
+	ctx context.Context,
+	monitorID string,
+	now time.Time,
+	load func(ctx context.Context, monitorID string) ([]*maintenance.Model, error),
+) ([]*maintenance.Model, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[monitorID]
+	c.mu.RUnlock()
+	if ok && now.Sub(entry.loadedAt) < maintenanceCacheTTL {
+		return entry.maintenances, nil
+	}
+
+	maintenances, err := load(ctx, monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[monitorID] = &maintenanceEntry{maintenances: maintenances, loadedAt: now}
+	c.mu.Unlock()
+
+	return maintenances, nil
+}
+
+// invalidate drops every cached entry, a maintenance may cover any number of monitors
+func (c *maintenanceCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*maintenanceEntry)
+}
+
+func (c *maintenanceCache) forget(monitorID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, monitorID)
+}
+
+// previousState returns the state left by the last heartbeat of the monitor, loading it
+// from the database only when it is not cached yet. Monitors of other nodes are never
+// cached, their owner keeps writing heartbeats this node does not see
+func (s *HealthCheckSupervisor) previousState(ctx context.Context, monitorID string) (*monitorState, error) {
+	owned := s.clusterService.Owns(monitorID)
+	if owned {
+		if state, ok := s.states.get(monitorID); ok {
+			return state, nil
+		}
+	}
+
+	previousBeats, err := s.heartbeatService.FindByMonitorIDPaginated(ctx, monitorID, 1, 0, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var state *monitorState
+	if len(previousBeats) > 0 {
+		state = &monitorState{
+			Status:    previousBeats[0].Status,
+			Retries:   previousBeats[0].Retries,
+			DownCount: previousBeats[0].DownCount,
+		}
+	}
+	if owned {
+		s.states.set(monitorID, state)
+	}
+
+	return state, nil
+}
+
+// seedStates loads the last state of the given monitors so steady-state ticks never hit the database
+func (s *HealthCheckSupervisor) seedStates(ctx context.Context, monitors []*Monitor) {
+	for _, m := range monitors {
+		if _, err := s.previousState(ctx, m.ID); err != nil {
+			s.logger.Errorf("Failed to seed state for monitor %s: %v", m.ID, err)
+		}
+	}
+}
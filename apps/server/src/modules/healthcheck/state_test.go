@@ -0,0 +1,182 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/maintenance"
+	"peekaping/src/modules/shared"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// countingHeartbeatService serves the latest heartbeat and counts how often it was read
+type countingHeartbeatService struct {
+	heartbeat.Service
+	latest map[string]*heartbeat.Model
+	reads  int
+}
+
+func (c *countingHeartbeatService) FindByMonitorIDPaginated(ctx context.Context, monitorID string, limit, page int, important *bool, reverse bool) ([]*heartbeat.Model, error) {
+	c.reads++
+	if hb, ok := c.latest[monitorID]; ok {
+		return []*heartbeat.Model{hb}, nil
+	}
+	return nil, nil
+}
+
+func TestSupervisor_PreviousStateIsCached(t *testing.T) {
+	hbService := &countingHeartbeatService{latest: map[string]*heartbeat.Model{
+		"m1": {MonitorID: "m1", Status: shared.MonitorStatusDown, Retries: 2, DownCount: 1},
+	}}
+	s := &HealthCheckSupervisor{
+		clusterService:   &fakeClusterService{},
+		heartbeatService: hbService,
+		states:           newStateManager(),
+		logger:           zap.NewNop().Sugar(),
+	}
+
+	state, err := s.previousState(context.Background(), "m1")
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, shared.MonitorStatusDown, state.Status)
+	assert.Equal(t, 2, state.Retries)
+	assert.Equal(t, 1, state.DownCount)
+
+	_, err = s.previousState(context.Background(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, hbService.reads)
+
+	// A monitor without heartbeats is cached as known-empty
+	state, err = s.previousState(context.Background(), "m2")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+	_, err = s.previousState(context.Background(), "m2")
+	require.NoError(t, err)
+	assert.Equal(t, 2, hbService.reads)
+}
+
+func TestSupervisor_SeedAndInvalidateState(t *testing.T) {
+	hbService := &countingHeartbeatService{latest: map[string]*heartbeat.Model{
+		"m1": {MonitorID: "m1", Status: shared.MonitorStatusUp},
+	}}
+	s := &HealthCheckSupervisor{
+		clusterService:   &fakeClusterService{},
+		heartbeatService: hbService,
+		states:           newStateManager(),
+		maintenances:     newMaintenanceCache(),
+		locations:        newLocationTracker(2, nil),
+		flaps:            newFlapTracker(),
+		logger:           zap.NewNop().Sugar(),
+	}
+
+	s.seedStates(context.Background(), []*Monitor{{ID: "m1"}, {ID: "m2"}})
+	assert.Equal(t, 2, hbService.reads)
+
+	_, err := s.previousState(context.Background(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, 2, hbService.reads)
+
+	s.InvalidateState("m1")
+	_, err = s.previousState(context.Background(), "m1")
+	require.NoError(t, err)
+	assert.Equal(t, 3, hbService.reads)
+
+	s.ForgetMonitor("m2")
+	_, ok := s.states.get("m2")
+	assert.False(t, ok)
+}
+
+func TestSupervisor_PreviousStateOfOtherNodesNotCached(t *testing.T) {
+	hbService := &countingHeartbeatService{latest: map[string]*heartbeat.Model{
+		"m1": {MonitorID: "m1", Status: shared.MonitorStatusUp},
+		"m2": {MonitorID: "m2", Status: shared.MonitorStatusDown},
+	}}
+	s := &HealthCheckSupervisor{
+		clusterService:   &fakeClusterService{enabled: true, owned: map[string]bool{"m1": true}},
+		heartbeatService: hbService,
+		states:           newStateManager(),
+		logger:           zap.NewNop().Sugar(),
+	}
+
+	for i := 0; i < 2; i++ {
+		state, err := s.previousState(context.Background(), "m2")
+		require.NoError(t, err)
+		assert.Equal(t, shared.MonitorStatusDown, state.Status)
+	}
+	assert.Equal(t, 2, hbService.reads)
+	_, ok := s.states.get("m2")
+	assert.False(t, ok)
+
+	_, err := s.previousState(context.Background(), "m1")
+	require.NoError(t, err)
+	_, ok = s.states.get("m1")
+	assert.True(t, ok)
+}
+
+func TestSupervisor_ReceivedResultReadsStateWithClustering(t *testing.T) {
+	m := &Monitor{ID: "m1", Name: "Push", Active: true}
+
+	// The cached UP state is stale, the database holds no heartbeat yet
+	s, hbService := newTickTestSupervisor(nil, nil)
+	s.clusterService = &fakeClusterService{enabled: true}
+	s.states.set(m.ID, &monitorState{Status: shared.MonitorStatusUp})
+	s.processReceivedResult(tickResult(shared.MonitorStatusUp, "OK", 10), m, "")
+	require.Len(t, hbService.beats, 1)
+	assert.True(t, hbService.beats[0].Important, "the first stored beat is important")
+
+	// A single node trusts its cache
+	s, hbService = newTickTestSupervisor(nil, nil)
+	s.states.set(m.ID, &monitorState{Status: shared.MonitorStatusUp})
+	s.processReceivedResult(tickResult(shared.MonitorStatusUp, "OK", 10), m, "")
+	require.Len(t, hbService.beats, 1)
+	assert.False(t, hbService.beats[0].Important)
+}
+
+func TestMaintenanceCache(t *testing.T) {
+	cache := newMaintenanceCache()
+	loads := 0
+	load := func(ctx context.Context, monitorID string) ([]*maintenance.Model, error) {
+		loads++
+		return []*maintenance.Model{{ID: "mt1"}}, nil
+	}
+	now := time.Now()
+
+	got, err := cache.get(context.Background(), "m1", now, load)
+	require.NoError(t, err)
+	assert.Len(t, got, 1)
+
+	_, err = cache.get(context.Background(), "m1", now.Add(time.Second), load)
+	require.NoError(t, err)
+	assert.Equal(t, 1, loads)
+
+	// Maintenance changes drop every entry
+	cache.invalidate()
+	_, err = cache.get(context.Background(), "m1", now.Add(2*time.Second), load)
+	require.NoError(t, err)
+	assert.Equal(t, 2, loads)
+
+	// Stale entries are reloaded
+	_, err = cache.get(context.Background(), "m1", now.Add(maintenanceCacheTTL+2*time.Second), load)
+	require.NoError(t, err)
+	assert.Equal(t, 3, loads)
+}
+
+func TestMaintenanceCache_LoadErrorNotCached(t *testing.T) {
+	cache := newMaintenanceCache()
+	loads := 0
+	load := func(ctx context.Context, monitorID string) ([]*maintenance.Model, error) {
+		loads++
+		return nil, errors.New("db down")
+	}
+
+	_, err := cache.get(context.Background(), "m1", time.Now(), load)
+	assert.Error(t, err)
+	_, err = cache.get(context.Background(), "m1", time.Now(), load)
+	assert.Error(t, err)
+	assert.Equal(t, 2, loads)
+}
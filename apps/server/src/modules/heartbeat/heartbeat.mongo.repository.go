@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"peekaping/src/config"
+	"peekaping/src/modules/shared"
 
 	"time"
 
@@ -26,6 +27,8 @@ type mongoModel struct {
 	Time      time.Time          `bson:"time"`
 	EndTime   time.Time          `bson:"end_time"`
 	Notified  bool               `bson:"notified"`
+	Location  string             `bson:"location,omitempty"`
+	Metadata  bson.M             `bson:"metadata,omitempty"`
 }
 
 type RepositoryImpl struct {
@@ -48,6 +51,8 @@ func toDomainModel(mm *mongoModel) *Model {
 		Time:      mm.Time,
 		EndTime:   mm.EndTime,
 		Notified:  mm.Notified,
+		Location:  mm.Location,
+		Metadata:  shared.HeartBeatMetadata(mm.Metadata),
 	}
 }
 
@@ -86,6 +91,8 @@ func (r *RepositoryImpl) Create(ctx context.Context, entity *Model) (*Model, err
 		Time:      entity.Time,
 		EndTime:   entity.EndTime,
 		Notified:  entity.Notified,
+		Location:  entity.Location,
+		Metadata:  bson.M(entity.Metadata),
 	}
 
 	_, err = r.collection.InsertOne(ctx, mm)
@@ -202,7 +209,7 @@ func (r *RepositoryImpl) FindUptimeStatsByMonitorID(ctx context.Context, monitor
 			}},
 			bson.M{"$group": bson.M{
 				"_id":  nil,
-				"up":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", 1}}, 1, 0}}},
+				"up":   bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$in": bson.A{"$status", bson.A{1, 4}}}, 1, 0}}},
 				"down": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$eq": bson.A{"$status", 0}}, 1, 0}}},
 			}},
 		}
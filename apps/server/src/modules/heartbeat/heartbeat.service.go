@@ -55,6 +55,8 @@ func (mr *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Mo
 		Time:      entity.Time,
 		EndTime:   entity.EndTime,
 		Notified:  entity.Notified,
+		Location:  entity.Location,
+		Metadata:  entity.Metadata,
 	}
 
 	created, err := mr.repository.Create(ctx, createModel)
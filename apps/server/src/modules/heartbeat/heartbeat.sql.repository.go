@@ -2,6 +2,7 @@ package heartbeat
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"peekaping/src/modules/shared"
@@ -25,9 +26,16 @@ type sqlModel struct {
 	Time      time.Time `bun:"time,nullzero,notnull,default:current_timestamp"`
 	EndTime   time.Time `bun:"end_time,nullzero"`
 	Notified  bool      `bun:"notified,notnull,default:false"`
+	Location  string    `bun:"location"`
+	Metadata  string    `bun:"metadata"` // Store as JSON string for compatibility
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
+	var metadata shared.HeartBeatMetadata
+	if sm.Metadata != "" {
+		json.Unmarshal([]byte(sm.Metadata), &metadata)
+	}
+
 	return &Model{
 		ID:        sm.ID,
 		MonitorID: sm.MonitorID,
@@ -41,10 +49,18 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 		Time:      sm.Time,
 		EndTime:   sm.EndTime,
 		Notified:  sm.Notified,
+		Location:  sm.Location,
+		Metadata:  metadata,
 	}
 }
 
 func toSQLModel(m *Model) *sqlModel {
+	var metadata string
+	if len(m.Metadata) > 0 {
+		metadataBytes, _ := json.Marshal(m.Metadata)
+		metadata = string(metadataBytes)
+	}
+
 	return &sqlModel{
 		ID:        m.ID,
 		MonitorID: m.MonitorID,
@@ -58,6 +74,8 @@ func toSQLModel(m *Model) *sqlModel {
 		Time:      m.Time,
 		EndTime:   m.EndTime,
 		Notified:  m.Notified,
+		Location:  m.Location,
+		Metadata:  metadata,
 	}
 }
 
@@ -195,7 +213,7 @@ func (r *SQLRepositoryImpl) FindUptimeStatsByMonitorID(
 		err := r.db.NewSelect().
 			Model((*sqlModel)(nil)).
 			ColumnExpr("COUNT(*) as total").
-			ColumnExpr("COUNT(CASE WHEN status = ? THEN 1 END) as up", 1).
+			ColumnExpr("COUNT(CASE WHEN status IN (?, ?) THEN 1 END) as up", int(shared.MonitorStatusUp), int(shared.MonitorStatusDegraded)).
 			Where("monitor_id = ? AND time >= ?", monitorID, since).
 			Scan(ctx, &result)
 
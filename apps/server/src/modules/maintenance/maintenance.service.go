@@ -6,6 +6,7 @@ import (
 
 	"go.uber.org/zap"
 
+	"peekaping/src/modules/events"
 	"peekaping/src/modules/maintenance/utils"
 	"peekaping/src/modules/monitor_maintenance"
 )
@@ -38,12 +39,14 @@ type ServiceImpl struct {
 	timeWindowChecker         utils.TimeWindowCheckerInterface
 	timeUtils                 utils.TimeUtilsInterface
 	validator                 utils.ValidatorInterface
+	eventBus                  *events.EventBus
 }
 
 func NewService(
 	repository Repository,
 	monitorMaintenanceService monitor_maintenance.Service,
 	logger *zap.SugaredLogger,
+	eventBus *events.EventBus,
 ) Service {
 	return &ServiceImpl{
 		repository:                repository,
@@ -53,6 +56,7 @@ func NewService(
 		timeWindowChecker:         utils.NewTimeWindowChecker(logger),
 		timeUtils:                 utils.NewTimeUtils(),
 		validator:                 utils.NewValidator(),
+		eventBus:                  eventBus,
 	}
 }
 
@@ -103,6 +107,11 @@ func (mr *ServiceImpl) Create(ctx context.Context, entity *CreateUpdateDto) (*Mo
 		}
 	}
 
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MaintenanceCreated,
+		Payload: created,
+	})
+
 	return created, nil
 }
 
@@ -171,6 +180,11 @@ func (mr *ServiceImpl) UpdateFull(ctx context.Context, id string, entity *Create
 		}
 	}
 
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MaintenanceUpdated,
+		Payload: updated,
+	})
+
 	return updated, nil
 }
 
@@ -274,11 +288,25 @@ func (mr *ServiceImpl) UpdatePartial(ctx context.Context, id string, entity *Par
 		}
 	}
 
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MaintenanceUpdated,
+		Payload: updated,
+	})
+
 	return updated, nil
 }
 
 func (mr *ServiceImpl) Delete(ctx context.Context, id string) error {
-	return mr.repository.Delete(ctx, id)
+	if err := mr.repository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MaintenanceDeleted,
+		Payload: id,
+	})
+
+	return nil
 }
 
 func (mr *ServiceImpl) SetActive(ctx context.Context, id string, active bool) (*Model, error) {
@@ -287,6 +315,11 @@ func (mr *ServiceImpl) SetActive(ctx context.Context, id string, active bool) (*
 		return nil, err
 	}
 
+	mr.eventBus.Publish(events.Event{
+		Type:    events.MaintenanceUpdated,
+		Payload: model,
+	})
+
 	return model, nil
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
 
+	"peekaping/src/modules/events"
 	"peekaping/src/modules/maintenance/utils"
 	"peekaping/src/modules/monitor_maintenance"
 )
@@ -216,6 +217,7 @@ func createTestService() (*ServiceImpl, *MockRepository, *MockMonitorMaintenance
 		timeWindowChecker:         mockTimeWindowChecker,
 		timeUtils:                 mockTimeUtils,
 		validator:                 mockValidator,
+		eventBus:                  events.NewEventBus(logger),
 	}
 
 	return service, mockRepo, mockMonitorMaintenanceService, mockCronGenerator, mockTimeWindowChecker, mockTimeUtils, mockValidator
@@ -3,6 +3,8 @@ package monitor
 import (
 	"fmt"
 	"net/http"
+	"peekaping/src/modules/monitor_dependency"
+	"peekaping/src/modules/monitor_flap"
 	"peekaping/src/modules/monitor_notification"
 	"peekaping/src/modules/monitor_tag"
 	"peekaping/src/modules/monitor_tls_info"
@@ -20,6 +22,8 @@ type MonitorController struct {
 	monitorNotificationService monitor_notification.Service
 	monitorTagService          monitor_tag.Service
 	tlsInfoService             monitor_tls_info.Service
+	monitorDependencyService   monitor_dependency.Service
+	monitorFlapService         monitor_flap.Service
 }
 
 func NewMonitorController(
@@ -28,6 +32,8 @@ func NewMonitorController(
 	monitorNotificationService monitor_notification.Service,
 	monitorTagService monitor_tag.Service,
 	tlsInfoService monitor_tls_info.Service,
+	monitorDependencyService monitor_dependency.Service,
+	monitorFlapService monitor_flap.Service,
 ) *MonitorController {
 	utils.Validate.RegisterStructValidation(CreateUpdateDtoStructLevelValidation, CreateUpdateDto{})
 
@@ -37,6 +43,8 @@ func NewMonitorController(
 		monitorNotificationService,
 		monitorTagService,
 		tlsInfoService,
+		monitorDependencyService,
+		monitorFlapService,
 	}
 }
 
@@ -228,22 +236,26 @@ func (ic *MonitorController) FindByID(ctx *gin.Context) {
 
 	// Compose response with notification_ids and tag_ids
 	response := MonitorResponseDto{
-		ID:              monitor.ID,
-		Name:            monitor.Name,
-		Interval:        monitor.Interval,
-		Timeout:         monitor.Timeout,
-		Type:            monitor.Type,
-		Active:          monitor.Active,
-		MaxRetries:      monitor.MaxRetries,
-		RetryInterval:   monitor.RetryInterval,
-		ResendInterval:  monitor.ResendInterval,
-		Status:          int(monitor.Status),
-		CreatedAt:       monitor.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:       monitor.UpdatedAt.Format(time.RFC3339),
-		NotificationIds: notificationIds,
-		TagIds:          tagIds,
-		ProxyId:         monitor.ProxyId,
-		Config:          monitor.Config,
+		ID:                monitor.ID,
+		Name:              monitor.Name,
+		Interval:          monitor.Interval,
+		Timeout:           monitor.Timeout,
+		Type:              monitor.Type,
+		Active:            monitor.Active,
+		MaxRetries:        monitor.MaxRetries,
+		RetryInterval:     monitor.RetryInterval,
+		ResendInterval:    monitor.ResendInterval,
+		DegradedThreshold: monitor.DegradedThreshold,
+		DegradedBreaches:  monitor.DegradedBreaches,
+		FlapThreshold:     monitor.FlapThreshold,
+		FlapWindow:        monitor.FlapWindow,
+		Status:            int(monitor.Status),
+		CreatedAt:         monitor.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         monitor.UpdatedAt.Format(time.RFC3339),
+		NotificationIds:   notificationIds,
+		TagIds:            tagIds,
+		ProxyId:           monitor.ProxyId,
+		Config:            monitor.Config,
 	}
 
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
@@ -730,3 +742,200 @@ func (ic *MonitorController) GetTLSInfo(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", tlsInfo))
 }
+
+// @Router /monitors/{id}/dependencies [get]
+// @Summary Get monitor dependencies
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Success 200 {object} utils.ApiResponse[DependenciesResponseDto]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) GetDependencies(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	monitor, err := ic.monitorService.FindByID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if monitor == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		return
+	}
+
+	parentRels, err := ic.monitorDependencyService.FindByMonitorID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor parents", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	childRels, err := ic.monitorDependencyService.FindByParentID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor children", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	response := DependenciesResponseDto{
+		ParentIds: make([]string, 0, len(parentRels)),
+		ChildIds:  make([]string, 0, len(childRels)),
+	}
+	for _, rel := range parentRels {
+		response.ParentIds = append(response.ParentIds, rel.ParentID)
+	}
+	for _, rel := range childRels {
+		response.ChildIds = append(response.ChildIds, rel.MonitorID)
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", response))
+}
+
+// @Router /monitors/{id}/dependencies [put]
+// @Summary Replace monitor parents
+// @Tags Monitors
+// @Produce json
+// @Accept json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Param body body DependenciesDto true "Parent monitors"
+// @Success 200 {object} utils.ApiResponse[DependenciesResponseDto]
+// @Failure 400 {object} utils.APIError[any]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) UpdateDependencies(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	var dto DependenciesDto
+	if err := ctx.ShouldBindJSON(&dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+	if err := utils.Validate.Struct(dto); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(err.Error()))
+		return
+	}
+
+	monitor, err := ic.monitorService.FindByID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if monitor == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		return
+	}
+
+	// Every parent has to exist
+	if len(dto.ParentIds) > 0 {
+		parents, err := ic.monitorService.FindByIDs(ctx, dto.ParentIds)
+		if err != nil {
+			ic.logger.Errorw("Failed to fetch parent monitors", "monitorID", id, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+		found := make(map[string]bool, len(parents))
+		for _, parent := range parents {
+			found[parent.ID] = true
+		}
+		for _, parentID := range dto.ParentIds {
+			if !found[parentID] {
+				ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(fmt.Sprintf("Parent monitor %s not found", parentID)))
+				return
+			}
+		}
+	}
+
+	// Reject parents that would make the dependency graph cyclic
+	rels, err := ic.monitorDependencyService.FindAll(ctx)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor dependencies", "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	edges := make(map[string][]string)
+	for _, rel := range rels {
+		edges[rel.MonitorID] = append(edges[rel.MonitorID], rel.ParentID)
+	}
+	if err := ValidateDependencies(id, dto.ParentIds, edges); err != nil {
+		ctx.JSON(http.StatusBadRequest, utils.NewFailResponse(fmt.Sprintf("Invalid dependencies: %v", err)))
+		return
+	}
+
+	if err := ic.monitorDependencyService.DeleteByMonitorID(ctx, id); err != nil {
+		ic.logger.Errorw("Failed to delete monitor dependencies", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	for _, parentID := range dto.ParentIds {
+		if _, err := ic.monitorDependencyService.Create(ctx, id, parentID); err != nil {
+			ic.logger.Errorw("Failed to create monitor dependency", "monitorID", id, "parentID", parentID, "error", err)
+			ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+			return
+		}
+	}
+
+	childRels, err := ic.monitorDependencyService.FindByParentID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor children", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	response := DependenciesResponseDto{
+		ParentIds: dto.ParentIds,
+		ChildIds:  make([]string, 0, len(childRels)),
+	}
+	for _, rel := range childRels {
+		response.ChildIds = append(response.ChildIds, rel.MonitorID)
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("Monitor dependencies updated successfully", response))
+}
+
+// @Router /monitors/{id}/flaps [get]
+// @Summary Get monitor flapping periods
+// @Tags Monitors
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Monitor ID"
+// @Param since query string false "Only periods still flapping at or after this time (RFC3339), defaults to 30 days ago"
+// @Success 200 {object} utils.ApiResponse[[]monitor_flap.Model]
+// @Failure 400 {object} utils.APIError[any]
+// @Failure 404 {object} utils.APIError[any]
+// @Failure 500 {object} utils.APIError[any]
+func (ic *MonitorController) GetFlapPeriods(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	since := time.Now().UTC().AddDate(0, 0, -30)
+	if sinceStr := ctx.Query("since"); sinceStr != "" {
+		parsed, err := time.Parse(time.RFC3339, sinceStr)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, utils.NewFailResponse("Invalid 'since' parameter (must be RFC3339)"))
+			return
+		}
+		since = parsed
+	}
+
+	monitor, err := ic.monitorService.FindByID(ctx, id)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch monitor", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+	if monitor == nil {
+		ctx.JSON(http.StatusNotFound, utils.NewFailResponse("Monitor not found"))
+		return
+	}
+
+	periods, err := ic.monitorFlapService.FindByMonitorID(ctx, id, since)
+	if err != nil {
+		ic.logger.Errorw("Failed to fetch flapping periods", "monitorID", id, "error", err)
+		ctx.JSON(http.StatusInternalServerError, utils.NewFailResponse("Internal server error"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, utils.NewSuccessResponse("success", periods))
+}
@@ -3,36 +3,55 @@ package monitor
 import "peekaping/src/modules/heartbeat"
 
 type CreateUpdateDto struct {
-	Type            string   `json:"type" validate:"required" example:"http"`
-	Name            string   `json:"name" validate:"required,min=3" example:"My Monitor"`
-	Interval        int      `json:"interval" validate:"min=20" example:"60"`
-	MaxRetries      int      `json:"max_retries" validate:"min=0" example:"3"`
-	RetryInterval   int      `json:"retry_interval" validate:"min=20" example:"60"`
-	Timeout         int      `json:"timeout" validate:"min=16" example:"16"`
-	ResendInterval  int      `json:"resend_interval" validate:"min=0" example:"10"`
-	Active          bool     `json:"active" example:"true"`
-	NotificationIds []string `json:"notification_ids" validate:"required" example:"6830ad485361f19c598d6d90"`
-	TagIds          []string `json:"tag_ids" example:"6830ad485361f19c598d6d90,6830ad485361f19c598d6d91"`
-	ProxyId         string   `json:"proxy_id" example:"6830ad485361f19c598d6d90"`
-	Config          string   `json:"config"`
-	PushToken       string   `json:"push_token"`
+	Type              string   `json:"type" validate:"required" example:"http"`
+	Name              string   `json:"name" validate:"required,min=3" example:"My Monitor"`
+	Interval          int      `json:"interval" validate:"min=20" example:"60"`
+	MaxRetries        int      `json:"max_retries" validate:"min=0" example:"3"`
+	RetryInterval     int      `json:"retry_interval" validate:"min=20" example:"60"`
+	Timeout           int      `json:"timeout" validate:"min=16" example:"16"`
+	ResendInterval    int      `json:"resend_interval" validate:"min=0" example:"10"`
+	DegradedThreshold int      `json:"degraded_threshold" validate:"min=0" example:"2000"`
+	DegradedBreaches  int      `json:"degraded_breaches" validate:"min=0" example:"3"`
+	FlapThreshold     int      `json:"flap_threshold" validate:"min=0,max=100" example:"30"`
+	FlapWindow        int      `json:"flap_window" validate:"min=0" example:"20"`
+	Active            bool     `json:"active" example:"true"`
+	NotificationIds   []string `json:"notification_ids" validate:"required" example:"6830ad485361f19c598d6d90"`
+	TagIds            []string `json:"tag_ids" example:"6830ad485361f19c598d6d90,6830ad485361f19c598d6d91"`
+	ProxyId           string   `json:"proxy_id" example:"6830ad485361f19c598d6d90"`
+	Config            string   `json:"config"`
+	PushToken         string   `json:"push_token"`
 }
 
 type PartialUpdateDto struct {
-	Name            *string                  `json:"name,omitempty" example:"My Monitor"`
-	Interval        *int                     `json:"interval,omitempty" example:"60"`
-	Timeout         *int                     `json:"timeout,omitempty" example:"16"`
-	Type            *string                  `json:"type,omitempty" example:"http"`
-	MaxRetries      *int                     `json:"max_retries,omitempty" example:"3"`
-	RetryInterval   *int                     `json:"retry_interval,omitempty" example:"60"`
-	ResendInterval  *int                     `json:"resend_interval,omitempty" example:"10"`
-	Active          *bool                    `json:"active,omitempty" example:"true"`
-	NotificationIds []string                 `json:"notification_ids,omitempty" example:"6830ad485361f19c598d6d90"`
-	TagIds          []string                 `json:"tag_ids,omitempty" example:"6830ad485361f19c598d6d90,6830ad485361f19c598d6d91"`
-	ProxyId         *string                  `json:"proxy_id,omitempty" example:"6830ad485361f19c598d6d90"`
-	Status          *heartbeat.MonitorStatus `json:"status,omitempty" example:"1"`
-	Config          *string                  `json:"config,omitempty"`
-	PushToken       *string                  `json:"push_token,omitempty"`
+	Name              *string                  `json:"name,omitempty" example:"My Monitor"`
+	Interval          *int                     `json:"interval,omitempty" example:"60"`
+	Timeout           *int                     `json:"timeout,omitempty" example:"16"`
+	Type              *string                  `json:"type,omitempty" example:"http"`
+	MaxRetries        *int                     `json:"max_retries,omitempty" example:"3"`
+	RetryInterval     *int                     `json:"retry_interval,omitempty" example:"60"`
+	ResendInterval    *int                     `json:"resend_interval,omitempty" example:"10"`
+	DegradedThreshold *int                     `json:"degraded_threshold,omitempty" validate:"omitempty,min=0" example:"2000"`
+	DegradedBreaches  *int                     `json:"degraded_breaches,omitempty" validate:"omitempty,min=0" example:"3"`
+	FlapThreshold     *int                     `json:"flap_threshold,omitempty" validate:"omitempty,min=0,max=100" example:"30"`
+	FlapWindow        *int                     `json:"flap_window,omitempty" validate:"omitempty,min=0" example:"20"`
+	Active            *bool                    `json:"active,omitempty" example:"true"`
+	NotificationIds   []string                 `json:"notification_ids,omitempty" example:"6830ad485361f19c598d6d90"`
+	TagIds            []string                 `json:"tag_ids,omitempty" example:"6830ad485361f19c598d6d90,6830ad485361f19c598d6d91"`
+	ProxyId           *string                  `json:"proxy_id,omitempty" example:"6830ad485361f19c598d6d90"`
+	Status            *heartbeat.MonitorStatus `json:"status,omitempty" example:"1"`
+	Config            *string                  `json:"config,omitempty"`
+	PushToken         *string                  `json:"push_token,omitempty"`
+}
+
+// DependenciesDto replaces the parent monitors of a monitor
+type DependenciesDto struct {
+	ParentIds []string `json:"parent_ids" validate:"required,dive,required" example:"6830ad485361f19c598d6d90"`
+}
+
+// DependenciesResponseDto lists the monitors a monitor depends on and the ones depending on it
+type DependenciesResponseDto struct {
+	ParentIds []string `json:"parent_ids" example:"6830ad485361f19c598d6d90"`
+	ChildIds  []string `json:"child_ids" example:"6830ad485361f19c598d6d91"`
 }
 
 // UptimeStatsDto represents uptime percentages for various periods
@@ -45,23 +64,27 @@ type UptimeStatsDto struct {
 }
 
 type MonitorResponseDto struct {
-	ID              string   `json:"id" example:"60c72b2f9b1e8b6f1f8e4b1a"`
-	Name            string   `json:"name" example:"My Monitor"`
-	Interval        int      `json:"interval" example:"60"`
-	Timeout         int      `json:"timeout" example:"10"`
-	Type            string   `json:"type" example:"http"`
-	Active          bool     `json:"active" example:"true" default:"true"`
-	Status          int      `json:"status" example:"1"`
-	MaxRetries      int      `json:"max_retries" example:"3"`
-	RetryInterval   int      `json:"retry_interval" example:"10"`
-	ResendInterval  int      `json:"resend_interval" example:"3"`
-	CreatedAt       string   `json:"created_at" example:"2024-06-01T12:00:00Z"`
-	UpdatedAt       string   `json:"updated_at" example:"2024-06-01T12:00:00Z"`
-	NotificationIds []string `json:"notification_ids" example:"6830ad485361f19c598d6d90"`
-	TagIds          []string `json:"tag_ids" example:"6830ad485361f19c598d6d90,6830ad485361f19c598d6d91"`
-	ProxyId         string   `json:"proxy_id" example:"6830ad485361f19c598d6d90"`
-	Config          string   `json:"config"`
-	PushToken       string   `json:"push_token"`
+	ID                string   `json:"id" example:"60c72b2f9b1e8b6f1f8e4b1a"`
+	Name              string   `json:"name" example:"My Monitor"`
+	Interval          int      `json:"interval" example:"60"`
+	Timeout           int      `json:"timeout" example:"10"`
+	Type              string   `json:"type" example:"http"`
+	Active            bool     `json:"active" example:"true" default:"true"`
+	Status            int      `json:"status" example:"1"`
+	MaxRetries        int      `json:"max_retries" example:"3"`
+	RetryInterval     int      `json:"retry_interval" example:"10"`
+	ResendInterval    int      `json:"resend_interval" example:"3"`
+	DegradedThreshold int      `json:"degraded_threshold" example:"2000"`
+	DegradedBreaches  int      `json:"degraded_breaches" example:"3"`
+	FlapThreshold     int      `json:"flap_threshold" example:"30"`
+	FlapWindow        int      `json:"flap_window" example:"20"`
+	CreatedAt         string   `json:"created_at" example:"2024-06-01T12:00:00Z"`
+	UpdatedAt         string   `json:"updated_at" example:"2024-06-01T12:00:00Z"`
+	NotificationIds   []string `json:"notification_ids" example:"6830ad485361f19c598d6d90"`
+	TagIds            []string `json:"tag_ids" example:"6830ad485361f19c598d6d90,6830ad485361f19c598d6d91"`
+	ProxyId           string   `json:"proxy_id" example:"6830ad485361f19c598d6d90"`
+	Config            string   `json:"config"`
+	PushToken         string   `json:"push_token"`
 }
 
 // StatPointsSummaryDto represents stat points and summary for a period
@@ -14,38 +14,46 @@ import (
 )
 
 type mongoModel struct {
-	ID             primitive.ObjectID      `bson:"_id"`
-	Type           string                  `bson:"type"`
-	Name           string                  `bson:"name"`
-	Interval       int                     `bson:"interval"`
-	Timeout        int                     `bson:"timeout"`
-	MaxRetries     int                     `bson:"max_retries"`
-	RetryInterval  int                     `bson:"retry_interval"`
-	ResendInterval int                     `bson:"resend_interval"`
-	Active         bool                    `bson:"active"`
-	Status         heartbeat.MonitorStatus `bson:"status"`
-	CreatedAt      time.Time               `bson:"created_at"`
-	UpdatedAt      time.Time               `bson:"updated_at"`
-	Config         string                  `bson:"config"`
-	ProxyId        *primitive.ObjectID     `bson:"proxy_id,omitempty"`
-	PushToken      string                  `bson:"push_token"`
+	ID                primitive.ObjectID      `bson:"_id"`
+	Type              string                  `bson:"type"`
+	Name              string                  `bson:"name"`
+	Interval          int                     `bson:"interval"`
+	Timeout           int                     `bson:"timeout"`
+	MaxRetries        int                     `bson:"max_retries"`
+	RetryInterval     int                     `bson:"retry_interval"`
+	ResendInterval    int                     `bson:"resend_interval"`
+	DegradedThreshold int                     `bson:"degraded_threshold"`
+	DegradedBreaches  int                     `bson:"degraded_breaches"`
+	FlapThreshold     int                     `bson:"flap_threshold"`
+	FlapWindow        int                     `bson:"flap_window"`
+	Active            bool                    `bson:"active"`
+	Status            heartbeat.MonitorStatus `bson:"status"`
+	CreatedAt         time.Time               `bson:"created_at"`
+	UpdatedAt         time.Time               `bson:"updated_at"`
+	Config            string                  `bson:"config"`
+	ProxyId           *primitive.ObjectID     `bson:"proxy_id,omitempty"`
+	PushToken         string                  `bson:"push_token"`
 }
 
 type mongoUpdateModel struct {
-	Type           *string                  `bson:"type,omitempty"`
-	Name           *string                  `bson:"name,omitempty"`
-	Interval       *int                     `bson:"interval,omitempty"`
-	Timeout        *int                     `bson:"timeout,omitempty"`
-	MaxRetries     *int                     `bson:"max_retries,omitempty"`
-	RetryInterval  *int                     `bson:"retry_interval,omitempty"`
-	ResendInterval *int                     `bson:"resend_interval,omitempty"`
-	Active         *bool                    `bson:"active,omitempty"`
-	Status         *heartbeat.MonitorStatus `bson:"status,omitempty"`
-	Config         *string                  `bson:"config,omitempty"`
-	ProxyId        *primitive.ObjectID      `bson:"proxy_id,omitempty"`
-	PushToken      *string                  `bson:"push_token,omitempty"`
-	CreatedAt      *time.Time               `bson:"created_at,omitempty"`
-	UpdatedAt      *time.Time               `bson:"updated_at,omitempty"`
+	Type              *string                  `bson:"type,omitempty"`
+	Name              *string                  `bson:"name,omitempty"`
+	Interval          *int                     `bson:"interval,omitempty"`
+	Timeout           *int                     `bson:"timeout,omitempty"`
+	MaxRetries        *int                     `bson:"max_retries,omitempty"`
+	RetryInterval     *int                     `bson:"retry_interval,omitempty"`
+	ResendInterval    *int                     `bson:"resend_interval,omitempty"`
+	DegradedThreshold *int                     `bson:"degraded_threshold,omitempty"`
+	DegradedBreaches  *int                     `bson:"degraded_breaches,omitempty"`
+	FlapThreshold     *int                     `bson:"flap_threshold,omitempty"`
+	FlapWindow        *int                     `bson:"flap_window,omitempty"`
+	Active            *bool                    `bson:"active,omitempty"`
+	Status            *heartbeat.MonitorStatus `bson:"status,omitempty"`
+	Config            *string                  `bson:"config,omitempty"`
+	ProxyId           *primitive.ObjectID      `bson:"proxy_id,omitempty"`
+	PushToken         *string                  `bson:"push_token,omitempty"`
+	CreatedAt         *time.Time               `bson:"created_at,omitempty"`
+	UpdatedAt         *time.Time               `bson:"updated_at,omitempty"`
 }
 
 func toDomainModel(mm *mongoModel) *Model {
@@ -56,21 +64,25 @@ func toDomainModel(mm *mongoModel) *Model {
 		proxyId = ""
 	}
 	return &Model{
-		ID:             mm.ID.Hex(),
-		Type:           mm.Type,
-		Name:           mm.Name,
-		Interval:       mm.Interval,
-		Timeout:        mm.Timeout,
-		MaxRetries:     mm.MaxRetries,
-		RetryInterval:  mm.RetryInterval,
-		ResendInterval: mm.ResendInterval,
-		Active:         mm.Active,
-		Status:         mm.Status,
-		Config:         mm.Config,
-		ProxyId:        proxyId,
-		PushToken:      mm.PushToken,
-		CreatedAt:      mm.CreatedAt,
-		UpdatedAt:      mm.UpdatedAt,
+		ID:                mm.ID.Hex(),
+		Type:              mm.Type,
+		Name:              mm.Name,
+		Interval:          mm.Interval,
+		Timeout:           mm.Timeout,
+		MaxRetries:        mm.MaxRetries,
+		RetryInterval:     mm.RetryInterval,
+		ResendInterval:    mm.ResendInterval,
+		DegradedThreshold: mm.DegradedThreshold,
+		DegradedBreaches:  mm.DegradedBreaches,
+		FlapThreshold:     mm.FlapThreshold,
+		FlapWindow:        mm.FlapWindow,
+		Active:            mm.Active,
+		Status:            mm.Status,
+		Config:            mm.Config,
+		ProxyId:           proxyId,
+		PushToken:         mm.PushToken,
+		CreatedAt:         mm.CreatedAt,
+		UpdatedAt:         mm.UpdatedAt,
 	}
 }
 
@@ -131,21 +143,25 @@ func (r *MonitorRepositoryImpl) Create(ctx context.Context, monitor *Model) (*Mo
 	}
 
 	mm := &mongoModel{
-		ID:             primitive.NewObjectID(),
-		Type:           monitor.Type,
-		Name:           monitor.Name,
-		Interval:       monitor.Interval,
-		Timeout:        monitor.Timeout,
-		MaxRetries:     monitor.MaxRetries,
-		RetryInterval:  monitor.RetryInterval,
-		ResendInterval: monitor.ResendInterval,
-		Active:         monitor.Active,
-		Status:         0,
-		CreatedAt:      time.Now().UTC(),
-		UpdatedAt:      time.Now().UTC(),
-		Config:         monitor.Config,
-		ProxyId:        proxyObjectID,
-		PushToken:      monitor.PushToken,
+		ID:                primitive.NewObjectID(),
+		Type:              monitor.Type,
+		Name:              monitor.Name,
+		Interval:          monitor.Interval,
+		Timeout:           monitor.Timeout,
+		MaxRetries:        monitor.MaxRetries,
+		RetryInterval:     monitor.RetryInterval,
+		ResendInterval:    monitor.ResendInterval,
+		DegradedThreshold: monitor.DegradedThreshold,
+		DegradedBreaches:  monitor.DegradedBreaches,
+		FlapThreshold:     monitor.FlapThreshold,
+		FlapWindow:        monitor.FlapWindow,
+		Active:            monitor.Active,
+		Status:            0,
+		CreatedAt:         time.Now().UTC(),
+		UpdatedAt:         time.Now().UTC(),
+		Config:            monitor.Config,
+		ProxyId:           proxyObjectID,
+		PushToken:         monitor.PushToken,
 	}
 
 	_, err := r.collection.InsertOne(ctx, mm)
@@ -311,18 +327,22 @@ func (r *MonitorRepositoryImpl) FindAll(
 
 func buildSetMapFromModel(m *Model, includeProxyId bool, proxyObjectID primitive.ObjectID) bson.M {
 	set := bson.M{
-		"type":            m.Type,
-		"name":            m.Name,
-		"interval":        m.Interval,
-		"timeout":         m.Timeout,
-		"max_retries":     m.MaxRetries,
-		"retry_interval":  m.RetryInterval,
-		"resend_interval": m.ResendInterval,
-		"active":          m.Active,
-		"status":          0, // or m.Status if available
-		"created_at":      time.Now().UTC(),
-		"updated_at":      time.Now().UTC(),
-		"config":          m.Config,
+		"type":               m.Type,
+		"name":               m.Name,
+		"interval":           m.Interval,
+		"timeout":            m.Timeout,
+		"max_retries":        m.MaxRetries,
+		"retry_interval":     m.RetryInterval,
+		"resend_interval":    m.ResendInterval,
+		"degraded_threshold": m.DegradedThreshold,
+		"degraded_breaches":  m.DegradedBreaches,
+		"flap_threshold":     m.FlapThreshold,
+		"flap_window":        m.FlapWindow,
+		"active":             m.Active,
+		"status":             0, // or m.Status if available
+		"created_at":         time.Now().UTC(),
+		"updated_at":         time.Now().UTC(),
+		"config":             m.Config,
 	}
 	if includeProxyId {
 		set["proxy_id"] = proxyObjectID
@@ -353,6 +373,18 @@ func buildSetMapFromUpdateModel(mu *mongoUpdateModel, includeProxyId bool, proxy
 	if mu.ResendInterval != nil {
 		set["resend_interval"] = *mu.ResendInterval
 	}
+	if mu.DegradedThreshold != nil {
+		set["degraded_threshold"] = *mu.DegradedThreshold
+	}
+	if mu.DegradedBreaches != nil {
+		set["degraded_breaches"] = *mu.DegradedBreaches
+	}
+	if mu.FlapThreshold != nil {
+		set["flap_threshold"] = *mu.FlapThreshold
+	}
+	if mu.FlapWindow != nil {
+		set["flap_window"] = *mu.FlapWindow
+	}
 	if mu.Active != nil {
 		set["active"] = *mu.Active
 	}
@@ -417,20 +449,24 @@ func (r *MonitorRepositoryImpl) UpdatePartial(ctx context.Context, id string, mo
 	}
 
 	mu := &mongoUpdateModel{
-		Type:           monitor.Type,
-		Name:           monitor.Name,
-		Interval:       monitor.Interval,
-		Timeout:        monitor.Timeout,
-		MaxRetries:     monitor.MaxRetries,
-		RetryInterval:  monitor.RetryInterval,
-		ResendInterval: monitor.ResendInterval,
-		Active:         monitor.Active,
-		Status:         monitor.Status,
-		CreatedAt:      monitor.CreatedAt,
-		UpdatedAt:      monitor.UpdatedAt,
-		Config:         monitor.Config,
-		ProxyId:        proxyObjectID,
-		PushToken:      monitor.PushToken,
+		Type:              monitor.Type,
+		Name:              monitor.Name,
+		Interval:          monitor.Interval,
+		Timeout:           monitor.Timeout,
+		MaxRetries:        monitor.MaxRetries,
+		RetryInterval:     monitor.RetryInterval,
+		ResendInterval:    monitor.ResendInterval,
+		DegradedThreshold: monitor.DegradedThreshold,
+		DegradedBreaches:  monitor.DegradedBreaches,
+		FlapThreshold:     monitor.FlapThreshold,
+		FlapWindow:        monitor.FlapWindow,
+		Active:            monitor.Active,
+		Status:            monitor.Status,
+		CreatedAt:         monitor.CreatedAt,
+		UpdatedAt:         monitor.UpdatedAt,
+		Config:            monitor.Config,
+		ProxyId:           proxyObjectID,
+		PushToken:         monitor.PushToken,
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(id)
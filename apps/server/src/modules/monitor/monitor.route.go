@@ -40,4 +40,7 @@ func (uc *MonitorRoute) ConnectRoute(
 	router.GET(":id/stats/uptime", uc.monitorController.GetUptimeStats)
 	router.GET(":id/stats/points", uc.monitorController.GetStatPoints)
 	router.GET(":id/tls", uc.monitorController.GetTLSInfo)
+	router.GET(":id/dependencies", uc.monitorController.GetDependencies)
+	router.PUT(":id/dependencies", uc.monitorController.UpdateDependencies)
+	router.GET(":id/flaps", uc.monitorController.GetFlapPeriods)
 }
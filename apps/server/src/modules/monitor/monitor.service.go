@@ -42,6 +42,7 @@ type StatPoint struct {
 	Up          int     `json:"up"`
 	Down        int     `json:"down"`
 	Maintenance int     `json:"maintenance"`
+	Degraded    int     `json:"degraded"`
 	Ping        float64 `json:"ping"`
 	PingMin     float64 `json:"ping_min"`
 	PingMax     float64 `json:"ping_max"`
@@ -83,19 +84,23 @@ func NewMonitorService(
 
 func (mr *MonitorServiceImpl) Create(ctx context.Context, monitorCreateDto *CreateUpdateDto) (*Model, error) {
 	createModel := &Model{
-		Type:           monitorCreateDto.Type,
-		Name:           monitorCreateDto.Name,
-		Interval:       monitorCreateDto.Interval,
-		Timeout:        monitorCreateDto.Timeout,
-		MaxRetries:     monitorCreateDto.MaxRetries,
-		RetryInterval:  monitorCreateDto.RetryInterval,
-		ResendInterval: monitorCreateDto.ResendInterval,
-		Active:         monitorCreateDto.Active,
-		Status:         shared.MonitorStatusUp,
-		CreatedAt:      time.Now().UTC(),
-		Config:         monitorCreateDto.Config,
-		ProxyId:        monitorCreateDto.ProxyId,
-		PushToken:      monitorCreateDto.PushToken,
+		Type:              monitorCreateDto.Type,
+		Name:              monitorCreateDto.Name,
+		Interval:          monitorCreateDto.Interval,
+		Timeout:           monitorCreateDto.Timeout,
+		MaxRetries:        monitorCreateDto.MaxRetries,
+		RetryInterval:     monitorCreateDto.RetryInterval,
+		ResendInterval:    monitorCreateDto.ResendInterval,
+		DegradedThreshold: monitorCreateDto.DegradedThreshold,
+		DegradedBreaches:  monitorCreateDto.DegradedBreaches,
+		FlapThreshold:     monitorCreateDto.FlapThreshold,
+		FlapWindow:        monitorCreateDto.FlapWindow,
+		Active:            monitorCreateDto.Active,
+		Status:            shared.MonitorStatusUp,
+		CreatedAt:         time.Now().UTC(),
+		Config:            monitorCreateDto.Config,
+		ProxyId:           monitorCreateDto.ProxyId,
+		PushToken:         monitorCreateDto.PushToken,
 	}
 
 	createdModel, err := mr.monitorRepository.Create(ctx, createModel)
@@ -135,20 +140,24 @@ func (mr *MonitorServiceImpl) FindActive(ctx context.Context) ([]*Model, error)
 
 func (mr *MonitorServiceImpl) UpdateFull(ctx context.Context, id string, monitor *CreateUpdateDto) (*Model, error) {
 	model := &Model{
-		ID:             id,
-		Name:           monitor.Name,
-		Type:           monitor.Type,
-		Interval:       monitor.Interval,
-		Timeout:        monitor.Timeout,
-		MaxRetries:     monitor.MaxRetries,
-		RetryInterval:  monitor.RetryInterval,
-		ResendInterval: monitor.ResendInterval,
-		Active:         monitor.Active,
-		Status:         shared.MonitorStatusUp,
-		UpdatedAt:      time.Now().UTC(),
-		Config:         monitor.Config,
-		ProxyId:        monitor.ProxyId,
-		PushToken:      monitor.PushToken,
+		ID:                id,
+		Name:              monitor.Name,
+		Type:              monitor.Type,
+		Interval:          monitor.Interval,
+		Timeout:           monitor.Timeout,
+		MaxRetries:        monitor.MaxRetries,
+		RetryInterval:     monitor.RetryInterval,
+		ResendInterval:    monitor.ResendInterval,
+		DegradedThreshold: monitor.DegradedThreshold,
+		DegradedBreaches:  monitor.DegradedBreaches,
+		FlapThreshold:     monitor.FlapThreshold,
+		FlapWindow:        monitor.FlapWindow,
+		Active:            monitor.Active,
+		Status:            shared.MonitorStatusUp,
+		UpdatedAt:         time.Now().UTC(),
+		Config:            monitor.Config,
+		ProxyId:           monitor.ProxyId,
+		PushToken:         monitor.PushToken,
 	}
 
 	err := mr.monitorRepository.UpdateFull(ctx, id, model)
@@ -167,16 +176,25 @@ func (mr *MonitorServiceImpl) UpdateFull(ctx context.Context, id string, monitor
 
 func (mr *MonitorServiceImpl) UpdatePartial(ctx context.Context, id string, monitor *PartialUpdateDto, noPublish bool) (*Model, error) {
 	model := &UpdateModel{
-		ID:             &id,
-		Type:           monitor.Type,
-		Name:           monitor.Name,
-		Interval:       monitor.Interval,
-		Timeout:        monitor.Timeout,
-		MaxRetries:     monitor.MaxRetries,
-		RetryInterval:  monitor.RetryInterval,
-		ResendInterval: monitor.ResendInterval,
-		Active:         monitor.Active,
-		Status:         monitor.Status,
+		ID:                &id,
+		Type:              monitor.Type,
+		Name:              monitor.Name,
+		Interval:          monitor.Interval,
+		Timeout:           monitor.Timeout,
+		MaxRetries:        monitor.MaxRetries,
+		RetryInterval:     monitor.RetryInterval,
+		ResendInterval:    monitor.ResendInterval,
+		DegradedThreshold: monitor.DegradedThreshold,
+		DegradedBreaches:  monitor.DegradedBreaches,
+		FlapThreshold:     monitor.FlapThreshold,
+		FlapWindow:        monitor.FlapWindow,
+		Active:            monitor.Active,
+		Status:            monitor.Status,
+	}
+	// Status updates from heartbeats do not change the settings of the monitor
+	if *model != (UpdateModel{ID: model.ID, Status: model.Status}) {
+		now := time.Now().UTC()
+		model.UpdatedAt = &now
 	}
 
 	err := mr.monitorRepository.UpdatePartial(ctx, id, model)
@@ -277,6 +295,7 @@ func (mr *MonitorServiceImpl) GetStatPoints(ctx context.Context, id string, sinc
 			Up:          s.Up,
 			Down:        s.Down,
 			Maintenance: s.Maintenance,
+			Degraded:    s.Degraded,
 			Ping:        s.Ping,
 			PingMin:     s.PingMin,
 			PingMax:     s.PingMax,
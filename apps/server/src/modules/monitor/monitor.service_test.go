@@ -271,7 +271,7 @@ func setupMonitorService() (*MonitorServiceImpl, *MockMonitorRepository, *MockHe
 	realEventBus := events.NewEventBus(logger)
 
 	// Create a real ExecutorRegistry since the service expects a pointer to ExecutorRegistry
-	realExecutorRegistry := executor.NewExecutorRegistry(logger, mockHeartbeatService)
+	realExecutorRegistry := executor.NewExecutorRegistry(logger, mockHeartbeatService, nil)
 
 	service := NewMonitorService(
 		mockRepo,
@@ -465,7 +465,8 @@ func TestMonitorService_UpdatePartial(t *testing.T) {
 		mockRepo.On("UpdatePartial", ctx, monitorID, mock.MatchedBy(func(m *UpdateModel) bool {
 			return *m.ID == monitorID &&
 				*m.Active == active &&
-				*m.Status == status
+				*m.Status == status &&
+				m.UpdatedAt != nil
 		})).Return(nil)
 
 		mockRepo.On("FindByID", ctx, monitorID).Return(expectedModel, nil)
@@ -502,6 +503,25 @@ func TestMonitorService_UpdatePartial(t *testing.T) {
 		// EventBus should not be called when noPublish is true
 	})
 
+	t.Run("status update keeps updated_at", func(t *testing.T) {
+		service, mockRepo, _, _, _, _, _, _ := setupMonitorService()
+		monitorID := "monitor123"
+		status := shared.MonitorStatusDown
+		updateDto := &PartialUpdateDto{
+			Status: &status,
+		}
+
+		mockRepo.On("UpdatePartial", ctx, monitorID, mock.MatchedBy(func(m *UpdateModel) bool {
+			return *m.Status == status && m.UpdatedAt == nil
+		})).Return(nil)
+		mockRepo.On("FindByID", ctx, monitorID).Return(&Model{ID: monitorID, Status: status}, nil)
+
+		_, err := service.UpdatePartial(ctx, monitorID, updateDto, true)
+
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
 	t.Run("update error", func(t *testing.T) {
 		service, mockRepo, _, _, _, _, _, _ := setupMonitorService()
 		monitorID := "monitor123"
@@ -1192,7 +1212,7 @@ func TestNewMonitorService(t *testing.T) {
 
 	// Create real instances for dependencies that expect concrete types
 	realEventBus := events.NewEventBus(logger)
-	realExecutorRegistry := executor.NewExecutorRegistry(logger, mockHeartbeatService)
+	realExecutorRegistry := executor.NewExecutorRegistry(logger, mockHeartbeatService, nil)
 
 	service := NewMonitorService(
 		mockRepo,
@@ -13,21 +13,25 @@ import (
 type sqlModel struct {
 	bun.BaseModel `bun:"table:monitors,alias:m"`
 
-	ID             string               `bun:"id,pk"`
-	Type           string               `bun:"type,notnull"`
-	Name           string               `bun:"name,notnull"`
-	Interval       int                  `bun:"interval,notnull"`
-	Timeout        int                  `bun:"timeout,notnull"`
-	MaxRetries     int                  `bun:"max_retries,notnull"`
-	RetryInterval  int                  `bun:"retry_interval,notnull"`
-	ResendInterval int                  `bun:"resend_interval,notnull"`
-	Active         bool                 `bun:"active,notnull,default:true"`
-	Status         shared.MonitorStatus `bun:"status,notnull,default:0"`
-	CreatedAt      time.Time            `bun:"created_at,nullzero,notnull,default:current_timestamp"`
-	UpdatedAt      time.Time            `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
-	Config         string               `bun:"config"`
-	ProxyId        *string              `bun:"proxy_id"`
-	PushToken      string               `bun:"push_token"`
+	ID                string               `bun:"id,pk"`
+	Type              string               `bun:"type,notnull"`
+	Name              string               `bun:"name,notnull"`
+	Interval          int                  `bun:"interval,notnull"`
+	Timeout           int                  `bun:"timeout,notnull"`
+	MaxRetries        int                  `bun:"max_retries,notnull"`
+	RetryInterval     int                  `bun:"retry_interval,notnull"`
+	ResendInterval    int                  `bun:"resend_interval,notnull"`
+	DegradedThreshold int                  `bun:"degraded_threshold,notnull,default:0"`
+	DegradedBreaches  int                  `bun:"degraded_breaches,notnull,default:0"`
+	FlapThreshold     int                  `bun:"flap_threshold,notnull,default:0"`
+	FlapWindow        int                  `bun:"flap_window,notnull,default:0"`
+	Active            bool                 `bun:"active,notnull,default:true"`
+	Status            shared.MonitorStatus `bun:"status,notnull,default:0"`
+	CreatedAt         time.Time            `bun:"created_at,nullzero,notnull,default:current_timestamp"`
+	UpdatedAt         time.Time            `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
+	Config            string               `bun:"config"`
+	ProxyId           *string              `bun:"proxy_id"`
+	PushToken         string               `bun:"push_token"`
 }
 
 func toDomainModelFromSQL(sm *sqlModel) *Model {
@@ -38,21 +42,25 @@ func toDomainModelFromSQL(sm *sqlModel) *Model {
 	}
 
 	return &Model{
-		ID:             sm.ID,
-		Type:           sm.Type,
-		Name:           sm.Name,
-		Interval:       sm.Interval,
-		Timeout:        sm.Timeout,
-		MaxRetries:     sm.MaxRetries,
-		RetryInterval:  sm.RetryInterval,
-		ResendInterval: sm.ResendInterval,
-		Active:         sm.Active,
-		Status:         sm.Status,
-		CreatedAt:      sm.CreatedAt,
-		UpdatedAt:      sm.UpdatedAt,
-		Config:         sm.Config,
-		ProxyId:        proxyId,
-		PushToken:      sm.PushToken,
+		ID:                sm.ID,
+		Type:              sm.Type,
+		Name:              sm.Name,
+		Interval:          sm.Interval,
+		Timeout:           sm.Timeout,
+		MaxRetries:        sm.MaxRetries,
+		RetryInterval:     sm.RetryInterval,
+		ResendInterval:    sm.ResendInterval,
+		DegradedThreshold: sm.DegradedThreshold,
+		DegradedBreaches:  sm.DegradedBreaches,
+		FlapThreshold:     sm.FlapThreshold,
+		FlapWindow:        sm.FlapWindow,
+		Active:            sm.Active,
+		Status:            sm.Status,
+		CreatedAt:         sm.CreatedAt,
+		UpdatedAt:         sm.UpdatedAt,
+		Config:            sm.Config,
+		ProxyId:           proxyId,
+		PushToken:         sm.PushToken,
 	}
 }
 
@@ -64,21 +72,25 @@ func toSQLModel(m *Model) *sqlModel {
 	}
 
 	return &sqlModel{
-		ID:             m.ID,
-		Type:           m.Type,
-		Name:           m.Name,
-		Interval:       m.Interval,
-		Timeout:        m.Timeout,
-		MaxRetries:     m.MaxRetries,
-		RetryInterval:  m.RetryInterval,
-		ResendInterval: m.ResendInterval,
-		Active:         m.Active,
-		Status:         m.Status,
-		CreatedAt:      m.CreatedAt,
-		UpdatedAt:      m.UpdatedAt,
-		Config:         m.Config,
-		ProxyId:        proxyId,
-		PushToken:      m.PushToken,
+		ID:                m.ID,
+		Type:              m.Type,
+		Name:              m.Name,
+		Interval:          m.Interval,
+		Timeout:           m.Timeout,
+		MaxRetries:        m.MaxRetries,
+		RetryInterval:     m.RetryInterval,
+		ResendInterval:    m.ResendInterval,
+		DegradedThreshold: m.DegradedThreshold,
+		DegradedBreaches:  m.DegradedBreaches,
+		FlapThreshold:     m.FlapThreshold,
+		FlapWindow:        m.FlapWindow,
+		Active:            m.Active,
+		Status:            m.Status,
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
+		Config:            m.Config,
+		ProxyId:           proxyId,
+		PushToken:         m.PushToken,
 	}
 }
 
@@ -250,6 +262,22 @@ func (r *SQLRepositoryImpl) UpdatePartial(ctx context.Context, id string, monito
 		query = query.Set("resend_interval = ?", *monitor.ResendInterval)
 		hasUpdates = true
 	}
+	if monitor.DegradedThreshold != nil {
+		query = query.Set("degraded_threshold = ?", *monitor.DegradedThreshold)
+		hasUpdates = true
+	}
+	if monitor.DegradedBreaches != nil {
+		query = query.Set("degraded_breaches = ?", *monitor.DegradedBreaches)
+		hasUpdates = true
+	}
+	if monitor.FlapThreshold != nil {
+		query = query.Set("flap_threshold = ?", *monitor.FlapThreshold)
+		hasUpdates = true
+	}
+	if monitor.FlapWindow != nil {
+		query = query.Set("flap_window = ?", *monitor.FlapWindow)
+		hasUpdates = true
+	}
 	if monitor.Active != nil {
 		query = query.Set("active = ?", *monitor.Active)
 		hasUpdates = true
@@ -280,8 +308,13 @@ func (r *SQLRepositoryImpl) UpdatePartial(ctx context.Context, id string, monito
 		return nil
 	}
 
-	// Always set updated_at
-	query = query.Set("updated_at = ?", time.Now())
+	// updated_at tracks changes of the settings, the node running the monitor restarts it when
+	// it moves. The status written back from heartbeats is not such a change.
+	if monitor.UpdatedAt != nil {
+		query = query.Set("updated_at = ?", *monitor.UpdatedAt)
+	} else if *monitor != (UpdateModel{ID: monitor.ID, Status: monitor.Status}) {
+		query = query.Set("updated_at = ?", time.Now())
+	}
 
 	_, err := query.Exec(ctx)
 	return err
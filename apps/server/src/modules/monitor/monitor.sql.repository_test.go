@@ -37,7 +37,11 @@ func setupTestDB(t *testing.T) *bun.DB {
 			updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			config TEXT,
 			proxy_id TEXT,
-			push_token TEXT
+			push_token TEXT,
+			degraded_threshold INTEGER NOT NULL DEFAULT 0,
+			degraded_breaches INTEGER NOT NULL DEFAULT 0,
+			flap_threshold INTEGER NOT NULL DEFAULT 0,
+			flap_window INTEGER NOT NULL DEFAULT 0
 		)
 	`)
 	require.NoError(t, err)
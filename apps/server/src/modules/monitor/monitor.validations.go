@@ -1,6 +1,9 @@
 package monitor
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -11,3 +14,46 @@ func CreateUpdateDtoStructLevelValidation(sl validator.StructLevel) {
 		sl.ReportError(cfg.Timeout, "Timeout", "timeout", "timeout", "")
 	}
 }
+
+// ValidateDependencies checks that monitorID can depend on parentIDs. Edges maps every
+// monitor to its current parents, the ones of monitorID are replaced by parentIDs.
+func ValidateDependencies(monitorID string, parentIDs []string, edges map[string][]string) error {
+	seen := make(map[string]bool, len(parentIDs))
+	for _, parentID := range parentIDs {
+		if parentID == monitorID {
+			return fmt.Errorf("monitor can not depend on itself")
+		}
+		if seen[parentID] {
+			return fmt.Errorf("duplicate parent monitor %s", parentID)
+		}
+		seen[parentID] = true
+	}
+
+	visited := make(map[string]bool)
+	var walk func(id string, path []string) []string
+	walk = func(id string, path []string) []string {
+		path = append(path, id)
+		if id == monitorID {
+			return path
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		for _, next := range edges[id] {
+			if cycle := walk(next, path); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	for _, parentID := range parentIDs {
+		if cycle := walk(parentID, []string{monitorID}); cycle != nil {
+			return fmt.Errorf("dependency cycle: %s", strings.Join(cycle, " -> "))
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package monitor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDependencies(t *testing.T) {
+	edges := map[string][]string{
+		"b": {"c"},
+		"c": {"d"},
+	}
+
+	tests := []struct {
+		name      string
+		monitorID string
+		parentIDs []string
+		wantErr   string
+	}{
+		{"no parents", "a", nil, ""},
+		{"chain", "a", []string{"b"}, ""},
+		{"self", "a", []string{"a"}, "itself"},
+		{"duplicate", "a", []string{"b", "b"}, "duplicate"},
+		{"direct cycle", "c", []string{"b"}, "dependency cycle: c -> b -> c"},
+		{"indirect cycle", "d", []string{"b"}, "dependency cycle: d -> b -> c -> d"},
+		{"replaced parents are ignored", "b", []string{"d"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDependencies(tt.monitorID, tt.parentIDs, edges)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
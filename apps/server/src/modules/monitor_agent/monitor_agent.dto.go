@@ -0,0 +1,6 @@
+package monitor_agent
+
+type CreateDto struct {
+	MonitorID string `json:"monitor_id"`
+	AgentID   string `json:"agent_id"`
+}
@@ -0,0 +1,11 @@
+package monitor_agent
+
+import "time"
+
+type Model struct {
+	ID        string    `json:"id"`
+	MonitorID string    `json:"monitor_id"`
+	AgentID   string    `json:"agent_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
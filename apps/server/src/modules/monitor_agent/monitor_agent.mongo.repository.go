@@ -0,0 +1,213 @@
+package monitor_agent
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	MonitorID primitive.ObjectID `bson:"monitor_id"`
+	AgentID   primitive.ObjectID `bson:"agent_id"`
+	CreatedAt time.Time          `bson:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		ID:        mm.ID.Hex(),
+		MonitorID: mm.MonitorID.Hex(),
+		AgentID:   mm.AgentID.Hex(),
+		CreatedAt: mm.CreatedAt,
+		UpdatedAt: mm.UpdatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("monitor_agents")
+
+	// Create a unique index for monitor_id and agent_id
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "monitor_id", Value: 1},
+			{Key: "agent_id", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+
+	if err != nil {
+		panic("Failed to create index for monitor_agents: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(model.MonitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	agentObjectID, err := primitive.ObjectIDFromHex(model.AgentID)
+	if err != nil {
+		return nil, err
+	}
+
+	mm := &mongoModel{
+		ID:        primitive.NewObjectID(),
+		MonitorID: monitorObjectID,
+		AgentID:   agentObjectID,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	_, err = r.collection.InsertOne(ctx, mm)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	var entity mongoModel
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objectID}
+	err = r.collection.FindOne(ctx, filter).Decode(&entity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&entity), nil
+}
+
+func (r *MongoRepositoryImpl) FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"monitor_id": monitorObjectID}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []*mongoModel
+	for cursor.Next(ctx) {
+		var entity mongoModel
+		if err := cursor.Decode(&entity); err != nil {
+			return nil, err
+		}
+		results = append(results, &entity)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	domainEntities := make([]*Model, len(results))
+	for i, entity := range results {
+		domainEntities[i] = toDomainModelFromMongo(entity)
+	}
+
+	return domainEntities, nil
+}
+
+func (r *MongoRepositoryImpl) FindByAgentID(ctx context.Context, agentID string) ([]*Model, error) {
+	agentObjectID, err := primitive.ObjectIDFromHex(agentID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"agent_id": agentObjectID}
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []*mongoModel
+	for cursor.Next(ctx) {
+		var entity mongoModel
+		if err := cursor.Decode(&entity); err != nil {
+			return nil, err
+		}
+		results = append(results, &entity)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	domainEntities := make([]*Model, len(results))
+	for i, entity := range results {
+		domainEntities[i] = toDomainModelFromMongo(entity)
+	}
+
+	return domainEntities, nil
+}
+
+func (r *MongoRepositoryImpl) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"_id": objectID}
+	_, err = r.collection.DeleteOne(ctx, filter)
+	return err
+}
+
+func (r *MongoRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"monitor_id": monitorObjectID}
+	_, err = r.collection.DeleteMany(ctx, filter)
+	return err
+}
+
+func (r *MongoRepositoryImpl) DeleteByAgentID(ctx context.Context, agentID string) error {
+	agentObjectID, err := primitive.ObjectIDFromHex(agentID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"agent_id": agentObjectID}
+	_, err = r.collection.DeleteMany(ctx, filter)
+	return err
+}
+
+func (r *MongoRepositoryImpl) DeleteByMonitorAndAgent(ctx context.Context, monitorID string, agentID string) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+	agentObjectID, err := primitive.ObjectIDFromHex(agentID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"monitor_id": monitorObjectID, "agent_id": agentObjectID}
+	_, err = r.collection.DeleteOne(ctx, filter)
+	return err
+}
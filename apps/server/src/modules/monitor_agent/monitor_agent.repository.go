@@ -0,0 +1,16 @@
+package monitor_agent
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error)
+	FindByAgentID(ctx context.Context, agentID string) ([]*Model, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+	DeleteByAgentID(ctx context.Context, agentID string) error
+	DeleteByMonitorAndAgent(ctx context.Context, monitorID string, agentID string) error
+}
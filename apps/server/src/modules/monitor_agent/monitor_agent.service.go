@@ -0,0 +1,70 @@
+package monitor_agent
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	Create(ctx context.Context, monitorID string, agentID string) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	Delete(ctx context.Context, id string) error
+	FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error)
+	FindByAgentID(ctx context.Context, agentID string) ([]*Model, error)
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+	DeleteByAgentID(ctx context.Context, agentID string) error
+	DeleteByMonitorAndAgent(ctx context.Context, monitorID string, agentID string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[monitor-agent-service]"),
+	}
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, monitorID string, agentID string) (*Model, error) {
+	createModel := &Model{
+		MonitorID: monitorID,
+		AgentID:   agentID,
+	}
+
+	return s.repository.Create(ctx, createModel)
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *ServiceImpl) FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error) {
+	return s.repository.FindByMonitorID(ctx, monitorID)
+}
+
+func (s *ServiceImpl) FindByAgentID(ctx context.Context, agentID string) ([]*Model, error) {
+	return s.repository.FindByAgentID(ctx, agentID)
+}
+
+func (s *ServiceImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return s.repository.DeleteByMonitorID(ctx, monitorID)
+}
+
+func (s *ServiceImpl) DeleteByAgentID(ctx context.Context, agentID string) error {
+	return s.repository.DeleteByAgentID(ctx, agentID)
+}
+
+func (s *ServiceImpl) DeleteByMonitorAndAgent(ctx context.Context, monitorID string, agentID string) error {
+	return s.repository.DeleteByMonitorAndAgent(ctx, monitorID, agentID)
+}
@@ -0,0 +1,6 @@
+package monitor_dependency
+
+type CreateDto struct {
+	MonitorID string `json:"monitor_id"`
+	ParentID  string `json:"parent_id"`
+}
@@ -0,0 +1,49 @@
+package monitor_dependency
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+// EventListener removes the dependencies of deleted monitors in both directions
+type EventListener struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+type EventListenerParams struct {
+	dig.In
+	Service Service
+	Logger  *zap.SugaredLogger
+}
+
+func NewEventListener(p EventListenerParams) *EventListener {
+	return &EventListener{
+		service: p.Service,
+		logger:  p.Logger.Named("[monitor-dependency-event-listener]"),
+	}
+}
+
+// Subscribe subscribes to MonitorDeleted events
+func (l *EventListener) Subscribe(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.MonitorDeleted, l.handleMonitorDeleted)
+}
+
+func (l *EventListener) handleMonitorDeleted(event events.Event) {
+	monitorID, ok := event.Payload.(string)
+	if !ok {
+		l.logger.Errorf("Invalid handleMonitorDeleted event payload type: %v", event.Payload)
+		return
+	}
+
+	ctx := context.Background()
+	if err := l.service.DeleteByMonitorID(ctx, monitorID); err != nil {
+		l.logger.Errorf("Failed to delete parents of monitor %s: %v", monitorID, err)
+	}
+	if err := l.service.DeleteByParentID(ctx, monitorID); err != nil {
+		l.logger.Errorf("Failed to delete children of monitor %s: %v", monitorID, err)
+	}
+}
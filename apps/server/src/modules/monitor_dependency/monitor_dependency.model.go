@@ -0,0 +1,11 @@
+package monitor_dependency
+
+import "time"
+
+type Model struct {
+	ID        string    `json:"id"`
+	MonitorID string    `json:"monitor_id"`
+	ParentID  string    `json:"parent_id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package monitor_dependency
+
+import (
+	"context"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error)
+	FindByParentID(ctx context.Context, parentID string) ([]*Model, error)
+	FindAll(ctx context.Context) ([]*Model, error)
+	Delete(ctx context.Context, id string) error
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+	DeleteByParentID(ctx context.Context, parentID string) error
+	DeleteByMonitorAndParent(ctx context.Context, monitorID string, parentID string) error
+}
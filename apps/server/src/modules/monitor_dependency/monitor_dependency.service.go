@@ -0,0 +1,76 @@
+package monitor_dependency
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	Create(ctx context.Context, monitorID string, parentID string) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	Delete(ctx context.Context, id string) error
+	FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error)
+	FindByParentID(ctx context.Context, parentID string) ([]*Model, error)
+	// FindAll returns every dependency, used to validate the dependency graph
+	FindAll(ctx context.Context) ([]*Model, error)
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+	DeleteByParentID(ctx context.Context, parentID string) error
+	DeleteByMonitorAndParent(ctx context.Context, monitorID string, parentID string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[monitor-dependency-service]"),
+	}
+}
+
+func (s *ServiceImpl) Create(ctx context.Context, monitorID string, parentID string) (*Model, error) {
+	createModel := &Model{
+		MonitorID: monitorID,
+		ParentID:  parentID,
+	}
+
+	return s.repository.Create(ctx, createModel)
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) Delete(ctx context.Context, id string) error {
+	return s.repository.Delete(ctx, id)
+}
+
+func (s *ServiceImpl) FindByMonitorID(ctx context.Context, monitorID string) ([]*Model, error) {
+	return s.repository.FindByMonitorID(ctx, monitorID)
+}
+
+func (s *ServiceImpl) FindByParentID(ctx context.Context, parentID string) ([]*Model, error) {
+	return s.repository.FindByParentID(ctx, parentID)
+}
+
+func (s *ServiceImpl) FindAll(ctx context.Context) ([]*Model, error) {
+	return s.repository.FindAll(ctx)
+}
+
+func (s *ServiceImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return s.repository.DeleteByMonitorID(ctx, monitorID)
+}
+
+func (s *ServiceImpl) DeleteByParentID(ctx context.Context, parentID string) error {
+	return s.repository.DeleteByParentID(ctx, parentID)
+}
+
+func (s *ServiceImpl) DeleteByMonitorAndParent(ctx context.Context, monitorID string, parentID string) error {
+	return s.repository.DeleteByMonitorAndParent(ctx, monitorID, parentID)
+}
@@ -0,0 +1,45 @@
+package monitor_flap
+
+import (
+	"context"
+	"peekaping/src/modules/events"
+
+	"go.uber.org/dig"
+	"go.uber.org/zap"
+)
+
+// EventListener removes the flapping periods of deleted monitors
+type EventListener struct {
+	service Service
+	logger  *zap.SugaredLogger
+}
+
+type EventListenerParams struct {
+	dig.In
+	Service Service
+	Logger  *zap.SugaredLogger
+}
+
+func NewEventListener(p EventListenerParams) *EventListener {
+	return &EventListener{
+		service: p.Service,
+		logger:  p.Logger.Named("[monitor-flap-event-listener]"),
+	}
+}
+
+// Subscribe subscribes to MonitorDeleted events
+func (l *EventListener) Subscribe(eventBus *events.EventBus) {
+	eventBus.Subscribe(events.MonitorDeleted, l.handleMonitorDeleted)
+}
+
+func (l *EventListener) handleMonitorDeleted(event events.Event) {
+	monitorID, ok := event.Payload.(string)
+	if !ok {
+		l.logger.Errorf("Invalid handleMonitorDeleted event payload type: %v", event.Payload)
+		return
+	}
+
+	if err := l.service.DeleteByMonitorID(context.Background(), monitorID); err != nil {
+		l.logger.Errorf("Failed to delete flapping periods of monitor %s: %v", monitorID, err)
+	}
+}
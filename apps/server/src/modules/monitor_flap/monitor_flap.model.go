@@ -0,0 +1,27 @@
+package monitor_flap
+
+import (
+	"peekaping/src/modules/heartbeat"
+	"time"
+)
+
+// Model is a period during which a monitor was flapping, EndedAt is nil while it lasts
+type Model struct {
+	ID          string     `json:"id"`
+	MonitorID   string     `json:"monitor_id"`
+	StartedAt   time.Time  `json:"started_at"`
+	EndedAt     *time.Time `json:"ended_at"`
+	Transitions int        `json:"transitions"` // status changes in the window when flapping started
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// FlappingEvent is published when a monitor starts or stops flapping
+type FlappingEvent struct {
+	MonitorID   string
+	MonitorName string
+	Started     bool
+	Transitions int
+	Window      int
+	Heartbeat   *heartbeat.Model
+}
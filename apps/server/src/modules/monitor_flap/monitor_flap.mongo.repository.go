@@ -0,0 +1,176 @@
+package monitor_flap
+
+import (
+	"context"
+	"errors"
+	"peekaping/src/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	MonitorID   primitive.ObjectID `bson:"monitor_id"`
+	StartedAt   time.Time          `bson:"started_at"`
+	EndedAt     *time.Time         `bson:"ended_at"`
+	Transitions int                `bson:"transitions"`
+	CreatedAt   time.Time          `bson:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		ID:          mm.ID.Hex(),
+		MonitorID:   mm.MonitorID.Hex(),
+		StartedAt:   mm.StartedAt,
+		EndedAt:     mm.EndedAt,
+		Transitions: mm.Transitions,
+		CreatedAt:   mm.CreatedAt,
+		UpdatedAt:   mm.UpdatedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("monitor_flaps")
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "monitor_id", Value: 1},
+			{Key: "started_at", Value: -1},
+		},
+	})
+
+	if err != nil {
+		panic("Failed to create index for monitor_flaps: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Create(ctx context.Context, model *Model) (*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(model.MonitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	mm := &mongoModel{
+		ID:          primitive.NewObjectID(),
+		MonitorID:   monitorObjectID,
+		StartedAt:   model.StartedAt,
+		EndedAt:     model.EndedAt,
+		Transitions: model.Transitions,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+
+	_, err = r.collection.InsertOne(ctx, mm)
+	if err != nil {
+		return nil, err
+	}
+
+	return toDomainModelFromMongo(mm), nil
+}
+
+func (r *MongoRepositoryImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	var entity mongoModel
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"_id": objectID}
+	err = r.collection.FindOne(ctx, filter).Decode(&entity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&entity), nil
+}
+
+func (r *MongoRepositoryImpl) FindOpenByMonitorID(ctx context.Context, monitorID string) (*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	var entity mongoModel
+	filter := bson.M{"monitor_id": monitorObjectID, "ended_at": nil}
+	opts := options.FindOne().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	err = r.collection.FindOne(ctx, filter, opts).Decode(&entity)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return toDomainModelFromMongo(&entity), nil
+}
+
+func (r *MongoRepositoryImpl) FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"monitor_id": monitorObjectID,
+		"$or": bson.A{
+			bson.M{"ended_at": nil},
+			bson.M{"ended_at": bson.M{"$gte": since}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "started_at", Value: -1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	domainEntities := make([]*Model, 0)
+	for cursor.Next(ctx) {
+		var entity mongoModel
+		if err := cursor.Decode(&entity); err != nil {
+			return nil, err
+		}
+		domainEntities = append(domainEntities, toDomainModelFromMongo(&entity))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return domainEntities, nil
+}
+
+func (r *MongoRepositoryImpl) SetEndedAt(ctx context.Context, id string, endedAt time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{"ended_at": endedAt, "updated_at": time.Now().UTC()}}
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
+
+func (r *MongoRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"monitor_id": monitorObjectID}
+	_, err = r.collection.DeleteMany(ctx, filter)
+	return err
+}
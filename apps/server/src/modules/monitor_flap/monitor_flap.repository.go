@@ -0,0 +1,15 @@
+package monitor_flap
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	Create(ctx context.Context, model *Model) (*Model, error)
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindOpenByMonitorID(ctx context.Context, monitorID string) (*Model, error)
+	FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error)
+	SetEndedAt(ctx context.Context, id string, endedAt time.Time) error
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
@@ -0,0 +1,78 @@
+package monitor_flap
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	// Start records the beginning of a flapping period
+	Start(ctx context.Context, monitorID string, startedAt time.Time, transitions int) (*Model, error)
+	// End closes the open flapping period of the monitor, if any
+	End(ctx context.Context, monitorID string, endedAt time.Time) error
+	FindByID(ctx context.Context, id string) (*Model, error)
+	FindOpenByMonitorID(ctx context.Context, monitorID string) (*Model, error)
+	// FindByMonitorID returns the periods that were still flapping at or after since, newest first
+	FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error)
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[monitor-flap-service]"),
+	}
+}
+
+func (s *ServiceImpl) Start(ctx context.Context, monitorID string, startedAt time.Time, transitions int) (*Model, error) {
+	// A period left open by a crash is closed before a new one starts
+	if err := s.End(ctx, monitorID, startedAt); err != nil {
+		return nil, err
+	}
+
+	createModel := &Model{
+		MonitorID:   monitorID,
+		StartedAt:   startedAt,
+		Transitions: transitions,
+	}
+
+	return s.repository.Create(ctx, createModel)
+}
+
+func (s *ServiceImpl) End(ctx context.Context, monitorID string, endedAt time.Time) error {
+	open, err := s.repository.FindOpenByMonitorID(ctx, monitorID)
+	if err != nil {
+		return err
+	}
+	if open == nil {
+		return nil
+	}
+
+	return s.repository.SetEndedAt(ctx, open.ID, endedAt)
+}
+
+func (s *ServiceImpl) FindByID(ctx context.Context, id string) (*Model, error) {
+	return s.repository.FindByID(ctx, id)
+}
+
+func (s *ServiceImpl) FindOpenByMonitorID(ctx context.Context, monitorID string) (*Model, error) {
+	return s.repository.FindOpenByMonitorID(ctx, monitorID)
+}
+
+func (s *ServiceImpl) FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error) {
+	return s.repository.FindByMonitorID(ctx, monitorID, since)
+}
+
+func (s *ServiceImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return s.repository.DeleteByMonitorID(ctx, monitorID)
+}
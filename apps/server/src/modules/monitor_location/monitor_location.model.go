@@ -0,0 +1,16 @@
+package monitor_location
+
+import (
+	"peekaping/src/modules/shared"
+	"time"
+)
+
+// Model is the latest result a location reported for a monitor. It is shared by all replicas
+// so the down quorum sees every location, whichever replica received the result.
+type Model struct {
+	MonitorID  string               `json:"monitor_id"`
+	Location   string               `json:"location"`
+	Status     shared.MonitorStatus `json:"status"`
+	Message    string               `json:"message"`
+	ReportedAt time.Time            `json:"reported_at"`
+}
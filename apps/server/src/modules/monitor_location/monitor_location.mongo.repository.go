@@ -0,0 +1,117 @@
+package monitor_location
+
+import (
+	"context"
+	"peekaping/src/config"
+	"peekaping/src/modules/shared"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoModel struct {
+	MonitorID  primitive.ObjectID   `bson:"monitor_id"`
+	Location   string               `bson:"location"`
+	Status     shared.MonitorStatus `bson:"status"`
+	Message    string               `bson:"message"`
+	ReportedAt time.Time            `bson:"reported_at"`
+}
+
+func toDomainModelFromMongo(mm *mongoModel) *Model {
+	return &Model{
+		MonitorID:  mm.MonitorID.Hex(),
+		Location:   mm.Location,
+		Status:     mm.Status,
+		Message:    mm.Message,
+		ReportedAt: mm.ReportedAt,
+	}
+}
+
+type MongoRepositoryImpl struct {
+	client     *mongo.Client
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+func NewMongoRepository(client *mongo.Client, cfg *config.Config) Repository {
+	db := client.Database(cfg.DBName)
+	collection := db.Collection("monitor_locations")
+
+	_, err := collection.Indexes().CreateOne(context.TODO(), mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "monitor_id", Value: 1},
+			{Key: "location", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+
+	if err != nil {
+		panic("Failed to create index for monitor_locations: " + err.Error())
+	}
+
+	return &MongoRepositoryImpl{client, db, collection}
+}
+
+func (r *MongoRepositoryImpl) Upsert(ctx context.Context, model *Model) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(model.MonitorID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{"monitor_id": monitorObjectID, "location": model.Location}
+	update := bson.M{
+		"$set": bson.M{
+			"status":      model.Status,
+			"message":     model.Message,
+			"reported_at": model.ReportedAt,
+		},
+	}
+
+	_, err = r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (r *MongoRepositoryImpl) FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error) {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"monitor_id":  monitorObjectID,
+		"reported_at": bson.M{"$gte": since},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "location", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	domainEntities := make([]*Model, 0)
+	for cursor.Next(ctx) {
+		var entity mongoModel
+		if err := cursor.Decode(&entity); err != nil {
+			return nil, err
+		}
+		domainEntities = append(domainEntities, toDomainModelFromMongo(&entity))
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return domainEntities, nil
+}
+
+func (r *MongoRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	monitorObjectID, err := primitive.ObjectIDFromHex(monitorID)
+	if err != nil {
+		return err
+	}
+	filter := bson.M{"monitor_id": monitorObjectID}
+	_, err = r.collection.DeleteMany(ctx, filter)
+	return err
+}
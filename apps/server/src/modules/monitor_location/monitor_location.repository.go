@@ -0,0 +1,14 @@
+package monitor_location
+
+import (
+	"context"
+	"time"
+)
+
+type Repository interface {
+	// Upsert replaces the result stored for the monitor and location
+	Upsert(ctx context.Context, model *Model) error
+	// FindByMonitorID returns the results of the monitor reported at or after since
+	FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error)
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
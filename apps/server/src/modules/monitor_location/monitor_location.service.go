@@ -0,0 +1,43 @@
+package monitor_location
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Service interface {
+	// Report stores the latest result of the monitor from a location
+	Report(ctx context.Context, model *Model) error
+	// FindByMonitorID returns the latest result of every location that reported at or after since
+	FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error)
+	DeleteByMonitorID(ctx context.Context, monitorID string) error
+}
+
+type ServiceImpl struct {
+	repository Repository
+	logger     *zap.SugaredLogger
+}
+
+func NewService(
+	repository Repository,
+	logger *zap.SugaredLogger,
+) Service {
+	return &ServiceImpl{
+		repository,
+		logger.Named("[monitor-location-service]"),
+	}
+}
+
+func (s *ServiceImpl) Report(ctx context.Context, model *Model) error {
+	return s.repository.Upsert(ctx, model)
+}
+
+func (s *ServiceImpl) FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error) {
+	return s.repository.FindByMonitorID(ctx, monitorID, since)
+}
+
+func (s *ServiceImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	return s.repository.DeleteByMonitorID(ctx, monitorID)
+}
@@ -0,0 +1,104 @@
+package monitor_location
+
+import (
+	"context"
+	"peekaping/src/modules/shared"
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+type sqlModel struct {
+	bun.BaseModel `bun:"table:monitor_locations,alias:mloc"`
+
+	MonitorID  string               `bun:"monitor_id,pk"`
+	Location   string               `bun:"location,pk"`
+	Status     shared.MonitorStatus `bun:"status,notnull"`
+	Message    string               `bun:"message"`
+	ReportedAt time.Time            `bun:"reported_at,notnull"`
+}
+
+func toDomainModelFromSQL(sm *sqlModel) *Model {
+	return &Model{
+		MonitorID:  sm.MonitorID,
+		Location:   sm.Location,
+		Status:     sm.Status,
+		Message:    sm.Message,
+		ReportedAt: sm.ReportedAt,
+	}
+}
+
+type SQLRepositoryImpl struct {
+	db *bun.DB
+}
+
+func NewSQLRepository(db *bun.DB) Repository {
+	return &SQLRepositoryImpl{db: db}
+}
+
+func (r *SQLRepositoryImpl) update(ctx context.Context, model *Model) (bool, error) {
+	res, err := r.db.NewUpdate().
+		Model((*sqlModel)(nil)).
+		Set("status = ?", model.Status).
+		Set("message = ?", model.Message).
+		Set("reported_at = ?", model.ReportedAt).
+		Where("monitor_id = ?", model.MonitorID).
+		Where("location = ?", model.Location).
+		Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	affected, _ := res.RowsAffected()
+	return affected > 0, nil
+}
+
+func (r *SQLRepositoryImpl) Upsert(ctx context.Context, model *Model) error {
+	// Update the existing row or insert the first result of the location, the update is
+	// retried when another replica inserted the row concurrently
+	updated, err := r.update(ctx, model)
+	if err != nil || updated {
+		return err
+	}
+
+	sm := &sqlModel{
+		MonitorID:  model.MonitorID,
+		Location:   model.Location,
+		Status:     model.Status,
+		Message:    model.Message,
+		ReportedAt: model.ReportedAt,
+	}
+	if _, insertErr := r.db.NewInsert().Model(sm).Exec(ctx); insertErr != nil {
+		updated, err = r.update(ctx, model)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return insertErr
+		}
+	}
+	return nil
+}
+
+func (r *SQLRepositoryImpl) FindByMonitorID(ctx context.Context, monitorID string, since time.Time) ([]*Model, error) {
+	var sms []*sqlModel
+	err := r.db.NewSelect().
+		Model(&sms).
+		Where("monitor_id = ?", monitorID).
+		Where("reported_at >= ?", since).
+		Order("location ASC").
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	models := make([]*Model, 0, len(sms))
+	for _, sm := range sms {
+		models = append(models, toDomainModelFromSQL(sm))
+	}
+	return models, nil
+}
+
+func (r *SQLRepositoryImpl) DeleteByMonitorID(ctx context.Context, monitorID string) error {
+	_, err := r.db.NewDelete().Model((*sqlModel)(nil)).Where("monitor_id = ?", monitorID).Exec(ctx)
+	return err
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"peekaping/src/config"
 	"peekaping/src/modules/certificate"
+	"peekaping/src/modules/domain_expiry"
 	"peekaping/src/modules/events"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_flap"
 	"peekaping/src/modules/monitor_notification"
 	"peekaping/src/modules/notification_channel/providers"
 	"strings"
@@ -72,124 +74,93 @@ func NewNotificationEventListener(p NotificationEventListenerParams) *Notificati
 func (l *NotificationEventListener) Subscribe(eventBus *events.EventBus) {
 	eventBus.Subscribe(events.ImportantHeartbeat, l.handleNotifyEvent)
 	eventBus.Subscribe(events.CertificateExpiry, l.handleCertificateExpiryEvent)
+	eventBus.Subscribe(events.DomainExpiry, l.handleDomainExpiryEvent)
+	eventBus.Subscribe(events.MonitorFlapping, l.handleFlappingEvent)
 }
 
 func (l *NotificationEventListener) handleNotifyEvent(event events.Event) {
-	ctx := context.Background()
-
 	hb, ok := event.Payload.(*heartbeat.Model)
 	if !ok {
 		l.logger.Errorf("Invalid handleNotifyEvent event payload type: %v", event.Payload)
 		return
 	}
 
-	monitorID := hb.MonitorID
+	l.logger.Infof("Notification event received for monitor: %s", hb.MonitorID)
 
-	l.logger.Infof("Notification event received for monitor: %s", monitorID)
+	l.sendToMonitorChannels(context.Background(), hb.MonitorID, hb.Msg, hb)
+}
 
-	// Get monitor-notification records
-	monitorNotifications, err := l.monitorNotificationService.FindByMonitorID(ctx, monitorID)
-	if err != nil {
-		l.logger.Errorf("Failed to get monitor-notification records: %v", err)
+func (l *NotificationEventListener) handleCertificateExpiryEvent(event events.Event) {
+	certEvent, ok := event.Payload.(*certificate.CertificateExpiryEvent)
+	if !ok {
+		l.logger.Errorf("Invalid certificate expiry event payload type: %v", event.Payload)
 		return
 	}
 
-	var notificationChannels []*Model
-	for _, mn := range monitorNotifications {
-		l.logger.Infof("Monitor notification: %s", mn.NotificationID)
-		notification, err := l.service.FindByID(ctx, mn.NotificationID)
-		if err != nil {
-			l.logger.Errorf("Failed to get notification by ID: %s, error: %v", mn.NotificationID, err)
-			continue
-		}
-		if notification != nil {
-			notificationChannels = append(notificationChannels, notification)
-		} else {
-			l.logger.Warnf("Notification not found for monitor-notification: %s", mn.NotificationID)
-		}
-	}
+	l.logger.Infof("Certificate expiry event received for monitor: %s", certEvent.MonitorID)
 
-	// Fetch monitor details for context
-	monitorModel, err := l.monitorSvc.FindByID(ctx, monitorID)
-	if err != nil || monitorModel == nil {
-		l.logger.Warn("Monitor not found for notification context")
+	// No heartbeat is passed since the notification is not about a status change
+	l.sendToMonitorChannels(context.Background(), certEvent.MonitorID, formatCertificateExpiryMessage(certEvent), nil)
+}
+
+func (l *NotificationEventListener) handleDomainExpiryEvent(event events.Event) {
+	domainEvent, ok := event.Payload.(*domain_expiry.DomainExpiryEvent)
+	if !ok {
+		l.logger.Errorf("Invalid domain expiry event payload type: %v", event.Payload)
 		return
 	}
 
-	for _, notificationChannel := range notificationChannels {
-		integration, ok := GetNotificationChannelProvider(notificationChannel.Type)
-		if !ok {
-			l.logger.Warnf("No integration registered for notification type: %s", notificationChannel.Type)
-			continue
-		}
-		if notificationChannel.Config == nil {
-			l.logger.Warnf("No config for notification: %s", notificationChannel.Name)
-			continue
-		}
-
-		// validate config
-		if err := integration.Validate(*notificationChannel.Config); err != nil {
-			l.logger.Errorf("Failed to validate notification config: %s, error: %v", notificationChannel.Name, err)
-			continue
-		}
+	l.logger.Infof("Domain expiry event received for monitor: %s", domainEvent.MonitorID)
 
-		err := integration.Send(ctx, *notificationChannel.Config, hb.Msg, monitorModel, hb)
-		if err != nil {
-			l.logger.Errorf("Failed to send notification: %s, error: %v", notificationChannel.Name, err)
-		} else {
-			l.logger.Infof("Notification sent to: %s for monitor: %s", notificationChannel.Name, monitorID)
-		}
-	}
+	// No heartbeat is passed since the notification is not about a status change
+	l.sendToMonitorChannels(context.Background(), domainEvent.MonitorID, formatDomainExpiryMessage(domainEvent), nil)
 }
 
-func (l *NotificationEventListener) handleCertificateExpiryEvent(event events.Event) {
-	ctx := context.Background()
-
-	certEvent, ok := event.Payload.(*certificate.CertificateExpiryEvent)
+func (l *NotificationEventListener) handleFlappingEvent(event events.Event) {
+	flapEvent, ok := event.Payload.(*monitor_flap.FlappingEvent)
 	if !ok {
-		l.logger.Errorf("Invalid certificate expiry event payload type: %v", event.Payload)
+		l.logger.Errorf("Invalid flapping event payload type: %v", event.Payload)
 		return
 	}
 
-	l.logger.Infof("Certificate expiry event received for monitor: %s", certEvent.MonitorID)
+	l.logger.Infof("Flapping event received for monitor: %s", flapEvent.MonitorID)
+
+	l.sendToMonitorChannels(context.Background(), flapEvent.MonitorID, formatFlappingMessage(flapEvent), flapEvent.Heartbeat)
+}
 
+// sendToMonitorChannels sends message through every notification channel of the monitor,
+// hb is nil when the notification is not about a heartbeat
+func (l *NotificationEventListener) sendToMonitorChannels(ctx context.Context, monitorID string, message string, hb *heartbeat.Model) {
 	// Get monitor-notification records
-	monitorNotifications, err := l.monitorNotificationService.FindByMonitorID(ctx, certEvent.MonitorID)
+	monitorNotifications, err := l.monitorNotificationService.FindByMonitorID(ctx, monitorID)
 	if err != nil {
 		l.logger.Errorf("Failed to get monitor-notification records: %v", err)
 		return
 	}
 
 	if len(monitorNotifications) == 0 {
-		l.logger.Debugf("No notification channels configured for monitor %s", certEvent.MonitorID)
+		l.logger.Debugf("No notification channels configured for monitor %s", monitorID)
+		return
+	}
+
+	// Fetch monitor details for context
+	monitorModel, err := l.monitorSvc.FindByID(ctx, monitorID)
+	if err != nil || monitorModel == nil {
+		l.logger.Warnf("Monitor not found for notification context: %s", monitorID)
 		return
 	}
 
-	// Get notification channels
-	var notificationChannels []*Model
 	for _, mn := range monitorNotifications {
-		l.logger.Infof("Monitor notification: %s", mn.NotificationID)
-		notification, err := l.service.FindByID(ctx, mn.NotificationID)
+		notificationChannel, err := l.service.FindByID(ctx, mn.NotificationID)
 		if err != nil {
 			l.logger.Errorf("Failed to get notification by ID: %s, error: %v", mn.NotificationID, err)
 			continue
 		}
-		if notification != nil {
-			notificationChannels = append(notificationChannels, notification)
-		} else {
+		if notificationChannel == nil {
 			l.logger.Warnf("Notification not found for monitor-notification: %s", mn.NotificationID)
+			continue
 		}
-	}
 
-	// Fetch monitor details for context
-	monitorModel, err := l.monitorSvc.FindByID(ctx, certEvent.MonitorID)
-	if err != nil || monitorModel == nil {
-		l.logger.Warn("Monitor not found for certificate expiry notification context")
-		return
-	}
-
-	// Send notifications through all configured channels
-	for _, notificationChannel := range notificationChannels {
 		integration, ok := GetNotificationChannelProvider(notificationChannel.Type)
 		if !ok {
 			l.logger.Warnf("No integration registered for notification type: %s", notificationChannel.Type)
@@ -200,27 +171,42 @@ func (l *NotificationEventListener) handleCertificateExpiryEvent(event events.Ev
 			continue
 		}
 
-		// Validate config
+		// validate config
 		if err := integration.Validate(*notificationChannel.Config); err != nil {
 			l.logger.Errorf("Failed to validate notification config: %s, error: %v", notificationChannel.Name, err)
 			continue
 		}
 
-		// Create a formatted message for certificate expiry
-		message := l.formatCertificateExpiryMessage(certEvent, monitorModel)
-
-		// Send notification (we pass nil for heartbeat since this is a certificate expiry notification)
-		err := integration.Send(ctx, *notificationChannel.Config, message, monitorModel, nil)
+		err = integration.Send(ctx, *notificationChannel.Config, message, monitorModel, hb)
 		if err != nil {
-			l.logger.Errorf("Failed to send certificate expiry notification: %s, error: %v", notificationChannel.Name, err)
+			l.logger.Errorf("Failed to send notification: %s, error: %v", notificationChannel.Name, err)
 		} else {
-			l.logger.Infof("Certificate expiry notification sent to: %s for monitor: %s", notificationChannel.Name, certEvent.MonitorID)
+			l.logger.Infof("Notification sent to: %s for monitor: %s", notificationChannel.Name, monitorID)
 		}
 	}
 }
 
+// formatFlappingMessage creates the message sent when a monitor starts or stops flapping
+func formatFlappingMessage(flapEvent *monitor_flap.FlappingEvent) string {
+	if flapEvent.Started {
+		return fmt.Sprintf(
+			"🔁 Monitor %s is flapping: %d status changes in the last %d checks. "+
+				"Notifications for individual status changes are paused until it stabilizes.",
+			flapEvent.MonitorName,
+			flapEvent.Transitions,
+			flapEvent.Window,
+		)
+	}
+
+	message := fmt.Sprintf("✅ Monitor %s stopped flapping", flapEvent.MonitorName)
+	if flapEvent.Heartbeat != nil {
+		message += fmt.Sprintf(", current status: %s", providers.HumanReadableStatus(int(flapEvent.Heartbeat.Status)))
+	}
+	return message
+}
+
 // formatCertificateExpiryMessage creates a formatted message for certificate expiry notifications
-func (l *NotificationEventListener) formatCertificateExpiryMessage(certEvent *certificate.CertificateExpiryEvent, monitor *monitor.Model) string {
+func formatCertificateExpiryMessage(certEvent *certificate.CertificateExpiryEvent) string {
 	subjectCN := extractCommonName(certEvent.CertInfo.Subject)
 
 	message := fmt.Sprintf(
@@ -251,6 +237,29 @@ func (l *NotificationEventListener) formatCertificateExpiryMessage(certEvent *ce
 	return message
 }
 
+// formatDomainExpiryMessage creates a formatted message for domain expiry notifications
+func formatDomainExpiryMessage(domainEvent *domain_expiry.DomainExpiryEvent) string {
+	message := fmt.Sprintf(
+		"🚨 Domain Expiry Warning\n\n"+
+			"Monitor: %s\n"+
+			"Domain: %s\n"+
+			"Expires in: %d days\n"+
+			"Expires on: %s\n"+
+			"Notification threshold: %d days",
+		domainEvent.MonitorName,
+		domainEvent.DomainInfo.Domain,
+		domainEvent.DaysRemaining,
+		domainEvent.DomainInfo.ExpiresAt.Format("2006-01-02 15:04:05"),
+		domainEvent.TargetDays,
+	)
+
+	if domainEvent.DomainInfo.Registrar != "" {
+		message += fmt.Sprintf("\nRegistrar: %s", domainEvent.DomainInfo.Registrar)
+	}
+
+	return message
+}
+
 // extractCommonName extracts the common name from a certificate subject string
 func extractCommonName(subject string) string {
 	// Simple extraction - in a real implementation you might want to use proper DN parsing
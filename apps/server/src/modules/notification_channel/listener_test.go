@@ -0,0 +1,84 @@
+package notification_channel
+
+import (
+	"context"
+	"testing"
+
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/monitor"
+	"peekaping/src/modules/monitor_notification"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recordingProvider keeps the notifications it was asked to send
+type recordingProvider struct {
+	NotificationChannelProvider
+	messages   []string
+	heartbeats []*heartbeat.Model
+}
+
+func (r *recordingProvider) Validate(configJSON string) error {
+	return nil
+}
+
+func (r *recordingProvider) Send(ctx context.Context, configJSON, message string, monitor *monitor.Model, heartbeat *heartbeat.Model) error {
+	r.messages = append(r.messages, message)
+	r.heartbeats = append(r.heartbeats, heartbeat)
+	return nil
+}
+
+type fakeChannelService struct {
+	Service
+	channels map[string]*Model
+}
+
+func (f *fakeChannelService) FindByID(ctx context.Context, id string) (*Model, error) {
+	return f.channels[id], nil
+}
+
+type fakeMonitorService struct {
+	monitor.Service
+	monitors map[string]*monitor.Model
+}
+
+func (f *fakeMonitorService) FindByID(ctx context.Context, id string) (*monitor.Model, error) {
+	return f.monitors[id], nil
+}
+
+func TestNotificationEventListener_SendToMonitorChannels(t *testing.T) {
+	provider := &recordingProvider{}
+	RegisterNotificationChannelProvider("test-recorder", provider)
+	t.Cleanup(func() { delete(NotificationChannelProviderRegistry, "test-recorder") })
+
+	config := "{}"
+	monitorNotifications := new(MockMonitorNotificationService)
+	monitorNotifications.On("FindByMonitorID", context.Background(), "m1").Return([]*monitor_notification.Model{
+		{MonitorID: "m1", NotificationID: "n1"},
+		{MonitorID: "m1", NotificationID: "missing"},
+		{MonitorID: "m1", NotificationID: "unconfigured"},
+	}, nil)
+	monitorNotifications.On("FindByMonitorID", context.Background(), "m2").Return([]*monitor_notification.Model{}, nil)
+
+	l := &NotificationEventListener{
+		service: &fakeChannelService{channels: map[string]*Model{
+			"n1":           {ID: "n1", Name: "ops", Type: "test-recorder", Config: &config},
+			"unconfigured": {ID: "unconfigured", Name: "empty", Type: "test-recorder"},
+		}},
+		monitorSvc:                 &fakeMonitorService{monitors: map[string]*monitor.Model{"m1": {ID: "m1", Name: "API"}}},
+		monitorNotificationService: monitorNotifications,
+		logger:                     zap.NewNop().Sugar(),
+	}
+
+	hb := &heartbeat.Model{MonitorID: "m1", Msg: "down"}
+	l.sendToMonitorChannels(context.Background(), "m1", "API is down", hb)
+	require.Len(t, provider.messages, 1)
+	assert.Equal(t, "API is down", provider.messages[0])
+	assert.Same(t, hb, provider.heartbeats[0])
+
+	// Monitors without channels send nothing
+	l.sendToMonitorChannels(context.Background(), "m2", "ignored", nil)
+	assert.Len(t, provider.messages, 1)
+}
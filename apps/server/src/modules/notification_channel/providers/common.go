@@ -53,7 +53,14 @@ func PrepareTemplateBindings(monitor *monitor.Model, heartbeat *heartbeat.Model,
 		heartbeatBytes, _ := json.Marshal(heartbeat)
 		json.Unmarshal(heartbeatBytes, &heartbeatJSON)
 		bindings["heartbeat"] = heartbeatJSON
-		bindings["status"] = humanReadableStatus(int(heartbeat.Status))
+		bindings["status"] = HumanReadableStatus(int(heartbeat.Status))
+
+		// Expose executor metadata at the top level, e.g. {{ metadata.status_code }}
+		metadata := map[string]any{}
+		if heartbeatMetadata, ok := heartbeatJSON["metadata"].(map[string]any); ok {
+			metadata = heartbeatMetadata
+		}
+		bindings["metadata"] = metadata
 	}
 
 	bindings["msg"] = message
@@ -61,7 +68,8 @@ func PrepareTemplateBindings(monitor *monitor.Model, heartbeat *heartbeat.Model,
 	return bindings
 }
 
-func humanReadableStatus(status int) string {
+// HumanReadableStatus returns the upper-case name of a monitor status
+func HumanReadableStatus(status int) string {
 	switch status {
 	case 0:
 		return "DOWN"
@@ -71,6 +79,8 @@ func humanReadableStatus(status int) string {
 		return "PENDING"
 	case 3:
 		return "MAINTENANCE"
+	case 4:
+		return "DEGRADED"
 	default:
 		return fmt.Sprintf("Unknown (%d)", status)
 	}
@@ -0,0 +1,37 @@
+package providers
+
+import (
+	"peekaping/src/modules/heartbeat"
+	"peekaping/src/modules/shared"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrepareTemplateBindings_Metadata(t *testing.T) {
+	hb := &heartbeat.Model{
+		Status:   shared.MonitorStatusDown,
+		Msg:      "HTTP request failed with status: 503",
+		Metadata: shared.HeartBeatMetadata{"status_code": 503},
+	}
+
+	bindings := PrepareTemplateBindings(nil, hb, "down")
+
+	metadata, ok := bindings["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, float64(503), metadata["status_code"])
+	assert.Equal(t, "DOWN", bindings["status"])
+
+	heartbeatJSON, ok := bindings["heartbeat"].(map[string]any)
+	require.True(t, ok)
+	assert.Contains(t, heartbeatJSON, "metadata")
+}
+
+func TestPrepareTemplateBindings_NoMetadata(t *testing.T) {
+	bindings := PrepareTemplateBindings(nil, &heartbeat.Model{Status: shared.MonitorStatusUp}, "up")
+
+	metadata, ok := bindings["metadata"].(map[string]any)
+	require.True(t, ok)
+	assert.Empty(t, metadata)
+}
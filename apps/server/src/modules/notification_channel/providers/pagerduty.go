@@ -126,6 +126,8 @@ func (p *PagerDutySender) getTitle(heartbeat *heartbeat.Model) string {
 		return "Peekaping Monitor ⏳ Pending"
 	case shared.MonitorStatusMaintenance:
 		return "Peekaping Monitor 🔧 Maintenance"
+	case shared.MonitorStatusDegraded:
+		return "Peekaping Monitor ⚠️ Degraded"
 	default:
 		return "Peekaping Alert"
 	}
@@ -139,6 +139,8 @@ func (s *PushbulletSender) buildTitle(m *monitor.Model, heartbeat *heartbeat.Mod
 			status = "PENDING"
 		case 3:
 			status = "MAINTENANCE"
+		case 4:
+			status = "DEGRADED"
 		}
 	}
 
@@ -177,6 +179,8 @@ func (s *PushbulletSender) buildBody(cfg *PushbulletConfig, message string, m *m
 			status = "PENDING"
 		case 3:
 			status = "MAINTENANCE"
+		case 4:
+			status = "DEGRADED"
 		}
 		body += fmt.Sprintf("Status: %s\n", status)
 		
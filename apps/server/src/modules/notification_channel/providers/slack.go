@@ -159,6 +159,8 @@ func (s *SlackSender) getStatusColor(status heartbeat.MonitorStatus) string {
 		return "#daa038"
 	case shared.MonitorStatusMaintenance:
 		return "#808080"
+	case shared.MonitorStatusDegraded:
+		return "#dfb317"
 	default:
 		return "#808080"
 	}
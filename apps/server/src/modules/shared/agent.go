@@ -0,0 +1,28 @@
+package shared
+
+import "time"
+
+// AgentTokenHeader carries the token remote probe agents authenticate with
+const AgentTokenHeader = "X-Agent-Token"
+
+// AgentMonitor is a monitor assigned to a remote probe agent together with the proxy it uses
+type AgentMonitor struct {
+	Monitor *Monitor `json:"monitor"`
+	Proxy   *Proxy   `json:"proxy,omitempty"`
+}
+
+// AgentResult is a single check result pushed back by a remote probe agent
+type AgentResult struct {
+	MonitorID  string            `json:"monitor_id" validate:"required"`
+	Status     MonitorStatus     `json:"status"`
+	Message    string            `json:"message"`
+	StartTime  time.Time         `json:"start_time" validate:"required"`
+	EndTime    time.Time         `json:"end_time" validate:"required"`
+	TLSInfo    *TLSInfo          `json:"tls_info,omitempty"`
+	DomainInfo *DomainInfo       `json:"domain_info,omitempty"`
+	Metadata   HeartBeatMetadata `json:"metadata,omitempty"`
+}
+
+type AgentResultsDto struct {
+	Results []*AgentResult `json:"results" validate:"required,dive"`
+}
@@ -0,0 +1,15 @@
+package shared
+
+import (
+	"time"
+)
+
+// DomainInfo represents the registration data of a domain
+type DomainInfo struct {
+	Domain        string    `json:"domain"`
+	Registrar     string    `json:"registrar,omitempty"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+	DaysRemaining int       `json:"daysRemaining"`
+	Status        []string  `json:"status,omitempty"` // EPP status codes such as "client transfer prohibited"
+	Source        string    `json:"source"`           // "rdap" or "whois"
+}
@@ -11,21 +11,29 @@ const (
 	MonitorStatusUp
 	MonitorStatusPending
 	MonitorStatusMaintenance
+	// MonitorStatusDegraded marks a check that succeeded slower than the monitor's latency threshold
+	MonitorStatusDegraded
 )
 
+// HeartBeatMetadata holds structured details of a check reported by the executor, such as the
+// HTTP status code or the resolved DNS records. Values must be JSON serializable.
+type HeartBeatMetadata map[string]any
+
 type HeartBeatModel struct {
-	ID        string        `json:"id"`
-	MonitorID string        `json:"monitor_id"`
-	Status    MonitorStatus `json:"status"`
-	Msg       string        `json:"msg"`
-	Ping      int           `json:"ping"`
-	Duration  int           `json:"duration"`
-	DownCount int           `json:"down_count"`
-	Retries   int           `json:"retries"`
-	Important bool          `json:"important"`
-	Time      time.Time     `json:"time"`
-	EndTime   time.Time     `json:"end_time"`
-	Notified  bool          `json:"notified"`
+	ID        string            `json:"id"`
+	MonitorID string            `json:"monitor_id"`
+	Status    MonitorStatus     `json:"status"`
+	Msg       string            `json:"msg"`
+	Ping      int               `json:"ping"`
+	Duration  int               `json:"duration"`
+	DownCount int               `json:"down_count"`
+	Retries   int               `json:"retries"`
+	Important bool              `json:"important"`
+	Time      time.Time         `json:"time"`
+	EndTime   time.Time         `json:"end_time"`
+	Notified  bool              `json:"notified"`
+	Location  string            `json:"location"`
+	Metadata  HeartBeatMetadata `json:"metadata,omitempty"`
 }
 
 type HeartBeatChartPoint struct {
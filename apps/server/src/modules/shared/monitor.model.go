@@ -31,6 +31,16 @@ type Monitor struct {
 	// Resend Notification if Down X times consecutively
 	ResendInterval int `json:"resend_interval" example:"10"`
 
+	// Response time in milliseconds above which a successful check is DEGRADED, 0 disables it
+	DegradedThreshold int `json:"degraded_threshold" example:"2000"`
+
+	// Consecutive slow checks required before the monitor is marked as DEGRADED
+	DegradedBreaches int `json:"degraded_breaches" example:"3"`
+	// Percentage of status changes within the window at which the monitor is flapping, 0 disables it
+	FlapThreshold int `json:"flap_threshold" example:"30"`
+	// Number of recent heartbeats the status change rate is computed over, 0 uses the default
+	FlapWindow int `json:"flap_window" example:"20"`
+
 	Active bool          `json:"active"`
 	Status MonitorStatus `json:"status"`
 
@@ -43,19 +53,23 @@ type Monitor struct {
 }
 
 type UpdateMonitor struct {
-	ID             *string        `json:"id"`
-	Type           *string        `json:"type"`
-	Name           *string        `json:"name"`
-	Interval       *int           `json:"interval"`
-	Timeout        *int           `json:"timeout"`
-	MaxRetries     *int           `json:"max_retries"`
-	RetryInterval  *int           `json:"retry_interval"`
-	ResendInterval *int           `json:"resend_interval"`
-	Active         *bool          `json:"active"`
-	Status         *MonitorStatus `json:"status"`
-	Config         *string        `json:"config"`
-	ProxyId        *string        `json:"proxy_id"`
-	PushToken      *string        `json:"push_token"`
+	ID                *string        `json:"id"`
+	Type              *string        `json:"type"`
+	Name              *string        `json:"name"`
+	Interval          *int           `json:"interval"`
+	Timeout           *int           `json:"timeout"`
+	MaxRetries        *int           `json:"max_retries"`
+	RetryInterval     *int           `json:"retry_interval"`
+	ResendInterval    *int           `json:"resend_interval"`
+	DegradedThreshold *int           `json:"degraded_threshold"`
+	DegradedBreaches  *int           `json:"degraded_breaches"`
+	FlapThreshold     *int           `json:"flap_threshold"`
+	FlapWindow        *int           `json:"flap_window"`
+	Active            *bool          `json:"active"`
+	Status            *MonitorStatus `json:"status"`
+	Config            *string        `json:"config"`
+	ProxyId           *string        `json:"proxy_id"`
+	PushToken         *string        `json:"push_token"`
 
 	CreatedAt *time.Time `json:"created_at"`
 	UpdatedAt *time.Time `json:"updated_at"`
@@ -22,4 +22,5 @@ type Stat struct {
 	Up          int       `json:"up"`
 	Down        int       `json:"down"`
 	Maintenance int       `json:"maintenance"`
+	Degraded    int       `json:"degraded"`
 }
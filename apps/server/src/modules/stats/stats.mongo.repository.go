@@ -45,6 +45,7 @@ type mongoModel struct {
 	Up          int                `bson:"up"`
 	Down        int                `bson:"down"`
 	Maintenance int                `bson:"maintenance"`
+	Degraded    int                `bson:"degraded"`
 }
 
 func toDomainModel(mm *mongoModel) *Stat {
@@ -58,6 +59,7 @@ func toDomainModel(mm *mongoModel) *Stat {
 		Up:          mm.Up,
 		Down:        mm.Down,
 		Maintenance: mm.Maintenance,
+		Degraded:    mm.Degraded,
 	}
 }
 
@@ -116,6 +118,7 @@ func (r *MongoRepository) UpsertStat(ctx context.Context, stat *Stat, period Sta
 		Up:          stat.Up,
 		Down:        stat.Down,
 		Maintenance: stat.Maintenance,
+		Degraded:    stat.Degraded,
 	}
 
 	filter := bson.M{"monitor_id": mm.MonitorID, "timestamp": mm.Timestamp}
@@ -128,6 +131,7 @@ func (r *MongoRepository) UpsertStat(ctx context.Context, stat *Stat, period Sta
 				"up":          mm.Up,
 				"down":        mm.Down,
 				"maintenance": mm.Maintenance,
+				"degraded":    mm.Degraded,
 			},
 		}
 	_, err = coll.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
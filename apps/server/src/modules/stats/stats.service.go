@@ -37,7 +37,7 @@ func NewService(repo Repository, logger *zap.SugaredLogger) Service {
 
 func (s *ServiceImpl) flatStatus(status int) int {
 	switch status {
-	case 1, 3: // MonitorStatusUp, MonitorStatusMaintenance
+	case 1, 3, 4: // MonitorStatusUp, MonitorStatusMaintenance, MonitorStatusDegraded
 		return 1 // MonitorStatusUp
 	case 0, 2: // MonitorStatusDown, MonitorStatusPending
 		return 0 // MonitorStatusDown
@@ -69,8 +69,8 @@ func (s *ServiceImpl) AggregateHeartbeat(ctx context.Context, hb *HeartbeatPaylo
 		// Up/Down logic (flattened)
 		if s.flatStatus(hb.Status) == 1 { // MonitorStatusUp
 			statToUpsert.Up = stat.Up + 1
-			// Only update ping stats for true UP
-			if hb.Status == 1 { // MonitorStatusUp
+			// Only update ping stats for checks that got a response
+			if hb.Status == 1 || hb.Status == 4 { // MonitorStatusUp, MonitorStatusDegraded
 				fPing := float64(hb.Ping)
 				if stat.Up == 0 {
 					statToUpsert.PingMin = fPing
@@ -103,6 +103,11 @@ func (s *ServiceImpl) AggregateHeartbeat(ctx context.Context, hb *HeartbeatPaylo
 			statToUpsert.Maintenance = stat.Maintenance + 1
 		}
 
+		// Degraded beats count as up and are also tracked on their own
+		if hb.Status == 4 { // MonitorStatusDegraded
+			statToUpsert.Degraded = stat.Degraded + 1
+		}
+
 		// Upsert stat
 		if err := s.repo.UpsertStat(ctx, &statToUpsert, p.Period); err != nil {
 			return err
@@ -183,6 +188,7 @@ func (s *ServiceImpl) FindStatsByMonitorIDAndTimeRangeWithInterval(ctx context.C
 				Up:          0,
 				Down:        0,
 				Maintenance: 0,
+				Degraded:    0,
 			})
 		}
 	}
@@ -241,6 +247,7 @@ func (s *ServiceImpl) groupStatsByInterval(minuteStats []*Stat, since, until tim
 				Up:          0,
 				Down:        0,
 				Maintenance: 0,
+				Degraded:    0,
 			})
 		}
 	}
@@ -261,11 +268,12 @@ func (s *ServiceImpl) aggregateStats(stats []*Stat, timestamp time.Time, monitor
 			Up:          0,
 			Down:        0,
 			Maintenance: 0,
+			Degraded:    0,
 		}
 	}
 
 	var totalPing, minPing, maxPing float64
-	var totalUp, totalDown, totalMaintenance int
+	var totalUp, totalDown, totalMaintenance, totalDegraded int
 	var pingCount int
 	var hasValidPing bool
 
@@ -273,6 +281,7 @@ func (s *ServiceImpl) aggregateStats(stats []*Stat, timestamp time.Time, monitor
 		totalUp += stat.Up
 		totalDown += stat.Down
 		totalMaintenance += stat.Maintenance
+		totalDegraded += stat.Degraded
 
 		// Only include stats with valid ping values (> 0) for ping calculations
 		if stat.Up > 0 && stat.Ping > 0 {
@@ -316,6 +325,7 @@ func (s *ServiceImpl) aggregateStats(stats []*Stat, timestamp time.Time, monitor
 		Up:          totalUp,
 		Down:        totalDown,
 		Maintenance: totalMaintenance,
+		Degraded:    totalDegraded,
 	}
 }
 
@@ -326,6 +336,7 @@ type Stats struct {
 	AvgPing     *float64 `json:"avgPing"`
 	Uptime      *float64 `json:"uptime"`
 	Maintenance *float64 `json:"maintenance"`
+	Degraded    *float64 `json:"degraded"`
 }
 
 // StatPointsSummary computes stat points and summary for a period using flatStatus logic
@@ -334,7 +345,7 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 	var minPing *float64
 	var sumPing float64
 	var upCount int
-	var totalUp, totalDown, totalMaintenance int
+	var totalUp, totalDown, totalMaintenance, totalDegraded int
 
 	for _, s := range statsList {
 		if s.Up > 0 {
@@ -352,6 +363,7 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 		totalUp += s.Up
 		totalDown += s.Down
 		totalMaintenance += s.Maintenance
+		totalDegraded += s.Degraded
 	}
 
 	var avgPing *float64
@@ -362,6 +374,7 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 
 	var uptime *float64
 	var maintenance *float64
+	var degraded *float64
 	total := totalUp + totalDown + totalMaintenance
 	if total > 0 {
 		uptimeV := float64(totalUp) / float64(total) * 100
@@ -369,6 +382,9 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 
 		maintenanceV := float64(totalMaintenance) / float64(total) * 100
 		maintenance = &maintenanceV
+
+		degradedV := float64(totalDegraded) / float64(total) * 100
+		degraded = &degradedV
 	}
 
 	return &Stats{
@@ -377,6 +393,7 @@ func (s *ServiceImpl) StatPointsSummary(statsList []*Stat) *Stats {
 		AvgPing:     avgPing,
 		Uptime:      uptime,
 		Maintenance: maintenance,
+		Degraded:    degraded,
 	}
 }
 
@@ -20,6 +20,7 @@ type sqlModel struct {
 	Up          int       `bun:"up,notnull,default:0"`
 	Down        int       `bun:"down,notnull,default:0"`
 	Maintenance int       `bun:"maintenance,notnull,default:0"`
+	Degraded    int       `bun:"degraded,notnull,default:0"`
 	CreatedAt   time.Time `bun:"created_at,nullzero,notnull,default:current_timestamp"`
 	UpdatedAt   time.Time `bun:"updated_at,nullzero,notnull,default:current_timestamp"`
 }
@@ -35,6 +36,7 @@ func toDomainModelFromSQL(sm *sqlModel) *Stat {
 		Up:          sm.Up,
 		Down:        sm.Down,
 		Maintenance: sm.Maintenance,
+		Degraded:    sm.Degraded,
 	}
 }
 
@@ -49,6 +51,7 @@ func toSQLModel(s *Stat) *sqlModel {
 		Up:          s.Up,
 		Down:        s.Down,
 		Maintenance: s.Maintenance,
+		Degraded:    s.Degraded,
 	}
 }
 
@@ -81,6 +84,7 @@ func (r *SQLRepositoryImpl) GetOrCreateStat(ctx context.Context, monitorID strin
 			Up:          0,
 			Down:        0,
 			Maintenance: 0,
+			Degraded:    0,
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
@@ -110,6 +114,7 @@ func (r *SQLRepositoryImpl) UpsertStat(ctx context.Context, stat *Stat, period S
 		Set("up = ?", sm.Up).
 		Set("down = ?", sm.Down).
 		Set("maintenance = ?", sm.Maintenance).
+		Set("degraded = ?", sm.Degraded).
 		Set("updated_at = ?", sm.UpdatedAt).
 		Exec(ctx)
 
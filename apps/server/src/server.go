@@ -3,9 +3,11 @@ package main
 import (
 	"net/http"
 	"peekaping/src/config"
+	"peekaping/src/modules/agent"
 	"peekaping/src/modules/auth"
 	"peekaping/src/modules/badge"
 	"peekaping/src/modules/healthcheck"
+	"peekaping/src/modules/healthcheck/executor"
 	"peekaping/src/modules/heartbeat"
 	"peekaping/src/modules/maintenance"
 	"peekaping/src/modules/monitor"
@@ -64,11 +66,13 @@ func ProvideServer(
 	notificationChannelController *notification_channel.Controller,
 	proxyRoute *proxy.Route,
 	proxyController *proxy.Controller,
+	proxyService proxy.Service,
 	settingRoute *setting.Route,
 	settingController *setting.Controller,
 	heartbeatService heartbeat.Service,
 	monitorService monitor.Service,
 	healthcheckSupervisor *healthcheck.HealthCheckSupervisor,
+	execRegistry *executor.ExecutorRegistry,
 	maintenanceRoute *maintenance.Route,
 	maintenanceController *maintenance.Controller,
 	statusPageRoute *status_page.Route,
@@ -77,6 +81,10 @@ func ProvideServer(
 	tagController *tag.Controller,
 	badgeRoute *badge.Route,
 	badgeController *badge.Controller,
+	agentRoute *agent.Route,
+	agentController *agent.Controller,
+	agentService agent.Service,
+	authMiddleware *auth.MiddlewareProvider,
 ) *Server {
 	server := gin.Default()
 	// server := gin.New()
@@ -109,10 +117,23 @@ func ProvideServer(
 	statusPageRoute.ConnectRoute(router, statusPageController)
 	tagRoute.ConnectRoute(router, tagController)
 	badgeRoute.ConnectRoute(router, badgeController)
+	agentRoute.ConnectRoute(router, agentController)
 
 	// Register push endpoint
 	healthcheck.RegisterPushEndpoint(router, monitorService, heartbeatService, healthcheckSupervisor, logger)
 
+	// Register remote probe agent endpoints
+	healthcheck.RegisterAgentEndpoints(router, agentService, monitorService, proxyService, healthcheckSupervisor, logger)
+
+	// Register on-demand check and dry-run endpoints
+	healthcheck.RegisterCheckEndpoints(router, authMiddleware, monitorService, healthcheckSupervisor, logger)
+
+	// Register execution pool metrics endpoint
+	healthcheck.RegisterMetricsEndpoint(router, authMiddleware, healthcheckSupervisor)
+
+	// Register the plugin monitor types endpoint
+	healthcheck.RegisterPluginsEndpoint(router, authMiddleware, execRegistry)
+
 	// Swagger routes
 	url := ginSwagger.URL("/swagger/doc.json")
 	server.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler, url))
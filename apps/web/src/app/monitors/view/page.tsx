@@ -540,13 +540,15 @@ const MonitorPage = () => {
                     lastHeartbeat?.status === 1 && "text-green-400",
                     lastHeartbeat?.status === 0 && "text-red-400",
                     lastHeartbeat?.status === 2 && "text-red-400",
-                    lastHeartbeat?.status === 3 && "text-blue-400"
+                    lastHeartbeat?.status === 3 && "text-blue-400",
+                    lastHeartbeat?.status === 4 && "text-yellow-400"
                   )}
                 >
                   {lastHeartbeat?.status === 1 && t("monitors.view.status.up")}
                   {lastHeartbeat?.status === 0 && t("monitors.view.status.down")}
                   {lastHeartbeat?.status === 2 && t("monitors.view.status.down")}
                   {lastHeartbeat?.status === 3 && t("monitors.view.status.maintenance")}
+                  {lastHeartbeat?.status === 4 && t("monitors.view.status.degraded")}
                 </div>
               ) : (
                 <div className="font-semibold text-2xl">{t("monitors.view.status.paused")}</div>
@@ -11,6 +11,7 @@ import { Skeleton } from "@/components/ui/skeleton";
 import { Alert, AlertDescription } from "@/components/ui/alert";
 import {
   AlertCircle,
+  AlertTriangle,
   CheckCircle,
   XCircle,
   Clock,
@@ -178,6 +179,8 @@ const PublicStatusPage = ({ incomingSlug }: { incomingSlug?: string }) => {
         return <XCircle className="h-5 w-5 text-red-500" />;
       case 3: // Maintenance
         return <Clock className="h-5 w-5 text-blue-500" />;
+      case 4: // Degraded
+        return <AlertTriangle className="h-5 w-5 text-yellow-500" />;
       default:
         return <Activity className="h-5 w-5 text-gray-500" />;
     }
@@ -192,6 +195,8 @@ const PublicStatusPage = ({ incomingSlug }: { incomingSlug?: string }) => {
         return t("status.messages.down");
       case 3:
         return t("status.messages.maintenance");
+      case 4:
+        return t("status.messages.degraded");
       default:
         return t("status.messages.unknown");
     }
@@ -207,6 +212,12 @@ const PublicStatusPage = ({ incomingSlug }: { incomingSlug?: string }) => {
         return lastHeartbeat?.status === 0 || lastHeartbeat?.status === 2;
       }
     );
+    const hasDegraded = monitors.some(
+      (m: StatusPageMonitorWithHeartbeatsAndUptimeDto) => {
+        const lastHeartbeat = last(m.heartbeats || []);
+        return lastHeartbeat?.status === 4;
+      }
+    );
     const hasMaintenance = monitors.some(
       (m: StatusPageMonitorWithHeartbeatsAndUptimeDto) => {
         const lastHeartbeat = last(m.heartbeats || []);
@@ -215,6 +226,7 @@ const PublicStatusPage = ({ incomingSlug }: { incomingSlug?: string }) => {
     );
 
     if (hasDown) return { status: 0, text: t("status.messages.partial_system_outage") };
+    if (hasDegraded) return { status: 4, text: t("status.messages.degraded_performance") };
     if (hasMaintenance) return { status: 3, text: t("status.messages.under_maintenance") };
     return { status: 1, text: t("status.messages.all_systems_operational") };
   };
@@ -317,7 +329,8 @@ const PublicStatusPage = ({ incomingSlug }: { incomingSlug?: string }) => {
                                   : lastHeartbeatStatus === 0 ||
                                     lastHeartbeatStatus === 2
                                   ? "destructive"
-                                  : lastHeartbeatStatus === 3
+                                  : lastHeartbeatStatus === 3 ||
+                                    lastHeartbeatStatus === 4
                                   ? "secondary"
                                   : "outline"
                               }
@@ -15,6 +15,8 @@ export const useStatusHelpers = () => {
         return t('common.unknown');
       case 3:
         return t('common.maintenance');
+      case 4:
+        return t('common.degraded');
       default:
         return t('common.unknown');
     }
@@ -30,6 +32,8 @@ export const useStatusHelpers = () => {
         return "bg-gray-500 border-gray-600";
       case 3:
         return "bg-blue-500 border-blue-600";
+      case 4:
+        return "bg-yellow-500 border-yellow-600";
       default:
         return "bg-gray-500 border-gray-600";
     }
@@ -44,6 +48,7 @@ export const useStatusHelpers = () => {
       case 2:
         return "outline";
       case 3:
+      case 4:
         return "secondary";
       default:
         return "outline";